@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"polytracker/internal/alerts"
+	"polytracker/internal/db"
+	"polytracker/internal/notify"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertsUnread bool
+	alertsLimit  int
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "List alerts fired for watchlisted traders",
+	Long: `List alerts raised by the rules under alerts.rules. Rules are evaluated after
+every scan against watchlisted traders:
+
+  large_position  a buy larger than threshold dollars
+  new_market      the first trade in a market the trader has not traded before
+  pnl_below       the trader's P&L is below threshold dollars
+  rank_change     the trader moved at least threshold places on the P&L leaderboard
+
+Newly fired alerts are also posted to every webhook under notifications.sinks
+(generic JSON, slack, discord or telegram format).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		list, err := database.ListAlerts(alertsLimit, alertsUnread)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			cmd.Println("No alerts.")
+			return nil
+		}
+		for _, a := range list {
+			marker := " "
+			if !a.Read {
+				marker = "*"
+			}
+			cmd.Printf("%s %s  %-15s %s\n", marker, a.CreatedAt.Format("2006-01-02 15:04"), a.Rule, a.Message)
+		}
+		return nil
+	},
+}
+
+var alertsReadCmd = &cobra.Command{
+	Use:   "read",
+	Short: "Mark all alerts as read",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		if err := database.MarkAlertsRead(); err != nil {
+			return err
+		}
+		cmd.Println("All alerts marked as read.")
+		return nil
+	},
+}
+
+var alertsTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test alert to every configured notification sink",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dispatcher, err := newDispatcher()
+		if err != nil {
+			return err
+		}
+		if dispatcher.Len() == 0 {
+			return fmt.Errorf("no notification sinks configured under notifications.sinks")
+		}
+		alert := db.Alert{
+			Rule:      "test",
+			Message:   "polytracker test notification",
+			CreatedAt: time.Now(),
+		}
+		if err := dispatcher.Dispatch(context.Background(), []db.Alert{alert}); err != nil {
+			return err
+		}
+		cmd.Printf("Test alert sent to %d sink(s).\n", dispatcher.Len())
+		return nil
+	},
+}
+
+// newAlertEngine builds the engine from the configured rules.
+func newAlertEngine(database *db.DB) (*alerts.Engine, error) {
+	rules := make([]alerts.Rule, len(cfg.Alerts.Rules))
+	for i, r := range cfg.Alerts.Rules {
+		rules[i] = alerts.Rule{Type: r.Type, Threshold: r.Threshold}
+	}
+	if err := alerts.ValidateRules(rules); err != nil {
+		return nil, err
+	}
+	return alerts.NewEngine(database, rules), nil
+}
+
+// evaluateAlerts runs the alert rules, prints newly fired alerts to out and
+// posts them to the configured notification sinks.
+func evaluateAlerts(ctx context.Context, database *db.DB, out io.Writer) error {
+	engine, err := newAlertEngine(database)
+	if err != nil {
+		return err
+	}
+	fired, err := engine.Evaluate()
+	if err != nil {
+		return fmt.Errorf("alert evaluation failed: %w", err)
+	}
+	if len(fired) == 0 {
+		return nil
+	}
+	for _, a := range fired {
+		fmt.Fprintf(out, "ALERT [%s] %s\n", a.Rule, a.Message)
+	}
+
+	dispatcher, err := newDispatcher()
+	if err != nil {
+		return err
+	}
+	if err := dispatcher.Dispatch(ctx, fired); err != nil {
+		log.Printf("Warning: failed to deliver some notifications: %v", err)
+	}
+	return nil
+}
+
+// newDispatcher builds webhook sinks from notifications.sinks.
+func newDispatcher() (*notify.Dispatcher, error) {
+	var sinks []notify.Notifier
+	for _, c := range cfg.Notifications.Sinks {
+		sink, err := notify.NewWebhookSink(notify.SinkConfig{
+			Name:          c.Name,
+			Format:        c.Format,
+			URL:           c.URL,
+			ChatID:        c.ChatID,
+			Template:      c.Template,
+			RatePerMinute: c.RatePerMinute,
+			Retries:       c.Retries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return notify.NewDispatcher(sinks...), nil
+}
+
+func init() {
+	alertsCmd.Flags().BoolVar(&alertsUnread, "unread", false, "Only show unread alerts")
+	alertsCmd.Flags().IntVar(&alertsLimit, "limit", 50, "Maximum number of alerts to show")
+	alertsCmd.AddCommand(alertsReadCmd, alertsTestCmd)
+	rootCmd.AddCommand(alertsCmd)
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"polytracker/internal/analytics"
 	"polytracker/internal/claude"
 	"polytracker/internal/db"
 	"polytracker/internal/polymarket"
@@ -11,21 +16,80 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var skipFetch bool
+var (
+	skipFetch       bool
+	analyzeModel    string
+	analyzeTemplate string
+	analyzeChat     bool
+	analyzeResume   bool
+
+	analyzeTop           int
+	analyzeWatchlist     bool
+	analyzeResumeBatches bool
+	analyzeNoWait        bool
+	analyzePollInterval  time.Duration
+)
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze [address]",
 	Short: "Analyze a specific trader's strategy using Claude AI",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		address := args[0]
+	Long: `Fetch a trader's history and ask Claude for a trading thesis.
+
+The prompt is rendered from a Go text/template. Put custom templates such as
+sports.tmpl or risk-review.tmpl in claude.prompt_dir and select one with
+--template sports. Templates receive .Trader, .Profile (nil without a behavioral
+profile), .Positions, .Categories and .Hours aggregated over the full history,
+and .Trades, a sample of the most recent, largest, biggest winning and losing
+trades, plus the functions pct, datetime and inc. A default.tmpl there replaces
+the built-in prompt. Saved analyses record the template name and a hash of its
+source.
+
+Positions and sampled trades are cut to keep the prompt within
+claude.context_budget tokens; the estimate is printed before the call.
+
+With --chat, ask follow-up questions about the thesis once it is written.
+Questions and replies are saved with the analysis; --resume continues the
+conversation on the trader's latest analysis instead of writing a new one.
 
+With --top N or --watchlist, analyze many traders at once through the Message
+Batches API, at half the usual price. The command waits for the batch and saves
+an analysis per trader; batches usually finish within an hour but may take up
+to a day. A batch interrupted or submitted with --no-wait is recorded, and
+--resume-batches collects its results later.
+
+Examples:
+  polytracker analyze 0x1234... --chat
+  polytracker analyze --top 50 --skip-fetch
+  polytracker analyze --watchlist --no-wait
+  polytracker analyze --resume-batches`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if analyzeTop > 0 || analyzeWatchlist || analyzeResumeBatches {
+			if analyzeTop > 0 && analyzeWatchlist {
+				return errors.New("--top and --watchlist cannot be used together")
+			}
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := db.NewDB(cfg.Database.Path)
 		if err != nil {
 			return fmt.Errorf("failed to initialize database: %w", err)
 		}
 		defer database.Close()
 
+		if analyzeResumeBatches {
+			return resumeBatches(cmd, database)
+		}
+		if analyzeTop > 0 || analyzeWatchlist {
+			return analyzeBatch(cmd, database)
+		}
+
+		address := args[0]
+		if analyzeResume {
+			return resumeChat(cmd, database, address)
+		}
+
 		// Fetch data unless --skip-fetch is set
 		if !skipFetch {
 			cmd.Printf("Fetching history for trader: %s\n", address)
@@ -53,23 +117,18 @@ var analyzeCmd = &cobra.Command{
 			return fmt.Errorf("trader not found: %s", address)
 		}
 
-		// Get trades
-		trades, err := database.GetTradesByTrader(address)
+		// Get trades, their markets and latest prices
+		data, err := claude.LoadTraderData(database, trader)
 		if err != nil {
-			return fmt.Errorf("failed to get trades: %w", err)
-		}
-
-		// Get markets for trades
-		markets := make(map[string]*db.Market)
-		for _, trade := range trades {
-			if _, exists := markets[trade.MarketID]; !exists {
-				market, err := database.GetMarket(trade.MarketID)
-				if err != nil {
-					cmd.Printf("Warning: failed to get market %s: %v\n", trade.MarketID, err)
-					continue
-				}
-				markets[trade.MarketID] = market
-			}
+			return err
+		}
+
+		// Refresh the behavioral profile for prompt context
+		profile, err := analytics.NewProfiler(database).ProfileTrader(address)
+		if err != nil {
+			cmd.Printf("Warning: failed to compute behavioral profile: %v\n", err)
+		} else {
+			data.Profile = profile
 		}
 
 		// Check if Claude API key is configured
@@ -80,22 +139,26 @@ var analyzeCmd = &cobra.Command{
 		}
 
 		// Initialize Claude client
-		claudeClient, err := claude.NewClient(claude.Config{
-			APIKey:   cfg.Claude.APIKey,
-			Endpoint: cfg.Claude.Endpoint,
-		})
+		claudeClient, err := newClaudeClient(analyzeModel, analyzeTemplate)
 		if err != nil {
-			return fmt.Errorf("failed to initialize Claude client: %w", err)
+			return err
 		}
 
-		cmd.Printf("\nAnalyzing trader with Claude AI...\n")
+		prompt, err := claudeClient.BuildPrompt(data)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Prompt: ~%d tokens of %d budget | %d of %d trades sampled | %d of %d markets\n",
+			prompt.EstimatedTokens, prompt.Budget, prompt.SampledTrades, prompt.TotalTrades, prompt.ShownMarkets, prompt.TotalMarkets)
+		if prompt.EstimatedTokens > prompt.Budget {
+			cmd.Println("Warning: prompt exceeds claude.context_budget even without sampled trades")
+		}
+
+		tmpl := claudeClient.Template()
+		cmd.Printf("\nAnalyzing trader with Claude AI (%s, template %s@%s)...\n", claudeClient.Model(), tmpl.Name, tmpl.Hash)
 
 		// Perform analysis
-		result, err := claudeClient.AnalyzeTrader(context.Background(), claude.TraderData{
-			Trader:  trader,
-			Trades:  trades,
-			Markets: markets,
-		})
+		result, err := claudeClient.AnalyzeTrader(context.Background(), data)
 
 		if err != nil {
 			if errors.Is(err, claude.ErrTokenLimit) {
@@ -108,24 +171,175 @@ var analyzeCmd = &cobra.Command{
 		// Display results
 		cmd.Printf("\n%s\n", result.Thesis)
 		cmd.Printf("\n---\n")
-		cmd.Printf("Model: %s | Tokens: %d in / %d out\n", result.Model, result.InputTokens, result.OutputTokens)
+		cmd.Printf("Model: %s | Tokens: %d in / %d out | Cost: $%.4f\n", result.Model, result.InputTokens, result.OutputTokens, result.CostUSD)
+		if s := result.Summary; s != nil {
+			cmd.Printf("Archetype: %s | Risk: %d/10 | Timing: %s | Edge: %s | Copyability: %s\n",
+				s.Archetype, s.RiskScore, s.TimingStyle, s.EdgeConfidence, s.Copyability)
+			cmd.Printf("Market focus: %s\n", strings.Join(s.MarketFocus, ", "))
+			for _, risk := range s.KeyRisks {
+				cmd.Printf("  - %s\n", risk)
+			}
+		} else if result.SummaryErr != nil {
+			cmd.Printf("Warning: no structured summary: %v\n", result.SummaryErr)
+		}
 
 		// Save analysis to database
-		analysis := &db.Analysis{
-			TraderID:  address,
-			Thesis:    result.Thesis,
-			CreatedAt: result.CreatedAt,
-		}
+		analysis := result.Analysis(address)
 		if err := database.SaveAnalysis(analysis); err != nil {
 			cmd.Printf("Warning: failed to save analysis: %v\n", err)
+			return nil
 		}
 
+		if analyzeChat {
+			return chatLoop(cmd, database, claudeClient, analysis, nil)
+		}
 		return nil
 	},
 }
 
+// resumeChat continues the follow-up conversation on a trader's latest analysis.
+func resumeChat(cmd *cobra.Command, database *db.DB, address string) error {
+	analysis, err := database.GetAnalysisByTrader(address)
+	if err != nil {
+		return err
+	}
+	if analysis == nil {
+		return fmt.Errorf("no saved analysis for trader: %s", address)
+	}
+	messages, err := database.GetAnalysisMessages(analysis.ID)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Claude.APIKey == "" {
+		cmd.Println("Claude API key not configured. Cannot resume the conversation.")
+		cmd.Println("Set POLYTRACKER_CLAUDE_API_KEY or add claude.api_key to config.yaml")
+		return nil
+	}
+	claudeClient, err := newClaudeClient(analyzeModel, analyzeTemplate)
+	if err != nil {
+		return err
+	}
+
+	// Analyses saved before prompts were stored are replayed against the
+	// trader's current data.
+	if analysis.Prompt == "" {
+		trader, err := database.GetTrader(address)
+		if err != nil {
+			return fmt.Errorf("failed to get trader: %w", err)
+		}
+		data, err := claude.LoadTraderData(database, trader)
+		if err != nil {
+			return err
+		}
+		prompt, err := claudeClient.BuildPrompt(data)
+		if err != nil {
+			return err
+		}
+		analysis.Prompt = prompt.Text
+		cmd.Println("Note: this analysis was saved without its prompt; using the trader's current data instead.")
+	}
+
+	cmd.Printf("Resuming analysis #%d from %s (%s)\n", analysis.ID, analysis.CreatedAt.Format("2006-01-02 15:04"), analysis.Model)
+	cmd.Printf("\n%s\n", analysis.Thesis)
+	for _, m := range messages {
+		if m.Role == db.MessageRoleUser {
+			cmd.Printf("\n> %s\n", m.Content)
+		} else {
+			cmd.Printf("\n%s\n", m.Content)
+		}
+	}
+	return chatLoop(cmd, database, claudeClient, analysis, messages)
+}
+
+// chatLoop reads follow-up questions until end of input or "exit", streaming
+// each reply and saving every question and reply with the analysis.
+func chatLoop(cmd *cobra.Command, database *db.DB, claudeClient *claude.Client, analysis *db.Analysis, messages []db.AnalysisMessage) error {
+	cmd.Println("\nAsk follow-up questions about this analysis. Type 'exit' or press Ctrl-D to finish.")
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for {
+		cmd.Print("\n> ")
+		if !scanner.Scan() {
+			cmd.Println()
+			return scanner.Err()
+		}
+		question := strings.TrimSpace(scanner.Text())
+		if question == "" {
+			continue
+		}
+		if question == "exit" || question == "quit" {
+			return nil
+		}
+
+		conv := claude.Conversation{Prompt: analysis.Prompt, Thesis: analysis.Thesis, Messages: messages}
+		var result *claude.AnalysisResult
+		var err error
+		for ev := range claudeClient.StreamChat(context.Background(), conv, question) {
+			cmd.Print(ev.Text)
+			if ev.Result != nil || ev.Err != nil {
+				result, err = ev.Result, ev.Err
+			}
+		}
+		cmd.Println()
+		if result == nil {
+			cmd.Printf("Error: %v\n", err)
+			continue
+		}
+		if errors.Is(err, claude.ErrTokenLimit) {
+			cmd.Println("Warning: Response was truncated due to token limit")
+		}
+
+		asked, reply := result.Messages(analysis.ID, question)
+		if err := database.SaveAnalysisMessages(asked, reply); err != nil {
+			cmd.Printf("Warning: failed to save messages: %v\n", err)
+		}
+		messages = append(messages, *asked, *reply)
+	}
+}
+
+// newClaudeClient builds a Claude client from the claude.* settings, using model
+// instead of claude.model when it is set and the named prompt template from
+// claude.prompt_dir (the default template when empty).
+func newClaudeClient(model, templateName string) (*claude.Client, error) {
+	if model == "" {
+		model = cfg.Claude.Model
+	}
+	tmpl, err := claude.LoadPromptTemplate(cfg.Claude.PromptDir, templateName)
+	if err != nil {
+		return nil, err
+	}
+	temperature := cfg.Claude.Temperature
+	prices := make(map[string]claude.Price, len(cfg.Claude.Prices))
+	for m, p := range cfg.Claude.Prices {
+		prices[m] = claude.Price{Input: p.Input, Output: p.Output}
+	}
+	client, err := claude.NewClient(claude.Config{
+		APIKey:        cfg.Claude.APIKey,
+		Endpoint:      cfg.Claude.Endpoint,
+		Model:         model,
+		MaxTokens:     cfg.Claude.MaxTokens,
+		Temperature:   &temperature,
+		Prices:        prices,
+		Template:      tmpl,
+		ContextBudget: cfg.Claude.ContextBudget,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Claude client: %w", err)
+	}
+	return client, nil
+}
+
 func init() {
 	analyzeCmd.Flags().BoolVar(&skipFetch, "skip-fetch", false, "Skip fetching new data and use cached data only")
+	analyzeCmd.Flags().StringVar(&analyzeModel, "model", "", "Claude model to use for this analysis (defaults to claude.model)")
+	analyzeCmd.Flags().StringVar(&analyzeTemplate, "template", "", "Prompt template to use from claude.prompt_dir (defaults to the built-in prompt)")
+	analyzeCmd.Flags().BoolVar(&analyzeChat, "chat", false, "Ask follow-up questions after the analysis")
+	analyzeCmd.Flags().BoolVar(&analyzeResume, "resume", false, "Continue the follow-up conversation on the trader's latest analysis")
+	analyzeCmd.Flags().IntVar(&analyzeTop, "top", 0, "Analyze the top N traders by P&L in one message batch")
+	analyzeCmd.Flags().BoolVar(&analyzeWatchlist, "watchlist", false, "Analyze every watchlist trader in one message batch")
+	analyzeCmd.Flags().BoolVar(&analyzeResumeBatches, "resume-batches", false, "Wait for batches submitted earlier and save their results")
+	analyzeCmd.Flags().BoolVar(&analyzeNoWait, "no-wait", false, "Submit the batch and exit without waiting for results")
+	analyzeCmd.Flags().DurationVar(&analyzePollInterval, "poll-interval", 30*time.Second, "How often to check on a submitted batch")
 	rootCmd.AddCommand(analyzeCmd)
 }
 
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+// Sources recorded with each analysis batch.
+const (
+	batchSourceTop       = "top"
+	batchSourceWatchlist = "watchlist"
+)
+
+// analyzeBatch submits analyses of the top traders or the watchlist as one
+// message batch and, unless --no-wait is set, waits for it and saves the results.
+func analyzeBatch(cmd *cobra.Command, database *db.DB) error {
+	source, addresses, err := batchAddresses(database)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		if source == batchSourceWatchlist {
+			cmd.Println("No traders on the watchlist.")
+		} else {
+			cmd.Println("No traders found. Run 'polytracker scan' first.")
+		}
+		return nil
+	}
+
+	if open, err := database.ListOpenAnalysisBatches(); err != nil {
+		return err
+	} else if len(open) > 0 {
+		cmd.Printf("Note: %d earlier batch(es) not collected yet; run 'polytracker analyze --resume-batches' to collect them.\n", len(open))
+	}
+
+	if !skipFetch {
+		pmClient := polymarket.NewClient(polymarket.Config{
+			APIKey:     cfg.Polymarket.APIKey,
+			APISecret:  cfg.Polymarket.APISecret,
+			Passphrase: cfg.Polymarket.Passphrase,
+		})
+		fetcher := polymarket.NewFetcher(pmClient, database)
+		for _, address := range addresses {
+			cmd.Printf("Fetching history for trader: %s\n", address)
+			if err := fetcher.FetchTraderHistory(context.Background(), address); err != nil {
+				cmd.Printf("Warning: fetch failed for %s: %v\n", address, err)
+			}
+		}
+		cmd.Println("Data fetch complete.")
+	}
+
+	profiler := analytics.NewProfiler(database)
+	var data []claude.TraderData
+	for _, address := range addresses {
+		trader, err := database.GetTrader(address)
+		if err != nil {
+			return fmt.Errorf("failed to get trader: %w", err)
+		}
+		if trader == nil {
+			cmd.Printf("Warning: skipping unknown trader %s\n", address)
+			continue
+		}
+		d, err := claude.LoadTraderData(database, trader)
+		if err != nil {
+			return err
+		}
+		if profile, err := profiler.ProfileTrader(address); err != nil {
+			cmd.Printf("Warning: failed to compute behavioral profile for %s: %v\n", address, err)
+		} else {
+			d.Profile = profile
+		}
+		data = append(data, d)
+	}
+	if len(data) == 0 {
+		cmd.Println("No trader data to analyze.")
+		return nil
+	}
+
+	if cfg.Claude.APIKey == "" {
+		cmd.Println("\nClaude API key not configured. Skipping AI analysis.")
+		cmd.Println("Set POLYTRACKER_CLAUDE_API_KEY or add claude.api_key to config.yaml")
+		return nil
+	}
+	claudeClient, err := newClaudeClient(analyzeModel, analyzeTemplate)
+	if err != nil {
+		return err
+	}
+
+	tmpl := claudeClient.Template()
+	cmd.Printf("\nSubmitting %d traders to the Message Batches API (%s, template %s@%s)...\n", len(data), claudeClient.Model(), tmpl.Name, tmpl.Hash)
+	batch, err := claudeClient.SubmitBatch(context.Background(), data)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+	batch.Source = source
+	if err := database.SaveAnalysisBatch(batch); err != nil {
+		return fmt.Errorf("batch %s was submitted but could not be recorded: %w", batch.ID, err)
+	}
+	cmd.Printf("Submitted batch %s.\n", batch.ID)
+
+	if analyzeNoWait {
+		cmd.Println("Collect the results later with 'polytracker analyze --resume-batches'.")
+		return nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return collectBatch(ctx, cmd, database, claudeClient, batch)
+}
+
+// batchAddresses returns the traders selected by --top or --watchlist and the
+// source recorded with their batch.
+func batchAddresses(database *db.DB) (string, []string, error) {
+	var addresses []string
+	if analyzeWatchlist {
+		items, err := database.ListWatchlist()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, item := range items {
+			addresses = append(addresses, item.TraderID)
+		}
+		return batchSourceWatchlist, addresses, nil
+	}
+
+	traders, err := database.ListTradersWithOptions(db.ListTradersOptions{
+		SortBy: db.SortByProfitLoss,
+		Order:  db.SortDesc,
+		Limit:  analyzeTop,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	for _, t := range traders {
+		addresses = append(addresses, t.Address)
+	}
+	return batchSourceTop, addresses, nil
+}
+
+// resumeBatches collects every batch submitted earlier whose results have not
+// been saved, such as one left in flight when the command was interrupted.
+func resumeBatches(cmd *cobra.Command, database *db.DB) error {
+	open, err := database.ListOpenAnalysisBatches()
+	if err != nil {
+		return err
+	}
+	if len(open) == 0 {
+		cmd.Println("No analysis batches in flight.")
+		return nil
+	}
+
+	if cfg.Claude.APIKey == "" {
+		cmd.Println("Claude API key not configured. Cannot collect batch results.")
+		cmd.Println("Set POLYTRACKER_CLAUDE_API_KEY or add claude.api_key to config.yaml")
+		return nil
+	}
+	// Results are recorded against the model and template each batch was
+	// submitted with, so any client can collect them.
+	claudeClient, err := newClaudeClient("", "")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for i := range open {
+		batch := &open[i]
+		cmd.Printf("Resuming batch %s: %d %s traders submitted %s (%s)\n",
+			batch.ID, len(batch.Items), batch.Source, batch.CreatedAt.Format("2006-01-02 15:04"), batch.Model)
+		if err := collectBatch(ctx, cmd, database, claudeClient, batch); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// collectBatch polls a batch until it ends, then saves an analysis for each
+// item still pending and marks the batch collected. Items saved by an earlier,
+// interrupted collection are skipped. If ctx is cancelled before the results
+// are fetched, the batch is left open to be resumed and no error is returned.
+func collectBatch(ctx context.Context, cmd *cobra.Command, database *db.DB, claudeClient *claude.Client, batch *db.AnalysisBatch) error {
+	for {
+		status, err := claudeClient.GetBatch(ctx, batch.ID)
+		if ctx.Err() != nil {
+			return batchInterrupted(cmd, batch)
+		}
+		if err != nil {
+			return err
+		}
+		if status.Ended {
+			break
+		}
+		cmd.Printf("Batch %s: %d of %d requests still processing...\n", batch.ID, status.Processing, len(batch.Items))
+
+		timer := time.NewTimer(analyzePollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return batchInterrupted(cmd, batch)
+		case <-timer.C:
+		}
+	}
+
+	results, err := claudeClient.BatchResults(ctx, batch)
+	if ctx.Err() != nil {
+		return batchInterrupted(cmd, batch)
+	}
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[string]bool, len(batch.Items))
+	for _, item := range batch.Items {
+		if item.Status == db.BatchItemPending {
+			pending[item.TraderID] = true
+		}
+	}
+
+	cmd.Println()
+	var saved, failed int
+	var costUSD float64
+	for _, r := range results {
+		if !pending[r.TraderID] {
+			continue
+		}
+		delete(pending, r.TraderID)
+
+		if r.Result == nil {
+			failed++
+			cmd.Printf("  %s: failed: %v\n", r.TraderID, r.Err)
+			if err := database.FailAnalysisBatchItem(batch.ID, r.TraderID, r.Err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		analysis := r.Result.Analysis(r.TraderID)
+		if err := database.SaveBatchAnalysis(batch.ID, analysis); err != nil {
+			return err
+		}
+		saved++
+		costUSD += analysis.CostUSD
+
+		line := fmt.Sprintf("  %s: saved analysis #%d ($%.4f)", r.TraderID, analysis.ID, analysis.CostUSD)
+		if s := analysis.Summary; s != nil {
+			line += fmt.Sprintf(" | %s, risk %d/10, copyability %s", s.Archetype, s.RiskScore, s.Copyability)
+		}
+		if errors.Is(r.Err, claude.ErrTokenLimit) {
+			line += " | truncated at the token limit"
+		}
+		cmd.Println(line)
+	}
+
+	// Requests the results do not mention cannot be recovered.
+	for _, item := range batch.Items {
+		if pending[item.TraderID] {
+			failed++
+			cmd.Printf("  %s: failed: no result returned\n", item.TraderID)
+			if err := database.FailAnalysisBatchItem(batch.ID, item.TraderID, "no result returned"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := database.FinishAnalysisBatch(batch.ID); err != nil {
+		return err
+	}
+	cmd.Printf("\nBatch %s collected: %d saved, %d failed | Cost: $%.4f (batch pricing)\n", batch.ID, saved, failed, costUSD)
+	return nil
+}
+
+// batchInterrupted tells the user how to pick up a batch whose collection was
+// interrupted; the batch keeps running and stays recorded as open.
+func batchInterrupted(cmd *cobra.Command, batch *db.AnalysisBatch) error {
+	cmd.Printf("\nInterrupted. Batch %s keeps running; collect it with 'polytracker analyze --resume-batches'.\n", batch.ID)
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	marketSkipFetch bool
+	marketModel     string
+)
+
+var analyzeMarketCmd = &cobra.Command{
+	Use:   "analyze-market <id>",
+	Short: "Analyze who is trading a market and why using Claude AI",
+	Long: `Ask Claude for a read on smart-money positioning in a market.
+
+The prompt covers the market's question, its stored price history, net flow
+by outcome, and the traders in the stored trades ranked by volume, with their
+win rate, ROI, overall P&L and classifier label. Participants and price
+history are cut to keep the prompt within claude.context_budget tokens.
+
+Unless --skip-fetch is set, the market's recent trades are fetched and stored
+first, each under its taker; trades already stored with a trader's history
+keep their attribution. The analysis is saved and shown on the market screen
+of the TUI.
+
+Example:
+  polytracker analyze-market 0x5f3a... --skip-fetch`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		marketID := args[0]
+
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		if !marketSkipFetch {
+			cmd.Printf("Fetching market: %s\n", marketID)
+			pmClient := polymarket.NewClient(polymarket.Config{
+				APIKey:     cfg.Polymarket.APIKey,
+				APISecret:  cfg.Polymarket.APISecret,
+				Passphrase: cfg.Polymarket.Passphrase,
+			})
+			if err := polymarket.NewFetcher(pmClient, database).FetchMarket(context.Background(), marketID); err != nil {
+				return fmt.Errorf("fetch failed: %w", err)
+			}
+			cmd.Println("Data fetch complete.")
+		}
+
+		market, err := database.GetMarket(marketID)
+		if err != nil {
+			return fmt.Errorf("failed to get market: %w", err)
+		}
+		if market == nil {
+			return fmt.Errorf("market not found: %s", marketID)
+		}
+		data, err := claude.LoadMarketData(database, market)
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("\nMarket: %s\n", market.Question)
+		cmd.Printf("Stored trades: %d | Price snapshots: %d\n", len(data.Trades), len(data.Snapshots))
+
+		if cfg.Claude.APIKey == "" {
+			cmd.Println("\nClaude API key not configured. Skipping analysis.")
+			cmd.Println("Set POLYTRACKER_CLAUDE_API_KEY or add claude.api_key to config.yaml")
+			return nil
+		}
+
+		claudeClient, err := newClaudeClient(marketModel, "")
+		if err != nil {
+			return err
+		}
+
+		prompt, err := claudeClient.BuildMarketPrompt(data)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Prompt: ~%d tokens of %d budget | %d of %d participants | %d of %d snapshots\n",
+			prompt.EstimatedTokens, prompt.Budget, prompt.ShownParticipants, prompt.TotalParticipants, prompt.ShownSnapshots, prompt.TotalSnapshots)
+
+		cmd.Printf("\nAnalyzing market with Claude AI (%s)...\n", claudeClient.Model())
+		result, err := claudeClient.AnalyzeMarket(context.Background(), data)
+		if err != nil {
+			if errors.Is(err, claude.ErrTokenLimit) {
+				cmd.Println("Warning: Response was truncated due to token limit")
+			} else {
+				return fmt.Errorf("analysis failed: %w", err)
+			}
+		}
+
+		cmd.Printf("\n%s\n", result.Thesis)
+		cmd.Printf("\n---\n")
+		cmd.Printf("Model: %s | Tokens: %d in / %d out | Cost: $%.4f\n", result.Model, result.InputTokens, result.OutputTokens, result.CostUSD)
+
+		if err := database.SaveMarketAnalysis(result.MarketAnalysis(marketID)); err != nil {
+			cmd.Printf("Warning: failed to save market analysis: %v\n", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	analyzeMarketCmd.Flags().BoolVar(&marketSkipFetch, "skip-fetch", false, "Skip fetching new data and use cached data only")
+	analyzeMarketCmd.Flags().StringVar(&marketModel, "model", "", "Claude model to use for this analysis (defaults to claude.model)")
+	rootCmd.AddCommand(analyzeMarketCmd)
+}
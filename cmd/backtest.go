@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"polytracker/internal/backtest"
+	"polytracker/internal/db"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backtestDelay     time.Duration
+	backtestSizing    string
+	backtestCash      float64
+	backtestNotional  float64
+	backtestRatio     float64
+	backtestKellyCap  float64
+	backtestSpreadBps float64
+	backtestSlippage  string
+	backtestPoints    int
+)
+
+var backtestCmd = &cobra.Command{
+	Use:   "backtest [address...]",
+	Short: "Simulate copy-trading one or more traders over stored history",
+	Long: `Replay the stored trades of the given traders (or everyone on the watchlist)
+as if each had been copied --delay later. Buys are sized with --sizing:
+
+  fixed         spend --notional per copied buy
+  proportional  spend --ratio times the trader's own notional
+  kelly         stake the Kelly fraction implied by the trader's win rate,
+                capped at --kelly-cap of equity
+
+Fills use the last snapshot or print at the delayed time plus slippage: a fixed
+--spread-bps, or with --slippage book the impact of walking the current CLOB
+order book for the copied size. Markets whose books cannot be fetched fall back
+to the spread, with a warning; the backtest fails if no book can be fetched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sizing, err := backtest.ParseSizingRule(backtestSizing)
+		if err != nil {
+			return err
+		}
+
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		traders := args
+		if len(traders) == 0 {
+			items, err := database.ListWatchlist()
+			if err != nil {
+				return fmt.Errorf("failed to list watchlist: %w", err)
+			}
+			for _, item := range items {
+				traders = append(traders, item.TraderID)
+			}
+		}
+		if len(traders) == 0 {
+			return fmt.Errorf("no traders given and the watchlist is empty")
+		}
+
+		bt := backtest.NewBacktester(database)
+		bt.Delay = backtestDelay
+		bt.Sizing = sizing
+		bt.StartingCash = backtestCash
+		bt.FixedNotional = backtestNotional
+		bt.Ratio = backtestRatio
+		bt.KellyCap = backtestKellyCap
+
+		spread := backtest.SpreadModel{Bps: backtestSpreadBps}
+		switch backtestSlippage {
+		case "spread":
+			bt.Slippage = spread
+		case "book":
+			books, failed, err := fetchOrderbooks(cmd.Context(), database, traders)
+			if err != nil {
+				return err
+			}
+			if failed > 0 {
+				cmd.Printf("Warning: failed to fetch order books for %d market(s); their fills use the spread model.\n", failed)
+			}
+			bt.Slippage = backtest.BookModel{Books: books, Fallback: spread}
+		default:
+			return fmt.Errorf("unknown slippage model %q (want spread or book)", backtestSlippage)
+		}
+
+		cmd.Printf("Backtesting %d trader(s) with %s sizing, %s delay...\n\n", len(traders), sizing, backtestDelay)
+		res, err := bt.Run(traders)
+		if err != nil {
+			return fmt.Errorf("backtest failed: %w", err)
+		}
+
+		cmd.Printf("%-16s $%.2f\n", "Starting cash:", res.StartingCash)
+		cmd.Printf("%-16s $%.2f\n", "Final equity:", res.FinalEquity)
+		cmd.Printf("%-16s %+.2f (%+.1f%%)\n", "P&L:", res.ProfitLoss, res.Return*100)
+		cmd.Printf("%-16s %.1f%%\n", "Max drawdown:", res.MaxDrawdown*100)
+		cmd.Printf("%-16s %.1f%% of %d positions\n", "Hit rate:", res.HitRate*100, res.Positions)
+		cmd.Printf("%-16s %d copied, %d skipped\n", "Fills:", res.Fills, res.Skipped)
+
+		if len(res.Curve) > 0 && backtestPoints > 0 {
+			cmd.Println("\nEquity curve:")
+			step := (len(res.Curve) + backtestPoints - 1) / backtestPoints
+			for i := 0; i < len(res.Curve); i += step {
+				p := res.Curve[i]
+				cmd.Printf("  %s  $%.2f\n", p.Time.Format("2006-01-02 15:04"), p.Equity)
+			}
+			if (len(res.Curve)-1)%step != 0 {
+				p := res.Curve[len(res.Curve)-1]
+				cmd.Printf("  %s  $%.2f\n", p.Time.Format("2006-01-02 15:04"), p.Equity)
+			}
+		}
+		return nil
+	},
+}
+
+// fetchOrderbooks loads the current YES- and NO-token order books for every
+// market the traders have traded, as a proxy for the depth available to a
+// copied order. It also returns how many markets are missing a book because a
+// request failed, and an error if every request did.
+func fetchOrderbooks(ctx context.Context, database *db.DB, traders []string) (map[backtest.BookKey]*polymarket.Orderbook, int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := polymarket.NewClient(polymarket.Config{
+		APIKey:     cfg.Polymarket.APIKey,
+		APISecret:  cfg.Polymarket.APISecret,
+		Passphrase: cfg.Polymarket.Passphrase,
+	})
+
+	books := make(map[backtest.BookKey]*polymarket.Orderbook)
+	fetched := make(map[string]bool)
+	failed := 0
+	var lastErr error
+	for _, address := range traders {
+		trades, err := database.GetTradesByTrader(address)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get trades for %s: %w", address, err)
+		}
+		for _, t := range trades {
+			if fetched[t.MarketID] {
+				continue
+			}
+			fetched[t.MarketID] = true
+
+			market, err := client.GetMarket(ctx, t.MarketID)
+			if err != nil {
+				failed++
+				lastErr = err
+				continue
+			}
+			missing := false
+			for _, token := range market.Tokens {
+				if token.Outcome != "Yes" && token.Outcome != "No" {
+					continue
+				}
+				book, err := client.GetOrderbook(ctx, token.TokenID)
+				if err != nil {
+					missing = true
+					lastErr = err
+					continue
+				}
+				books[backtest.NewBookKey(t.MarketID, token.Outcome)] = book
+			}
+			if missing {
+				failed++
+			}
+		}
+	}
+	if len(books) == 0 && lastErr != nil {
+		return nil, failed, fmt.Errorf("failed to fetch any order book: %w", lastErr)
+	}
+	return books, failed, nil
+}
+
+func init() {
+	backtestCmd.Flags().DurationVar(&backtestDelay, "delay", backtest.DefaultDelay, "How long after the trader each copy is filled")
+	backtestCmd.Flags().StringVar(&backtestSizing, "sizing", string(backtest.SizingFixed), "Sizing rule (fixed, proportional, kelly)")
+	backtestCmd.Flags().Float64Var(&backtestCash, "cash", backtest.DefaultStartingCash, "Starting cash")
+	backtestCmd.Flags().Float64Var(&backtestNotional, "notional", backtest.DefaultFixedNotional, "Cash per copied buy with fixed sizing")
+	backtestCmd.Flags().Float64Var(&backtestRatio, "ratio", backtest.DefaultRatio, "Share of the trader's notional to copy with proportional sizing")
+	backtestCmd.Flags().Float64Var(&backtestKellyCap, "kelly-cap", backtest.DefaultKellyCap, "Maximum fraction of equity per trade with kelly sizing")
+	backtestCmd.Flags().Float64Var(&backtestSpreadBps, "spread-bps", backtest.DefaultSpreadBps, "Half-spread paid on each fill, in basis points")
+	backtestCmd.Flags().StringVar(&backtestSlippage, "slippage", "spread", "Slippage model (spread, book)")
+	backtestCmd.Flags().IntVar(&backtestPoints, "points", 20, "Equity curve points to print (0 to hide)")
+	rootCmd.AddCommand(backtestCmd)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var classifyCmd = &cobra.Command{
+	Use:   "classify [address]",
+	Short: "Label traders as directional, market maker or bot",
+	Long: `Run the heuristic trader-type classifier over stored trades. It looks at
+maker ratio, two-sided quoting within a market, trade frequency, round-number
+sizes and sub-second cadence, and stores a label with a confidence score.
+Classifies all traders when no address is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		classifier := analytics.NewClassifier(database)
+
+		if len(args) == 0 {
+			cmd.Println("Classifying all traders...")
+			counts, err := classifier.ClassifyAll()
+			if err != nil {
+				return fmt.Errorf("classification failed: %w", err)
+			}
+			for _, label := range []string{db.TraderTypeDirectional, db.TraderTypeMarketMaker, db.TraderTypeBot, db.TraderTypeUnknown} {
+				cmd.Printf("  %-12s %d\n", label+":", counts[label])
+			}
+			return nil
+		}
+
+		c, err := classifier.ClassifyTrader(args[0])
+		if err != nil {
+			return fmt.Errorf("classification failed: %w", err)
+		}
+
+		cmd.Printf("Classification for %s: %s (confidence %.0f%%)\n\n", args[0], c.Label, c.Confidence*100)
+		cmd.Printf("  Maker ratio:        %.1f%%\n", c.MakerRatio*100)
+		cmd.Printf("  Two-sided markets:  %.1f%%\n", c.TwoSidedRatio*100)
+		cmd.Printf("  Trades per day:     %.1f\n", c.TradesPerDay)
+		cmd.Printf("  Round-number sizes: %.1f%%\n", c.RoundSizeRatio*100)
+		cmd.Printf("  Sub-second gaps:    %.1f%%\n", c.SubSecondRatio*100)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(classifyCmd)
+}
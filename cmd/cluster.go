@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterThreshold float64
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Group wallets likely controlled by the same entity",
+	Long: `Score every pair of traders that share a market on market overlap, co-timed
+entries and sizing patterns, then link pairs scoring at least --threshold into
+clusters. Similarities and cluster membership are stored so the TUI can show
+similar traders on the trader detail screen.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		clusterer := analytics.NewClusterer(database)
+		clusterer.Threshold = clusterThreshold
+
+		cmd.Println("Computing trader similarities...")
+		clusters, err := clusterer.Run()
+		if err != nil {
+			return fmt.Errorf("clustering failed: %w", err)
+		}
+
+		if len(clusters) == 0 {
+			cmd.Println("No wallet clusters found.")
+			return nil
+		}
+
+		cmd.Printf("Found %d wallet clusters:\n", len(clusters))
+		for i, members := range clusters {
+			cmd.Printf("\nCluster %d (%d wallets)\n", i+1, len(members))
+			for _, addr := range members {
+				cmd.Printf("  %s\n", addr)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	clusterCmd.Flags().Float64Var(&clusterThreshold, "threshold", analytics.DefaultClusterThreshold, "Similarity score (0-1) at which two wallets are linked")
+	rootCmd.AddCommand(clusterCmd)
+}
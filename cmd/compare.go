@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+	"polytracker/internal/export"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareSkipFetch bool
+	compareModel     string
+	compareFilename  string
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <address> <address> [address...]",
+	Short: "Compare several traders' strategies using Claude AI",
+	Long: fmt.Sprintf(`Ask Claude to compare %d to %d traders side by side.
+
+Each trader's statistics, positions and sampled trades get an equal share of
+claude.context_budget. The comparison is saved, linked to every trader, and
+exported to Markdown in the exports directory.
+
+Example:
+  polytracker compare 0x1234... 0xabcd... --skip-fetch`, claude.MinCompareTraders, claude.MaxCompareTraders),
+	Args: cobra.RangeArgs(claude.MinCompareTraders, claude.MaxCompareTraders),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seen := make(map[string]bool, len(args))
+		for _, address := range args {
+			if seen[address] {
+				return fmt.Errorf("trader listed twice: %s", address)
+			}
+			seen[address] = true
+		}
+
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		if !compareSkipFetch {
+			pmClient := polymarket.NewClient(polymarket.Config{
+				APIKey:     cfg.Polymarket.APIKey,
+				APISecret:  cfg.Polymarket.APISecret,
+				Passphrase: cfg.Polymarket.Passphrase,
+			})
+			fetcher := polymarket.NewFetcher(pmClient, database)
+			for _, address := range args {
+				cmd.Printf("Fetching history for trader: %s\n", address)
+				if err := fetcher.FetchTraderHistory(context.Background(), address); err != nil {
+					return fmt.Errorf("fetch failed for %s: %w", address, err)
+				}
+			}
+			cmd.Println("Data fetch complete.")
+		}
+
+		traders := make([]*db.Trader, len(args))
+		data := make([]claude.TraderData, len(args))
+		for i, address := range args {
+			trader, err := database.GetTrader(address)
+			if err != nil {
+				return fmt.Errorf("failed to get trader: %w", err)
+			}
+			if trader == nil {
+				return fmt.Errorf("trader not found: %s", address)
+			}
+			traders[i] = trader
+			if data[i], err = claude.LoadTraderData(database, trader); err != nil {
+				return err
+			}
+		}
+
+		if cfg.Claude.APIKey == "" {
+			cmd.Println("\nClaude API key not configured. Skipping comparison.")
+			cmd.Println("Set POLYTRACKER_CLAUDE_API_KEY or add claude.api_key to config.yaml")
+			return nil
+		}
+
+		claudeClient, err := newClaudeClient(compareModel, "")
+		if err != nil {
+			return err
+		}
+
+		prompt, err := claudeClient.BuildComparePrompt(data)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Prompt: ~%d tokens of %d budget | %d of %d trades sampled | %d of %d markets\n",
+			prompt.EstimatedTokens, prompt.Budget, prompt.SampledTrades, prompt.TotalTrades, prompt.ShownMarkets, prompt.TotalMarkets)
+
+		cmd.Printf("\nComparing %d traders with Claude AI (%s)...\n", len(args), claudeClient.Model())
+		result, err := claudeClient.CompareTraders(context.Background(), data)
+		if err != nil {
+			if errors.Is(err, claude.ErrTokenLimit) {
+				cmd.Println("Warning: Response was truncated due to token limit")
+			} else {
+				return fmt.Errorf("comparison failed: %w", err)
+			}
+		}
+
+		cmd.Printf("\n%s\n", result.Thesis)
+		cmd.Printf("\n---\n")
+		cmd.Printf("Model: %s | Tokens: %d in / %d out | Cost: $%.4f\n", result.Model, result.InputTokens, result.OutputTokens, result.CostUSD)
+
+		comparison := result.Comparison(args)
+		if err := database.SaveComparison(comparison); err != nil {
+			cmd.Printf("Warning: failed to save comparison: %v\n", err)
+		}
+
+		filename := compareFilename
+		if filename == "" {
+			filename = outputFile
+		}
+		path, err := export.NewExporter("exports").ExportComparisonMarkdown(traders, comparison, filename)
+		if err != nil {
+			return fmt.Errorf("failed to export comparison: %w", err)
+		}
+		cmd.Printf("Exported comparison to: %s\n", path)
+		return nil
+	},
+}
+
+func init() {
+	compareCmd.Flags().BoolVar(&compareSkipFetch, "skip-fetch", false, "Skip fetching new data and use cached data only")
+	compareCmd.Flags().StringVar(&compareModel, "model", "", "Claude model to use for this comparison (defaults to claude.model)")
+	compareCmd.Flags().StringVarP(&compareFilename, "filename", "f", "", "Output filename (auto-generated if not specified)")
+	rootCmd.AddCommand(compareCmd)
+}
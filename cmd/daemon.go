@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+	"polytracker/internal/polymarket"
+	"polytracker/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonRunNow     bool
+	daemonHistoryJob string
+	daemonHistoryN   int
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run scheduled scans, refreshes and digests until stopped",
+	Long: `Run jobs on the cron schedules under daemon.jobs until interrupted:
+
+  scan        scan recent activity and evaluate alerts
+  watchlist   refetch watchlisted traders, mirror trades into the paper portfolio, evaluate alerts
+  snapshot    snapshot prices of every open market in the database
+  resolution  mark markets closed or resolved
+  digest      email the daily digest (requires email.smtp_host)
+
+Schedules are standard five-field cron expressions (or @hourly, @every 10m, ...);
+an empty schedule disables a job. Each run takes a lock in the database so two
+daemons never run the same job at once, and is recorded in the job history
+shown by 'polytracker daemon history'. On SIGINT or SIGTERM the daemon stops
+scheduling and waits for running jobs to finish.
+
+Prometheus metrics are served on http://<daemon.metrics_addr>/metrics unless
+daemon.metrics_addr is empty.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		client := polymarket.NewClient(polymarket.Config{
+			APIKey:     cfg.Polymarket.APIKey,
+			APISecret:  cfg.Polymarket.APISecret,
+			Passphrase: cfg.Polymarket.Passphrase,
+		})
+
+		host, _ := os.Hostname()
+		sched := scheduler.NewScheduler(database, fmt.Sprintf("%s:%d", host, os.Getpid()))
+
+		jobs := daemonJobs(client, database)
+		var names []string
+		for _, job := range jobs {
+			if err := sched.Add(job); err != nil {
+				return err
+			}
+			names = append(names, fmt.Sprintf("%s (%s)", job.Name, job.Schedule))
+		}
+		if len(jobs) == 0 {
+			return fmt.Errorf("no jobs scheduled; configure daemon.jobs")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if daemonRunNow {
+			for _, job := range jobs {
+				if ctx.Err() != nil {
+					break
+				}
+				if err := sched.Run(job); err != nil {
+					log.Printf("Job %s failed: %v", job.Name, err)
+				}
+			}
+		}
+
+		if cfg.Daemon.MetricsAddr != "" {
+			metricsServer := serveMetrics(cfg.Daemon.MetricsAddr)
+			defer metricsServer.Close()
+		}
+
+		sched.Start()
+		log.Printf("Daemon started: %s", strings.Join(names, ", "))
+		<-ctx.Done()
+
+		log.Printf("Shutting down; waiting for running jobs...")
+		sched.Stop()
+		log.Printf("Daemon stopped.")
+		return nil
+	},
+}
+
+var daemonHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent daemon job runs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		runs, err := database.ListJobRuns(daemonHistoryJob, daemonHistoryN)
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			cmd.Println("No job runs recorded.")
+			return nil
+		}
+		cmd.Printf("%-16s %-11s %-10s %10s  %s\n", "Started", "Job", "Status", "Duration", "Error")
+		for _, r := range runs {
+			duration := "-"
+			if !r.FinishedAt.IsZero() {
+				duration = r.FinishedAt.Sub(r.StartedAt).Round(100 * time.Millisecond).String()
+			}
+			cmd.Printf("%-16s %-11s %-10s %10s  %s\n", r.StartedAt.Format("2006-01-02 15:04"), r.Job, r.Status, duration, r.Error)
+		}
+		return nil
+	},
+}
+
+// serveMetrics serves /metrics on addr in the background. Failing to listen is
+// logged rather than fatal so metrics never keep the daemon from running.
+func serveMetrics(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Warning: metrics server failed: %v", err)
+		}
+	}()
+	log.Printf("Serving metrics on http://%s/metrics", addr)
+	return server
+}
+
+// daemonJobs builds the jobs that have a schedule in daemon.jobs, in name order.
+func daemonJobs(client *polymarket.Client, database *db.DB) []scheduler.Job {
+	out := log.Writer()
+	fetcher := polymarket.NewFetcher(client, database)
+
+	runs := map[string]func(ctx context.Context) error{
+		"scan": func(ctx context.Context) error {
+			scanner := polymarket.NewScanner(client, database)
+			if err := scanner.ScanRecentActivity(ctx, 10); err != nil {
+				return fmt.Errorf("scan failed: %w", err)
+			}
+			return evaluateAlerts(ctx, database, out)
+		},
+		"watchlist": func(ctx context.Context) error {
+			copied, err := syncPaperPortfolio(ctx, client, database)
+			if err != nil {
+				return err
+			}
+			if copied > 0 {
+				log.Printf("Mirrored %d new watchlist trade(s) into the paper portfolio.", copied)
+			}
+			return evaluateAlerts(ctx, database, out)
+		},
+		"snapshot": func(ctx context.Context) error {
+			n, err := fetcher.SnapshotMarkets(ctx)
+			if err == nil {
+				log.Printf("Checked snapshots for %d open market(s).", n)
+			}
+			return err
+		},
+		"resolution": func(ctx context.Context) error {
+			n, err := fetcher.SyncResolutions(ctx)
+			if err == nil && n > 0 {
+				log.Printf("Updated the status of %d market(s).", n)
+			}
+			return err
+		},
+		"digest": func(ctx context.Context) error {
+			return runDigest(database, false, out)
+		},
+	}
+
+	var names []string
+	for name, schedule := range cfg.Daemon.Jobs {
+		if strings.TrimSpace(schedule) == "" {
+			continue
+		}
+		if _, ok := runs[name]; !ok {
+			log.Printf("Warning: ignoring unknown daemon job %q", name)
+			continue
+		}
+		if name == "digest" && cfg.Email.SMTPHost == "" {
+			log.Printf("Skipping digest job: email.smtp_host is not configured")
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	jobs := make([]scheduler.Job, len(names))
+	for i, name := range names {
+		jobs[i] = scheduler.Job{Name: name, Schedule: cfg.Daemon.Jobs[name], Run: runs[name]}
+	}
+	return jobs
+}
+
+func init() {
+	daemonCmd.Flags().BoolVar(&daemonRunNow, "run-now", false, "Run every scheduled job once at startup")
+	daemonHistoryCmd.Flags().StringVar(&daemonHistoryJob, "job", "", "Only show runs of this job")
+	daemonHistoryCmd.Flags().IntVar(&daemonHistoryN, "limit", 20, "Maximum number of runs to show")
+	daemonCmd.AddCommand(daemonHistoryCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	detectWindow     time.Duration
+	detectThreshold  float64
+	detectMinEntries int
+	detectLimit      int
+)
+
+var detectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Rank wallets whose entries consistently precede large price moves or surprise resolutions",
+	Long: `Scan stored trades and price history for entries that were followed within
+--window by a favorable move of at least --threshold; a market resolving against
+its prevailing price counts as such a move. Each trader's hit rate is compared
+with the rate for random entries in the same markets, and traders are ranked by
+how far they beat that baseline ("suspicious timing").`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		detector := analytics.NewDetector(database)
+		detector.Window = detectWindow
+		detector.Threshold = detectThreshold
+		detector.MinEntries = detectMinEntries
+
+		cmd.Printf("Detecting entries followed by moves >= %.2f within %s...\n\n", detectThreshold, detectWindow)
+		reports, err := detector.Detect()
+		if err != nil {
+			return fmt.Errorf("detection failed: %w", err)
+		}
+
+		if len(reports) == 0 {
+			cmd.Println("No traders with enough entries to evaluate.")
+			return nil
+		}
+
+		if detectLimit > 0 && len(reports) > detectLimit {
+			reports = reports[:detectLimit]
+		}
+
+		cmd.Printf("%-4s %-44s %8s %6s %9s %9s %6s %7s\n", "#", "Trader", "Entries", "Hits", "Hit %", "Base %", "Lift", "Z")
+		for i, r := range reports {
+			cmd.Printf("%-4d %-44s %8d %6d %8.1f%% %8.1f%% %6.2f %7.2f\n",
+				i+1, r.TraderID, r.Entries, r.Hits, r.HitRate*100, r.BaselineRate*100, r.Lift, r.ZScore)
+		}
+		return nil
+	},
+}
+
+func init() {
+	detectCmd.Flags().DurationVar(&detectWindow, "window", analytics.DefaultMoveWindow, "How soon after an entry the move must happen")
+	detectCmd.Flags().Float64Var(&detectThreshold, "threshold", analytics.DefaultMoveThreshold, "Minimum favorable price move (0-1) that counts as a hit")
+	detectCmd.Flags().IntVar(&detectMinEntries, "min-entries", analytics.DefaultMinEntries, "Minimum entries required to rank a trader")
+	detectCmd.Flags().IntVar(&detectLimit, "limit", 20, "Maximum number of traders to show (0 for all)")
+	rootCmd.AddCommand(detectCmd)
+}
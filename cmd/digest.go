@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"polytracker/internal/db"
+	"polytracker/internal/digest"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestHours  int
+	digestTop    int
+	digestDryRun bool
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Email a summary of leaderboard and watchlist changes",
+	Long: `Summarize the last 24 hours - new top traders, the largest rank moves,
+watchlisted traders' activity and new analyses - and email it as HTML and plain
+text through the SMTP server under email. Rank changes are measured against the
+leaderboard recorded by the previous digest.
+
+Use --dry-run to print the plain text digest instead of sending it; a dry run
+leaves the recorded leaderboard untouched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		return runDigest(database, digestDryRun, cmd.OutOrStdout())
+	},
+}
+
+// runDigest generates the digest and either prints it (dryRun) or emails it,
+// recording the leaderboard only once the email has been sent.
+func runDigest(database *db.DB, dryRun bool, out io.Writer) error {
+	var sender *digest.Sender
+	if !dryRun {
+		var err error
+		sender, err = digest.NewSender(digest.SMTPConfig{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.SMTPPort,
+			Username: cfg.Email.Username,
+			Password: cfg.Email.Password,
+			From:     cfg.Email.From,
+			To:       cfg.Email.To,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	gen := digest.NewGenerator(database)
+	if digestHours > 0 {
+		gen.Period = time.Duration(digestHours) * time.Hour
+	}
+	if digestTop > 0 {
+		gen.TopN = digestTop
+	}
+	d, err := gen.Generate(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to generate digest: %w", err)
+	}
+
+	if dryRun {
+		text, err := d.Text()
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, text)
+		return nil
+	}
+	if err := sender.SendDigest(d); err != nil {
+		return err
+	}
+	if err := gen.Record(d); err != nil {
+		return fmt.Errorf("digest sent but failed to record the leaderboard: %w", err)
+	}
+	fmt.Fprintf(out, "Digest sent to %d recipient(s).\n", len(cfg.Email.To))
+	return nil
+}
+
+func init() {
+	digestCmd.Flags().IntVar(&digestHours, "hours", 24, "Length of the period to summarize")
+	digestCmd.Flags().IntVar(&digestTop, "top", digest.DefaultTopN, "Size of the top-trader list")
+	digestCmd.Flags().BoolVar(&digestDryRun, "dry-run", false, "Print the digest instead of emailing it")
+	rootCmd.AddCommand(digestCmd)
+}
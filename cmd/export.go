@@ -13,6 +13,7 @@ var (
 	exportType     string
 	traderAddress  string
 	exportFilename string
+	exportFilters  db.ListTradersOptions
 )
 
 var exportCmd = &cobra.Command{
@@ -53,10 +54,11 @@ Examples:
 func exportLeaderboard(cmd *cobra.Command, database *db.DB, exporter *export.Exporter) error {
 	cmd.Println("Exporting leaderboard to CSV...")
 
-	traders, err := database.ListTradersWithOptions(db.ListTradersOptions{
-		SortBy: db.SortByProfitLoss,
-		Order:  db.SortDesc,
-	})
+	opts := exportFilters
+	opts.SortBy = db.SortByProfitLoss
+	opts.Order = db.SortDesc
+
+	traders, err := database.ListTradersWithOptions(opts)
 	if err != nil {
 		return fmt.Errorf("failed to fetch traders: %w", err)
 	}
@@ -128,6 +130,11 @@ func init() {
 	exportCmd.Flags().StringVarP(&exportType, "type", "t", "leaderboard", "Export type (leaderboard, thesis)")
 	exportCmd.Flags().StringVarP(&traderAddress, "trader", "a", "", "Trader address for thesis export")
 	exportCmd.Flags().StringVarP(&exportFilename, "filename", "f", "", "Output filename (auto-generated if not specified)")
+	exportCmd.Flags().Float64Var(&exportFilters.MinHoldingHours, "min-holding-hours", 0, "Only include traders with a median holding period of at least this many hours")
+	exportCmd.Flags().Float64Var(&exportFilters.MaxHoldingHours, "max-holding-hours", 0, "Only include traders with a median holding period of at most this many hours")
+	exportCmd.Flags().StringVar(&exportFilters.TraderType, "trader-type", "", "Only include traders with this classifier label (directional, market_maker, bot)")
+	exportCmd.Flags().Float64Var(&exportFilters.MinLateEntryRatio, "min-late-entry", 0, "Only include traders with at least this share (0-1) of entries in the final 24h")
+	exportCmd.Flags().StringVar(&exportFilters.Category, "category", "", "Rank traders by their performance within this market category")
 
 	// Also support the global --output flag
 	exportCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"polytracker/internal/db"
+	"polytracker/internal/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the database to MCP clients over stdio",
+	Long: `Speak the Model Context Protocol over stdin/stdout so MCP clients such as
+Claude Desktop can query stored data. Exposed tools:
+
+  list_top_traders    rank traders by profit_loss, win_rate, roi or volume
+  get_trader_trades   a trader's stats and most recent trades
+  get_market          a market and its latest price snapshot
+  search_markets      markets whose question matches a query
+  add_to_watchlist    add a trader to the watchlist with notes
+
+To use it from Claude Desktop, add to claude_desktop_config.json:
+
+  "mcpServers": {
+    "polytracker": {"command": "polytracker", "args": ["mcp"]}
+  }
+
+Logs go to stderr; stdout carries only protocol messages.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return mcp.NewServer(database).Serve(ctx, cmd.InOrStdin(), os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
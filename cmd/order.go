@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	orderSide    string
+	orderOutcome string
+	orderPrice   float64
+	orderSize    float64
+	orderDryRun  bool
+	orderYes     bool
+)
+
+var orderCmd = &cobra.Command{
+	Use:   "order [market-id]",
+	Short: "Build, sign and optionally place a CLOB limit order",
+	Long: `Build an EIP-712 signed limit order for one outcome of a market using
+trading.private_key. Orders are dry runs by default: the signed order is printed
+and recorded but not sent. Pass --dry-run=false to submit it; you will be asked
+to confirm unless --yes is given.
+
+Live orders are refused if they would take the total placed in the market past
+trading.max_market_notional, or the total placed today past
+trading.max_daily_notional.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		marketID := args[0]
+		if cfg.Trading.PrivateKey == "" {
+			return fmt.Errorf("trading.private_key is not configured")
+		}
+
+		signer, err := polymarket.NewOrderSigner(cfg.Trading.PrivateKey, cfg.Trading.ChainID, cfg.Trading.Exchange)
+		if err != nil {
+			return err
+		}
+
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		client := polymarket.NewClient(polymarket.Config{
+			APIKey:     cfg.Polymarket.APIKey,
+			APISecret:  cfg.Polymarket.APISecret,
+			Passphrase: cfg.Polymarket.Passphrase,
+		})
+
+		ctx := context.Background()
+		market, err := client.GetMarket(ctx, marketID)
+		if err != nil {
+			return fmt.Errorf("failed to get market: %w", err)
+		}
+		var tokenID string
+		for _, t := range market.Tokens {
+			if strings.EqualFold(t.Outcome, orderOutcome) {
+				tokenID = t.TokenID
+			}
+		}
+		if tokenID == "" {
+			return fmt.Errorf("market %s has no %q outcome", marketID, orderOutcome)
+		}
+
+		manager := polymarket.NewOrderManager(client, database, signer)
+		manager.DryRun = orderDryRun
+		manager.Limits = polymarket.OrderLimits{
+			MaxMarketNotional: cfg.Trading.MaxMarketNotional,
+			MaxDailyNotional:  cfg.Trading.MaxDailyNotional,
+		}
+		if !orderYes {
+			reader := bufio.NewReader(cmd.InOrStdin())
+			manager.Confirm = func(req polymarket.OrderRequest) bool {
+				cmd.Printf("Submit %s %.2f %s @ %.3f ($%.2f) on %q? [y/N] ",
+					strings.ToUpper(req.Side), req.Size, orderOutcome, req.Price, req.Notional(), market.Question)
+				answer, _ := reader.ReadString('\n')
+				answer = strings.ToLower(strings.TrimSpace(answer))
+				return answer == "y" || answer == "yes"
+			}
+		}
+
+		res, err := manager.PlaceOrder(ctx, polymarket.OrderRequest{
+			MarketID: marketID,
+			TokenID:  tokenID,
+			Side:     orderSide,
+			Price:    orderPrice,
+			Size:     orderSize,
+		})
+		if errors.Is(err, polymarket.ErrOrderCancelled) {
+			cmd.Println("Order cancelled.")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("order failed: %w", err)
+		}
+
+		if res.Record.Status == db.OrderStatusDryRun {
+			signed, _ := json.MarshalIndent(res.Order, "", "  ")
+			cmd.Printf("Dry run: signed order (not sent):\n%s\n", signed)
+			return nil
+		}
+		cmd.Printf("Order submitted: %s\n", res.Record.ExchangeOrderID)
+		return nil
+	},
+}
+
+func init() {
+	orderCmd.Flags().StringVar(&orderSide, "side", polymarket.OrderSideBuy, "Order side (BUY, SELL)")
+	orderCmd.Flags().StringVar(&orderOutcome, "outcome", "Yes", "Outcome to trade (Yes, No)")
+	orderCmd.Flags().Float64Var(&orderPrice, "price", 0, "Limit price (0-1)")
+	orderCmd.Flags().Float64Var(&orderSize, "size", 0, "Number of outcome tokens")
+	orderCmd.Flags().BoolVar(&orderDryRun, "dry-run", true, "Sign and record the order without sending it")
+	orderCmd.Flags().BoolVarP(&orderYes, "yes", "y", false, "Skip the confirmation prompt for live orders")
+	_ = orderCmd.MarkFlagRequired("price")
+	_ = orderCmd.MarkFlagRequired("size")
+	rootCmd.AddCommand(orderCmd)
+}
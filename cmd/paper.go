@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"polytracker/internal/db"
+	"polytracker/internal/paper"
+	"polytracker/internal/polymarket"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	paperMode        string
+	paperAmount      float64
+	paperMaxPosition float64
+	paperResetCash   float64
+)
+
+var paperCmd = &cobra.Command{
+	Use:   "paper",
+	Short: "Show the paper portfolio that mirrors watchlisted traders",
+	Long: `Show the paper portfolio marked to the latest market snapshots. The portfolio
+copies trades made by watchlisted traders after they were added; each scan
+refreshes their history and mirrors anything new.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		v, err := newPortfolio(database).Value()
+		if err != nil {
+			return fmt.Errorf("failed to value portfolio: %w", err)
+		}
+
+		cmd.Printf("%-12s $%.2f\n", "Cash:", v.Cash)
+		cmd.Printf("%-12s $%.2f\n", "Positions:", v.MarketValue)
+		cmd.Printf("%-12s $%.2f\n", "Equity:", v.Equity)
+		cmd.Printf("%-12s %+.2f (realized %+.2f, unrealized %+.2f)\n", "P&L:", v.TotalPnL, v.RealizedPnL, v.UnrealizedPnL)
+
+		if len(v.Holdings) == 0 {
+			cmd.Println("\nNo open positions.")
+			return nil
+		}
+		cmd.Printf("\n%-44s %-4s %10s %7s %7s %10s\n", "Market", "Side", "Shares", "Avg", "Mark", "P&L")
+		for _, h := range v.Holdings {
+			market := h.Question
+			if market == "" {
+				market = h.MarketID
+			}
+			if len(market) > 44 {
+				market = market[:41] + "..."
+			}
+			cmd.Printf("%-44s %-4s %10.2f %7.3f %7.3f %+10.2f\n",
+				market, h.Side, h.Shares, h.CostBasis/h.Shares, h.Mark, h.UnrealizedPnL)
+		}
+		return nil
+	},
+}
+
+var paperAllocateCmd = &cobra.Command{
+	Use:   "allocate [address]",
+	Short: "Set how much of a watchlisted trader's buys the paper portfolio copies",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if paperMode != db.AllocationFixed && paperMode != db.AllocationProportional {
+			return fmt.Errorf("unknown allocation mode %q (want fixed or proportional)", paperMode)
+		}
+
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		alloc := &db.PaperAllocation{
+			TraderID:    args[0],
+			Mode:        paperMode,
+			Amount:      paperAmount,
+			MaxPosition: paperMaxPosition,
+		}
+		if err := database.SavePaperAllocation(alloc); err != nil {
+			return err
+		}
+		cmd.Printf("Allocation for %s set to %s %.2f.\n", args[0], paperMode, paperAmount)
+		return nil
+	},
+}
+
+var paperResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear the paper portfolio and start again with fresh cash",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		cash := paperResetCash
+		if cash <= 0 {
+			cash = cfg.Paper.StartingCash
+		}
+		if err := database.ResetPaperPortfolio(cash); err != nil {
+			return err
+		}
+		cmd.Printf("Paper portfolio reset with $%.2f.\n", cash)
+		return nil
+	},
+}
+
+func newPortfolio(database *db.DB) *paper.Portfolio {
+	p := paper.NewPortfolio(database)
+	if cfg.Paper.StartingCash > 0 {
+		p.StartingCash = cfg.Paper.StartingCash
+	}
+	if cfg.Paper.Amount > 0 {
+		p.DefaultAllocation.Amount = cfg.Paper.Amount
+	}
+	return p
+}
+
+// syncPaperPortfolio refreshes every watchlisted trader's history and mirrors
+// their new trades, returning how many were copied.
+func syncPaperPortfolio(ctx context.Context, client *polymarket.Client, database *db.DB) (int, error) {
+	if err := refreshWatchlist(ctx, client, database); err != nil {
+		return 0, err
+	}
+	return newPortfolio(database).Sync()
+}
+
+// refreshWatchlist refetches the trade history of every watchlisted trader.
+func refreshWatchlist(ctx context.Context, client *polymarket.Client, database *db.DB) error {
+	items, err := database.ListWatchlist()
+	if err != nil {
+		return err
+	}
+
+	fetcher := polymarket.NewFetcher(client, database)
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fetcher.FetchTraderHistory(ctx, item.TraderID); err != nil {
+			log.Printf("Warning: failed to refresh %s: %v", item.TraderID, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	paperAllocateCmd.Flags().StringVar(&paperMode, "mode", db.AllocationFixed, "Allocation mode (fixed, proportional)")
+	paperAllocateCmd.Flags().Float64Var(&paperAmount, "amount", paper.DefaultAmount, "Dollars per buy (fixed) or share of the trader's notional (proportional)")
+	paperAllocateCmd.Flags().Float64Var(&paperMaxPosition, "max-position", 0, "Maximum cost basis per market outcome (0 for no cap)")
+	paperResetCmd.Flags().Float64Var(&paperResetCash, "cash", 0, "Starting cash (defaults to paper.starting_cash)")
+	paperCmd.AddCommand(paperAllocateCmd, paperResetCmd)
+	rootCmd.AddCommand(paperCmd)
+}
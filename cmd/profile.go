@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile [address]",
+	Short: "Compute behavioral profiles (holding period, timing, sizing) for traders",
+	Long: `Compute behavioral statistics from stored trades: median holding period,
+share of entries in the final 24h before market end, average entry size as a
+share of market volume, scaling-in vs one-shot entries, and momentum vs
+contrarian entries. Profiles all traders when no address is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		profiler := analytics.NewProfiler(database)
+
+		if len(args) == 0 {
+			cmd.Println("Profiling all traders...")
+			count, err := profiler.ProfileAll()
+			if err != nil {
+				return fmt.Errorf("profiling failed: %w", err)
+			}
+			cmd.Printf("Profiled %d traders with trade history.\n", count)
+			return nil
+		}
+
+		profile, err := profiler.ProfileTrader(args[0])
+		if err != nil {
+			return fmt.Errorf("profiling failed: %w", err)
+		}
+
+		cmd.Printf("Behavioral profile for %s\n\n", args[0])
+		cmd.Printf("  Entries:              %d\n", profile.Entries)
+		cmd.Printf("  Median holding:       %.1fh\n", profile.MedianHoldingHours)
+		cmd.Printf("  Late entries (<24h):  %.1f%%\n", profile.LateEntryRatio*100)
+		cmd.Printf("  Avg size / mkt vol:   %.2f%%\n", profile.AvgSizeShare*100)
+		cmd.Printf("  Scale-in markets:     %.1f%%\n", profile.ScaleInRatio*100)
+		cmd.Printf("  Momentum entries:     %.1f%%\n", profile.MomentumRatio*100)
+		cmd.Printf("  Contrarian entries:   %.1f%%\n", profile.ContrarianRatio*100)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+}
@@ -2,7 +2,22 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/config"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
 )
 
 func TestRootCommand(t *testing.T) {
@@ -30,6 +45,7 @@ func TestSubcommands(t *testing.T) {
 		{[]string{"scan"}, "Scanning Polymarket for recent activity..."},
 		{[]string{"analyze", "0x123"}, "Fetching history for trader: 0x123"},
 		{[]string{"export"}, "Exporting leaderboard to CSV..."},
+		{[]string{"usage", "--by", "model"}, "No Claude usage recorded."},
 	}
 
 	for _, tc := range cases {
@@ -48,6 +64,292 @@ func TestSubcommands(t *testing.T) {
 	}
 }
 
+func TestCollectBatch_Interrupted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msgbatch_1","type":"message_batch","processing_status":"in_progress","request_counts":{"processing":1}}`)
+	}))
+	defer server.Close()
+
+	dbPath := "test_collect_batch_interrupted.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	batch := &db.AnalysisBatch{ID: "msgbatch_1", Source: batchSourceTop, Model: "claude-test",
+		Items: []db.AnalysisBatchItem{{TraderID: "0xa", Prompt: "prompt a"}}}
+	if err := database.SaveAnalysisBatch(batch); err != nil {
+		t.Fatalf("failed to save batch: %v", err)
+	}
+	client, err := claude.NewClient(claude.Config{APIKey: "test-api-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The interrupt arrives while waiting between polls.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	cmd := &cobra.Command{}
+	out := bytes.NewBufferString("")
+	cmd.SetOut(out)
+	if err := collectBatch(ctx, cmd, database, client, batch); err != nil {
+		t.Fatalf("expected an interrupted collection to exit cleanly, got %v", err)
+	}
+	if !contains(out.String(), "--resume-batches") {
+		t.Errorf("expected the resume hint, got %q", out.String())
+	}
+
+	open, err := database.ListOpenAnalysisBatches()
+	if err != nil {
+		t.Fatalf("failed to list batches: %v", err)
+	}
+	if len(open) != 1 || open[0].Items[0].Status != db.BatchItemPending {
+		t.Errorf("expected the batch left open for resuming, got %+v", open)
+	}
+}
+
+// withClaudeStub points the global config at dbPath and a stub Messages API
+// that answers every request with reply, restoring the config when the test ends.
+func withClaudeStub(t *testing.T, dbPath, reply string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":%q}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":500,"output_tokens":50}}`, reply)
+	}))
+	t.Cleanup(server.Close)
+
+	saved := cfg
+	cfg = &config.Config{}
+	cfg.Database.Path = dbPath
+	cfg.Claude.APIKey = "test-api-key"
+	cfg.Claude.Endpoint = server.URL
+	cfg.Claude.Model = "claude-test"
+	t.Cleanup(func() { cfg = saved })
+}
+
+func TestCompareCommand(t *testing.T) {
+	dbPath := "test_compare_command.db"
+	defer os.Remove(dbPath)
+	exportPath := filepath.Join("exports", "test_compare_command.md")
+	defer os.Remove("exports")
+	defer os.Remove(exportPath)
+
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+	for _, address := range []string{"0xa", "0xb"} {
+		if err := database.SaveTrader(&db.Trader{Address: address, Volume: 1000}); err != nil {
+			t.Fatalf("failed to save trader: %v", err)
+		}
+	}
+
+	withClaudeStub(t, dbPath, "0xa is the steadier trader.")
+	compareSkipFetch, compareFilename = true, "test_compare_command"
+	defer func() { compareSkipFetch, compareFilename = false, "" }()
+
+	out := bytes.NewBufferString("")
+	compareCmd.SetOut(out)
+	defer compareCmd.SetOut(nil)
+	if err := compareCmd.RunE(compareCmd, []string{"0xa", "0xb"}); err != nil {
+		t.Fatalf("compare failed: %v", err)
+	}
+	if !contains(out.String(), "Exported comparison to: "+exportPath) {
+		t.Errorf("expected the export path in the output, got %q", out.String())
+	}
+
+	for _, address := range []string{"0xa", "0xb"} {
+		comparisons, err := database.ListComparisonsByTrader(address)
+		if err != nil {
+			t.Fatalf("failed to list comparisons: %v", err)
+		}
+		if len(comparisons) != 1 || comparisons[0].Thesis != "0xa is the steadier trader." || comparisons[0].Model != "claude-test" {
+			t.Errorf("expected the comparison saved for %s, got %+v", address, comparisons)
+		}
+	}
+	if _, err := os.Stat(exportPath); err != nil {
+		t.Errorf("expected the comparison exported: %v", err)
+	}
+}
+
+func TestAnalyzeMarketCommand(t *testing.T) {
+	dbPath := "test_analyze_market_command.db"
+	defer os.Remove(dbPath)
+
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+	if err := database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain?", Status: "active"}); err != nil {
+		t.Fatalf("failed to save market: %v", err)
+	}
+	if err := database.SaveTrade(&db.Trade{ID: "t1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.6, Size: 100, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("failed to save trade: %v", err)
+	}
+
+	withClaudeStub(t, dbPath, "Smart money is long YES.")
+	marketSkipFetch = true
+	defer func() { marketSkipFetch = false }()
+
+	out := bytes.NewBufferString("")
+	analyzeMarketCmd.SetOut(out)
+	defer analyzeMarketCmd.SetOut(nil)
+	if err := analyzeMarketCmd.RunE(analyzeMarketCmd, []string{"m1"}); err != nil {
+		t.Fatalf("analyze-market failed: %v", err)
+	}
+	if !contains(out.String(), "Stored trades: 1") {
+		t.Errorf("expected the stored trade counted, got %q", out.String())
+	}
+
+	analysis, err := database.GetMarketAnalysisByMarket("m1")
+	if err != nil || analysis == nil || analysis.Thesis != "Smart money is long YES." || analysis.Model != "claude-test" {
+		t.Errorf("expected the market analysis saved, got %+v, %v", analysis, err)
+	}
+
+	if err := analyzeMarketCmd.RunE(analyzeMarketCmd, []string{"m-unknown"}); err == nil || !contains(err.Error(), "market not found") {
+		t.Errorf("expected an unknown market to fail, got %v", err)
+	}
+}
+
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
 }
+
+func TestChatLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range []string{
+			`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude-test","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":10,"output_tokens":1}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Mostly sports."}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":3}}`,
+			`{"type":"message_stop"}`,
+		} {
+			var event struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal([]byte(e), &event)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, e)
+		}
+	}))
+	defer server.Close()
+
+	dbPath := "test_chat_loop.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	analysis := &db.Analysis{TraderID: "0xabc", Thesis: "the thesis", Prompt: "the prompt"}
+	if err := database.SaveAnalysis(analysis); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+	client, err := claude.NewClient(claude.Config{APIKey: "test-api-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	out := bytes.NewBufferString("")
+	cmd.SetOut(out)
+	cmd.SetIn(strings.NewReader("What do they trade?\n\nexit\nnever asked\n"))
+	if err := chatLoop(cmd, database, client, analysis, nil); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if !contains(out.String(), "Mostly sports.") {
+		t.Errorf("expected the reply in the output, got %q", out.String())
+	}
+
+	messages, err := database.GetAnalysisMessages(analysis.ID)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Content != "What do they trade?" || messages[1].Content != "Mostly sports." {
+		t.Errorf("expected one saved question and reply, got %+v", messages)
+	}
+}
+
+func TestCollectBatch(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "in_progress"
+		if polls > 1 {
+			status = "ended"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"msgbatch_1","type":"message_batch","processing_status":%q,"request_counts":{"processing":1}}`, status)
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1/results", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-jsonl")
+		for _, address := range []string{"0xa", "0xb"} {
+			fmt.Fprintf(w, `{"custom_id":%q,"result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"Thesis for %s."}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":5}}}}`+"\n", address, address)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dbPath := "test_collect_batch.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	batch := &db.AnalysisBatch{ID: "msgbatch_1", Source: batchSourceTop, Model: "claude-test",
+		Items: []db.AnalysisBatchItem{{TraderID: "0xa", Prompt: "prompt a"}, {TraderID: "0xb", Prompt: "prompt b"}, {TraderID: "0xc", Prompt: "prompt c"}}}
+	if err := database.SaveAnalysisBatch(batch); err != nil {
+		t.Fatalf("failed to save batch: %v", err)
+	}
+	// An earlier, interrupted collection already saved 0xb.
+	if err := database.SaveBatchAnalysis(batch.ID, &db.Analysis{TraderID: "0xb", Thesis: "earlier"}); err != nil {
+		t.Fatalf("failed to save batch analysis: %v", err)
+	}
+	batch, err = database.GetAnalysisBatch(batch.ID)
+	if err != nil {
+		t.Fatalf("failed to get batch: %v", err)
+	}
+
+	client, err := claude.NewClient(claude.Config{APIKey: "test-api-key", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	analyzePollInterval = time.Millisecond
+	defer func() { analyzePollInterval = 30 * time.Second }()
+
+	cmd := &cobra.Command{}
+	out := bytes.NewBufferString("")
+	cmd.SetOut(out)
+	if err := collectBatch(context.Background(), cmd, database, client, batch); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if polls != 2 || !contains(out.String(), "1 saved, 1 failed") {
+		t.Errorf("expected one poll before the batch ended and a summary, got %d polls and %q", polls, out.String())
+	}
+
+	analysis, err := database.GetAnalysisByTrader("0xa")
+	if err != nil || analysis == nil || analysis.Thesis != "Thesis for 0xa." || analysis.Prompt != "prompt a" {
+		t.Errorf("expected 0xa's analysis saved with its prompt, got %+v, %v", analysis, err)
+	}
+	if all, _ := database.GetAllAnalysesByTrader("0xb"); len(all) != 1 || all[0].Thesis != "earlier" {
+		t.Errorf("expected 0xb's earlier analysis kept, got %+v", all)
+	}
+
+	collected, err := database.GetAnalysisBatch(batch.ID)
+	if err != nil {
+		t.Fatalf("failed to get batch: %v", err)
+	}
+	if collected.Status != db.BatchStatusCollected || collected.Items[2].Status != db.BatchItemFailed {
+		t.Errorf("expected a collected batch with 0xc failed, got %+v", collected)
+	}
+}
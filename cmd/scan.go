@@ -9,6 +9,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	scanExcludeTypes []string
+	scanPaper        bool
+	scanAlerts       bool
+)
+
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Scan Polymarket for high-performing traders",
@@ -26,6 +32,9 @@ var scanCmd = &cobra.Command{
 		})
 
 		scanner := polymarket.NewScanner(client, database)
+		if len(scanExcludeTypes) > 0 {
+			scanner.SetExcludedTypes(scanExcludeTypes)
+		}
 		
 		cmd.Println("Scanning Polymarket for recent activity...")
 		// Use a default limit or a flag if implemented
@@ -34,10 +43,29 @@ var scanCmd = &cobra.Command{
 			return fmt.Errorf("scan failed: %w", err)
 		}
 		cmd.Println("Scan complete.")
+
+		if scanPaper {
+			copied, err := syncPaperPortfolio(context.Background(), client, database)
+			if err != nil {
+				return fmt.Errorf("paper sync failed: %w", err)
+			}
+			if copied > 0 {
+				cmd.Printf("Mirrored %d new watchlist trade(s) into the paper portfolio.\n", copied)
+			}
+		}
+
+		if scanAlerts {
+			if err := evaluateAlerts(context.Background(), database, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
 
 func init() {
+	scanCmd.Flags().StringSliceVar(&scanExcludeTypes, "exclude-type", nil, "Skip traders classified as these types (market_maker, bot, directional)")
+	scanCmd.Flags().BoolVar(&scanPaper, "paper", true, "Refresh watchlisted traders and mirror their new trades into the paper portfolio")
+	scanCmd.Flags().BoolVar(&scanAlerts, "alerts", true, "Evaluate alert rules against watchlisted traders")
 	rootCmd.AddCommand(scanCmd)
 }
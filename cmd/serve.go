@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"polytracker/internal/api"
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the database as a local JSON REST API",
+	Long: `Serve traders, trades, markets, snapshots, analyses and the watchlist over HTTP.
+
+  GET    /api/traders?sort=profit_loss&order=desc&limit=50&offset=0&type=&category=
+  GET    /api/traders/{address}
+  GET    /api/traders/{address}/trades
+  GET    /api/traders/{address}/analyses
+  POST   /api/traders/{address}/analyses   run a Claude analysis (needs claude.api_key)
+  GET    /api/markets?q=&status=
+  GET    /api/markets/{id}
+  GET    /api/markets/{id}/snapshots
+  GET    /api/analyses
+  GET    /api/watchlist
+  PUT    /api/watchlist/{address}          body: {"notes": "..."}
+  DELETE /api/watchlist/{address}
+  GET    /metrics                          Prometheus metrics
+
+List endpoints accept limit and offset and return {"data": [...], "pagination": {...}}.
+When server.token (or --token) is set, requests must send "Authorization: Bearer <token>".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		server := api.NewServer(database)
+		server.Token = cfg.Server.Token
+		if serveToken != "" {
+			server.Token = serveToken
+		}
+		if cfg.Claude.APIKey != "" {
+			claudeClient, err := newClaudeClient("", "")
+			if err != nil {
+				return err
+			}
+			server.Analyzer = claudeClient
+		}
+
+		addr := cfg.Server.Addr
+		if serveAddr != "" {
+			addr = serveAddr
+		}
+		httpServer := &http.Server{
+			Addr:              addr,
+			Handler:           server.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- httpServer.ListenAndServe()
+		}()
+		log.Printf("Serving API on http://%s", addr)
+		if server.Token == "" {
+			log.Printf("Warning: no bearer token configured; the API is unauthenticated")
+		}
+
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "", "Listen address (defaults to server.addr)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required on every request (defaults to server.token)")
+	rootCmd.AddCommand(serveCmd)
+}
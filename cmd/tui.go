@@ -23,10 +23,7 @@ var tuiCmd = &cobra.Command{
 		// Try to create a Claude client if API key is configured
 		var claudeClient *claude.Client
 		if cfg.Claude.APIKey != "" {
-			claudeClient, err = claude.NewClient(claude.Config{
-				APIKey:   cfg.Claude.APIKey,
-				Endpoint: cfg.Claude.Endpoint,
-			})
+			claudeClient, err = newClaudeClient("", "")
 			if err != nil {
 				// Log warning but don't fail - analysis just won't be available
 				log.Printf("Warning: Could not initialize Claude client: %v", err)
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	usageBy   string
+	usageDays int
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report Claude token usage and spend",
+	Long: `Report the tokens and USD cost of Claude requests - trader analyses, follow-up
+chat replies, comparisons and market analyses - grouped by day, model or trader.
+Grouping by trader counts only analyses and their chat replies. Costs are
+computed when each request runs, from the per-model prices under claude.prices;
+requests from before costs were recorded count as zero.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.NewDB(cfg.Database.Path)
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		defer database.Close()
+
+		var since time.Time
+		if usageDays > 0 {
+			since = time.Now().AddDate(0, 0, -usageDays)
+		}
+		rows, err := database.AnalysisUsage(usageBy, since)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			cmd.Println("No Claude usage recorded.")
+			return nil
+		}
+
+		cmd.Printf("%-44s %8s %12s %12s %10s\n", usageBy, "requests", "input", "output", "cost")
+		var total db.UsageRow
+		for _, r := range rows {
+			key := r.Key
+			if key == "" {
+				key = "(unknown)"
+			}
+			cmd.Printf("%-44s %8d %12d %12d %10s\n", key, r.Requests, r.InputTokens, r.OutputTokens, fmt.Sprintf("$%.4f", r.CostUSD))
+			total.Requests += r.Requests
+			total.InputTokens += r.InputTokens
+			total.OutputTokens += r.OutputTokens
+			total.CostUSD += r.CostUSD
+		}
+		cmd.Printf("%-44s %8d %12d %12d %10s\n", "total", total.Requests, total.InputTokens, total.OutputTokens, fmt.Sprintf("$%.4f", total.CostUSD))
+		return nil
+	},
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageBy, "by", db.UsageByDay, "Group usage by day, model or trader")
+	usageCmd.Flags().IntVar(&usageDays, "days", 30, "Only count requests from the last N days (0 for all time)")
+	rootCmd.AddCommand(usageCmd)
+}
@@ -0,0 +1,235 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+)
+
+// Rule types understood by the engine.
+const (
+	// RuleLargePosition fires when a watched trader buys more than Threshold dollars in one trade.
+	RuleLargePosition = "large_position"
+	// RuleNewMarket fires when a watched trader trades a market for the first time.
+	RuleNewMarket = "new_market"
+	// RulePnLBelow fires when a watched trader's P&L is below Threshold dollars.
+	RulePnLBelow = "pnl_below"
+	// RuleRankChange fires when a watched trader moves at least Threshold places on the P&L leaderboard.
+	RuleRankChange = "rank_change"
+)
+
+// Rule is one configured alert condition.
+type Rule struct {
+	Type      string
+	Threshold float64
+}
+
+// DefaultRules apply when no rules are configured.
+var DefaultRules = []Rule{
+	{Type: RuleLargePosition, Threshold: 1000},
+	{Type: RuleNewMarket},
+	{Type: RulePnLBelow, Threshold: 0},
+	{Type: RuleRankChange, Threshold: 5},
+}
+
+// ValidateRules reports the first rule with an unknown type.
+func ValidateRules(rules []Rule) error {
+	for _, r := range rules {
+		switch r.Type {
+		case RuleLargePosition, RuleNewMarket, RulePnLBelow, RuleRankChange:
+		default:
+			return fmt.Errorf("unknown alert rule type %q", r.Type)
+		}
+	}
+	return nil
+}
+
+// Engine evaluates alert rules against watchlisted traders.
+type Engine struct {
+	db    *db.DB
+	rules []Rule
+}
+
+func NewEngine(database *db.DB, rules []Rule) *Engine {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	return &Engine{
+		db:    database,
+		rules: rules,
+	}
+}
+
+// Evaluate checks every rule against the watchlist and stores alerts that have
+// not fired before. It returns only the newly fired alerts.
+func (e *Engine) Evaluate() ([]db.Alert, error) {
+	items, err := e.db.ListWatchlist()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []db.Alert
+	rankChanges, ranks, err := e.rankAlerts(items)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, rankChanges...)
+
+	for _, item := range items {
+		trader, err := e.db.GetTrader(item.TraderID)
+		if err != nil {
+			return nil, err
+		}
+		trades, err := e.db.GetTradesByTrader(item.TraderID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range e.rules {
+			switch r.Type {
+			case RuleLargePosition:
+				candidates = append(candidates, largePositionAlerts(r, item, trades)...)
+			case RuleNewMarket:
+				candidates = append(candidates, newMarketAlerts(item, trades)...)
+			case RulePnLBelow:
+				if trader != nil && trader.ProfitLoss < r.Threshold {
+					candidates = append(candidates, db.Alert{
+						Rule:     r.Type,
+						TraderID: item.TraderID,
+						Message:  fmt.Sprintf("%s P&L is $%.2f, below $%.2f", item.TraderID, trader.ProfitLoss, r.Threshold),
+						// Fires at most once a day while the P&L stays below the threshold.
+						DedupKey: fmt.Sprintf("%s:%s:%.2f:%s", r.Type, item.TraderID, r.Threshold, time.Now().Format("2006-01-02")),
+					})
+				}
+			}
+		}
+	}
+
+	var fired []db.Alert
+	for _, a := range candidates {
+		saved, err := e.db.SaveAlert(&a)
+		if err != nil {
+			return fired, err
+		}
+		if saved {
+			fired = append(fired, a)
+		}
+	}
+
+	// Ranks are stored only once their alerts are, so a failed evaluation
+	// reports the same moves when it is retried.
+	if ranks != nil {
+		if err := e.db.ReplaceTraderRanks(ranks); err != nil {
+			return fired, err
+		}
+	}
+	return fired, nil
+}
+
+// largePositionAlerts flags buys above the threshold made since the trader was watched.
+func largePositionAlerts(r Rule, item db.WatchlistItem, trades []db.Trade) []db.Alert {
+	var alerts []db.Alert
+	for _, t := range trades {
+		notional := t.Price * t.Size
+		if t.Timestamp.Before(item.CreatedAt) || !analytics.IsBuy(t) || notional <= r.Threshold {
+			continue
+		}
+		alerts = append(alerts, db.Alert{
+			Rule:     r.Type,
+			TraderID: t.TraderID,
+			MarketID: t.MarketID,
+			Message:  fmt.Sprintf("%s bought $%.2f of %s in %s @ %.3f", t.TraderID, notional, t.Side, t.MarketID, t.Price),
+			DedupKey: fmt.Sprintf("%s:%.2f:%s", r.Type, r.Threshold, t.ID),
+		})
+	}
+	return alerts
+}
+
+// newMarketAlerts flags the first trade in each market made since the trader was watched.
+func newMarketAlerts(item db.WatchlistItem, trades []db.Trade) []db.Alert {
+	first := make(map[string]db.Trade)
+	for _, t := range trades {
+		if f, ok := first[t.MarketID]; !ok || t.Timestamp.Before(f.Timestamp) {
+			first[t.MarketID] = t
+		}
+	}
+
+	var alerts []db.Alert
+	for marketID, t := range first {
+		if t.Timestamp.Before(item.CreatedAt) {
+			continue
+		}
+		alerts = append(alerts, db.Alert{
+			Rule:     RuleNewMarket,
+			TraderID: t.TraderID,
+			MarketID: marketID,
+			Message:  fmt.Sprintf("%s entered a new market: %s", t.TraderID, marketID),
+			DedupKey: fmt.Sprintf("%s:%s:%s", RuleNewMarket, t.TraderID, marketID),
+		})
+	}
+	return alerts
+}
+
+// rankAlerts compares the P&L leaderboard with the ranks stored on the previous
+// evaluation. It returns the current ranks for the caller to store, or nil when
+// no rank rule is configured.
+func (e *Engine) rankAlerts(items []db.WatchlistItem) ([]db.Alert, []db.TraderRank, error) {
+	var rule *Rule
+	for i := range e.rules {
+		if e.rules[i].Type == RuleRankChange {
+			rule = &e.rules[i]
+		}
+	}
+	if rule == nil {
+		return nil, nil, nil
+	}
+
+	previous, err := e.db.GetTraderRanks()
+	if err != nil {
+		return nil, nil, err
+	}
+	traders, err := e.db.ListTradersWithOptions(db.ListTradersOptions{SortBy: db.SortByProfitLoss, Order: db.SortDesc})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	current := make(map[string]int, len(traders))
+	ranks := make([]db.TraderRank, len(traders))
+	for i, t := range traders {
+		current[t.Address] = i + 1
+		ranks[i] = db.TraderRank{TraderID: t.Address, Rank: i + 1, UpdatedAt: now}
+	}
+
+	var alerts []db.Alert
+	for _, item := range items {
+		prev, hadRank := previous[item.TraderID]
+		before := prev.Rank
+		after, hasRank := current[item.TraderID]
+		if !hadRank || !hasRank {
+			continue
+		}
+		moved := before - after
+		if moved < 0 {
+			moved = -moved
+		}
+		if float64(moved) < rule.Threshold || moved == 0 {
+			continue
+		}
+		direction := "up"
+		if after > before {
+			direction = "down"
+		}
+		alerts = append(alerts, db.Alert{
+			Rule:     RuleRankChange,
+			TraderID: item.TraderID,
+			Message:  fmt.Sprintf("%s moved %s %d places to #%d", item.TraderID, direction, moved, after),
+			// Keyed by when the previous ranks were recorded, so the same move
+			// fires again after a later evaluation but not on a retry.
+			DedupKey: fmt.Sprintf("%s:%s:%d:%d:%d", RuleRankChange, item.TraderID, before, after, prev.UpdatedAt.UnixNano()),
+		})
+	}
+	return alerts, ranks, nil
+}
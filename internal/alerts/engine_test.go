@@ -0,0 +1,131 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rulesFired(alerts []db.Alert) map[string]int {
+	counts := make(map[string]int)
+	for _, a := range alerts {
+		counts[a.Rule]++
+	}
+	return counts
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	dbPath := "test_alerts.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xa", ProfitLoss: -50, LastScanned: time.Now()}))
+	past := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "old", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 5000, Timestamp: past,
+	}))
+	require.NoError(t, database.AddToWatchlist("0xa", ""))
+
+	now := time.Now().Add(time.Minute)
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "big", TraderID: "0xa", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.5, Size: 4000, Timestamp: now,
+	}))
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "small", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.5, Size: 10, Timestamp: now,
+	}))
+
+	engine := NewEngine(database, []Rule{
+		{Type: RuleLargePosition, Threshold: 1000},
+		{Type: RuleNewMarket},
+		{Type: RulePnLBelow, Threshold: 0},
+	})
+
+	fired, err := engine.Evaluate()
+	require.NoError(t, err)
+	counts := rulesFired(fired)
+	// Only the post-watch $2000 buy is large, and only m2 is a new market (m1 was traded before).
+	assert.Equal(t, 1, counts[RuleLargePosition])
+	assert.Equal(t, 1, counts[RuleNewMarket])
+	assert.Equal(t, 1, counts[RulePnLBelow])
+
+	// Re-evaluating fires nothing new.
+	fired, err = engine.Evaluate()
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	// A rule with another threshold fires on its own.
+	fired, err = NewEngine(database, []Rule{{Type: RuleLargePosition, Threshold: 500}}).Evaluate()
+	require.NoError(t, err)
+	assert.Equal(t, 1, rulesFired(fired)[RuleLargePosition])
+
+	unread, err := database.CountUnreadAlerts()
+	require.NoError(t, err)
+	assert.Equal(t, 4, unread)
+
+	require.NoError(t, database.MarkAlertsRead())
+	unread, err = database.CountUnreadAlerts()
+	require.NoError(t, err)
+	assert.Equal(t, 0, unread)
+}
+
+func TestEngine_RankChange(t *testing.T) {
+	dbPath := "test_alerts_rank.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, database.SaveTrader(&db.Trader{
+			Address: fmt.Sprintf("0x%d", i), ProfitLoss: float64(100 - i), LastScanned: time.Now(),
+		}))
+	}
+	require.NoError(t, database.AddToWatchlist("0x9", ""))
+
+	engine := NewEngine(database, []Rule{{Type: RuleRankChange, Threshold: 5}})
+
+	// The first evaluation only records ranks.
+	fired, err := engine.Evaluate()
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	// 0x9 jumps from #10 to #1.
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0x9", ProfitLoss: 1000, LastScanned: time.Now()}))
+	fired, err = engine.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	assert.Equal(t, RuleRankChange, fired[0].Rule)
+	assert.Contains(t, fired[0].Message, "up 9 places to #1")
+
+	// Small moves stay quiet.
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0x9", ProfitLoss: 97.5, LastScanned: time.Now()}))
+	fired, err = engine.Evaluate()
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	// Falling back to #10 and jumping to #1 again fires each move.
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0x9", ProfitLoss: 0, LastScanned: time.Now()}))
+	fired, err = engine.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	assert.Contains(t, fired[0].Message, "down 6 places to #10")
+
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0x9", ProfitLoss: 1000, LastScanned: time.Now()}))
+	fired, err = engine.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, fired, 1, "a repeated move is a new alert")
+	assert.Contains(t, fired[0].Message, "up 9 places to #1")
+}
+
+func TestValidateRules(t *testing.T) {
+	assert.NoError(t, ValidateRules(DefaultRules))
+	assert.Error(t, ValidateRules([]Rule{{Type: "price_spike"}}))
+}
@@ -0,0 +1,255 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+const (
+	// lateEntryWindow is how close to market end an entry must be to count as late.
+	lateEntryWindow = 24 * time.Hour
+	// priorMoveLookback is how far back we look to measure the price move before an entry.
+	priorMoveLookback = 24 * time.Hour
+	// minPriceMove is the smallest prior move treated as a trend rather than noise.
+	minPriceMove = 0.01
+)
+
+// Profiler computes behavioral profiles for traders from stored trade history.
+type Profiler struct {
+	db *db.DB
+}
+
+func NewProfiler(database *db.DB) *Profiler {
+	return &Profiler{db: database}
+}
+
+// ProfileTrader computes and persists the behavioral profile for a single trader.
+func (p *Profiler) ProfileTrader(address string) (*db.TraderProfile, error) {
+	trades, err := p.db.GetTradesByTrader(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	markets := make(map[string]*db.Market)
+	marketTrades := make(map[string][]db.Trade)
+	for _, t := range trades {
+		if _, exists := markets[t.MarketID]; exists {
+			continue
+		}
+		market, err := p.db.GetMarket(t.MarketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get market %s: %w", t.MarketID, err)
+		}
+		markets[t.MarketID] = market
+
+		mt, err := p.db.GetTradesByMarket(t.MarketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trades for market %s: %w", t.MarketID, err)
+		}
+		marketTrades[t.MarketID] = mt
+	}
+
+	profile := ComputeProfile(address, trades, markets, marketTrades)
+	if err := p.db.SaveTraderProfile(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// ProfileAll profiles every stored trader and returns how many were updated.
+func (p *Profiler) ProfileAll() (int, error) {
+	traders, err := p.db.ListTraders()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, t := range traders {
+		profile, err := p.ProfileTrader(t.Address)
+		if err != nil {
+			return count, fmt.Errorf("failed to profile %s: %w", t.Address, err)
+		}
+		if profile.Entries > 0 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ComputeProfile derives behavioral statistics from a trader's trades. markets and
+// marketTrades are keyed by market ID; marketTrades holds every stored trade in the
+// market (from all traders) and is used for volume share and prior price moves.
+func ComputeProfile(traderID string, trades []db.Trade, markets map[string]*db.Market, marketTrades map[string][]db.Trade) *db.TraderProfile {
+	profile := &db.TraderProfile{
+		TraderID:  traderID,
+		UpdatedAt: time.Now(),
+	}
+
+	byMarket := make(map[string][]db.Trade)
+	for _, t := range trades {
+		byMarket[t.MarketID] = append(byMarket[t.MarketID], t)
+	}
+
+	var holdings []float64
+	var lateEntries, sizedEntries, momentum, contrarian int
+	var sizeShareSum float64
+	var enteredMarkets, scaledMarkets int
+
+	for marketID, mtrades := range byMarket {
+		sort.Slice(mtrades, func(i, j int) bool {
+			return mtrades[i].Timestamp.Before(mtrades[j].Timestamp)
+		})
+		market := markets[marketID]
+		volume := marketVolume(marketTrades[marketID])
+
+		buys := 0
+		for _, t := range mtrades {
+			if !IsBuy(t) {
+				continue
+			}
+			buys++
+
+			if market != nil && !market.EndsAt.IsZero() {
+				untilEnd := market.EndsAt.Sub(t.Timestamp)
+				if untilEnd >= 0 && untilEnd <= lateEntryWindow {
+					lateEntries++
+				}
+			}
+
+			if volume > 0 {
+				sizeShareSum += t.Price * t.Size / volume
+				sizedEntries++
+			}
+
+			if move, ok := priorMove(t, marketTrades[marketID]); ok {
+				if move >= minPriceMove {
+					momentum++
+				} else if move <= -minPriceMove {
+					contrarian++
+				}
+			}
+		}
+
+		if buys > 0 {
+			enteredMarkets++
+			if buys > 1 {
+				scaledMarkets++
+			}
+		}
+		profile.Entries += buys
+
+		holdings = append(holdings, holdingPeriods(mtrades, market)...)
+	}
+
+	if profile.Entries > 0 {
+		profile.LateEntryRatio = float64(lateEntries) / float64(profile.Entries)
+		profile.MomentumRatio = float64(momentum) / float64(profile.Entries)
+		profile.ContrarianRatio = float64(contrarian) / float64(profile.Entries)
+	}
+	if sizedEntries > 0 {
+		profile.AvgSizeShare = sizeShareSum / float64(sizedEntries)
+	}
+	if enteredMarkets > 0 {
+		profile.ScaleInRatio = float64(scaledMarkets) / float64(enteredMarkets)
+	}
+	profile.MedianHoldingHours = median(holdings)
+
+	return profile
+}
+
+// IsBuy reports whether a trade opens or adds to a position.
+func IsBuy(t db.Trade) bool {
+	return strings.EqualFold(t.Type, "buy")
+}
+
+// holdingPeriods matches sells against earlier buys first-in-first-out and returns
+// each holding period in hours. Lots still open when the market ended are held to
+// the market end time; lots in markets that are still running are ignored.
+func holdingPeriods(trades []db.Trade, market *db.Market) []float64 {
+	type lot struct {
+		opened time.Time
+		size   float64
+	}
+
+	var open []lot
+	var periods []float64
+	for _, t := range trades {
+		if IsBuy(t) {
+			open = append(open, lot{opened: t.Timestamp, size: t.Size})
+			continue
+		}
+
+		remaining := t.Size
+		for remaining > 0 && len(open) > 0 {
+			periods = append(periods, t.Timestamp.Sub(open[0].opened).Hours())
+			if open[0].size > remaining {
+				open[0].size -= remaining
+				remaining = 0
+			} else {
+				remaining -= open[0].size
+				open = open[1:]
+			}
+		}
+	}
+
+	if market != nil && !market.EndsAt.IsZero() && market.EndsAt.Before(time.Now()) {
+		for _, l := range open {
+			if market.EndsAt.After(l.opened) {
+				periods = append(periods, market.EndsAt.Sub(l.opened).Hours())
+			}
+		}
+	}
+
+	return periods
+}
+
+// priorMove returns the price change on the entry's side over priorMoveLookback,
+// measured from other stored trades in the same market.
+func priorMove(entry db.Trade, marketTrades []db.Trade) (float64, bool) {
+	var first, last *db.Trade
+	windowStart := entry.Timestamp.Add(-priorMoveLookback)
+	for i := range marketTrades {
+		t := &marketTrades[i]
+		if t.ID == entry.ID || !strings.EqualFold(t.Side, entry.Side) {
+			continue
+		}
+		if t.Timestamp.Before(windowStart) || !t.Timestamp.Before(entry.Timestamp) {
+			continue
+		}
+		if first == nil || t.Timestamp.Before(first.Timestamp) {
+			first = t
+		}
+		if last == nil || t.Timestamp.After(last.Timestamp) {
+			last = t
+		}
+	}
+	if first == nil || first == last {
+		return 0, false
+	}
+	return last.Price - first.Price, true
+}
+
+func marketVolume(trades []db.Trade) float64 {
+	var volume float64
+	for _, t := range trades {
+		volume += t.Price * t.Size
+	}
+	return volume
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
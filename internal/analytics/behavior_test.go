@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeProfile(t *testing.T) {
+	end := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	markets := map[string]*db.Market{
+		"m1": {ID: "m1", Question: "Market 1", EndsAt: end},
+		"m2": {ID: "m2", Question: "Market 2", EndsAt: end},
+	}
+
+	trades := []db.Trade{
+		// m1: scaled in twice, sold 48h after first entry
+		{ID: "a1", TraderID: "0xabc", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.60, Size: 100, Timestamp: end.Add(-96 * time.Hour)},
+		{ID: "a2", TraderID: "0xabc", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.62, Size: 100, Timestamp: end.Add(-72 * time.Hour)},
+		{ID: "a3", TraderID: "0xabc", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.70, Size: 200, Timestamp: end.Add(-48 * time.Hour)},
+		// m2: one-shot late entry held to market end
+		{ID: "a4", TraderID: "0xabc", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.30, Size: 100, Timestamp: end.Add(-12 * time.Hour)},
+	}
+
+	marketTrades := map[string][]db.Trade{
+		"m1": append([]db.Trade{
+			{ID: "o1", TraderID: "0xother", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.50, Size: 100, Timestamp: end.Add(-110 * time.Hour)},
+			{ID: "o2", TraderID: "0xother", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.58, Size: 100, Timestamp: end.Add(-100 * time.Hour)},
+		}, trades[:3]...),
+		"m2": {
+			{ID: "o3", TraderID: "0xother", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.45, Size: 100, Timestamp: end.Add(-20 * time.Hour)},
+			{ID: "o4", TraderID: "0xother", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.35, Size: 100, Timestamp: end.Add(-16 * time.Hour)},
+			trades[3],
+		},
+	}
+
+	profile := ComputeProfile("0xabc", trades, markets, marketTrades)
+
+	assert.Equal(t, "0xabc", profile.TraderID)
+	assert.Equal(t, 3, profile.Entries)
+	assert.InDelta(t, 1.0/3.0, profile.LateEntryRatio, 0.001)
+	assert.InDelta(t, 0.5, profile.ScaleInRatio, 0.001)
+	// a1 entered after a rise, a4 after a fall, a2 has too few prior trades in window
+	assert.InDelta(t, 1.0/3.0, profile.MomentumRatio, 0.001)
+	assert.InDelta(t, 1.0/3.0, profile.ContrarianRatio, 0.001)
+	// Holding periods: 48h, 24h (m1 sells) and 12h (m2 held to end)
+	assert.InDelta(t, 24.0, profile.MedianHoldingHours, 0.001)
+	assert.Greater(t, profile.AvgSizeShare, 0.0)
+	assert.Less(t, profile.AvgSizeShare, 1.0)
+}
+
+func TestComputeProfile_NoTrades(t *testing.T) {
+	profile := ComputeProfile("0xempty", nil, nil, nil)
+
+	assert.Equal(t, 0, profile.Entries)
+	assert.Equal(t, 0.0, profile.MedianHoldingHours)
+	assert.Equal(t, 0.0, profile.LateEntryRatio)
+}
+
+func TestProfiler_ProfileTrader(t *testing.T) {
+	dbPath := "test_profiler.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xabc", LastScanned: time.Now()}))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Market 1", EndsAt: time.Now().Add(12 * time.Hour)}))
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t1", TraderID: "0xabc", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: time.Now(),
+	}))
+
+	profiler := NewProfiler(database)
+	profile, err := profiler.ProfileTrader("0xabc")
+	require.NoError(t, err)
+	assert.Equal(t, 1, profile.Entries)
+	assert.InDelta(t, 1.0, profile.LateEntryRatio, 0.001)
+
+	stored, err := database.GetTraderProfile("0xabc")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 1, stored.Entries)
+
+	traders, err := database.ListTradersWithOptions(db.ListTradersOptions{MinLateEntryRatio: 0.5})
+	require.NoError(t, err)
+	assert.Len(t, traders, 1)
+
+	traders, err = database.ListTradersWithOptions(db.ListTradersOptions{MinHoldingHours: 1})
+	require.NoError(t, err)
+	assert.Len(t, traders, 0)
+}
@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+const (
+	// minClassifyTrades is the fewest trades needed before we assign a label.
+	minClassifyTrades = 10
+	// twoSidedWindow is how close a buy and a sell in one market must be to count as quoting both sides.
+	twoSidedWindow = time.Hour
+	// highFrequencyTradesPerDay is the trade rate treated as fully automated.
+	highFrequencyTradesPerDay = 100.0
+	// labelThreshold is the score a non-directional label must reach to be assigned.
+	labelThreshold = 0.5
+)
+
+// Classifier labels traders as directional, market makers or bots from their trade data.
+type Classifier struct {
+	db *db.DB
+}
+
+func NewClassifier(database *db.DB) *Classifier {
+	return &Classifier{db: database}
+}
+
+// ClassifyTrader classifies a single trader and persists the result.
+func (c *Classifier) ClassifyTrader(address string) (*db.TraderClassification, error) {
+	trades, err := c.db.GetTradesByTrader(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	classification := Classify(address, trades)
+	if err := c.db.SaveTraderClassification(classification); err != nil {
+		return nil, err
+	}
+	return classification, nil
+}
+
+// ClassifyAll classifies every stored trader and returns the number per label.
+func (c *Classifier) ClassifyAll() (map[string]int, error) {
+	traders, err := c.db.ListTraders()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, t := range traders {
+		classification, err := c.ClassifyTrader(t.Address)
+		if err != nil {
+			return counts, fmt.Errorf("failed to classify %s: %w", t.Address, err)
+		}
+		counts[classification.Label]++
+	}
+	return counts, nil
+}
+
+// Classify computes the classifier features for a trader and assigns a label with
+// a confidence between 0 and 1.
+func Classify(traderID string, trades []db.Trade) *db.TraderClassification {
+	c := &db.TraderClassification{
+		TraderID:  traderID,
+		Label:     db.TraderTypeUnknown,
+		UpdatedAt: time.Now(),
+	}
+	if len(trades) == 0 {
+		return c
+	}
+
+	sorted := append([]db.Trade(nil), trades...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var withRole, maker, round, subSecond int
+	byMarket := make(map[string][]db.Trade)
+	for i, t := range sorted {
+		if t.Role != "" {
+			withRole++
+			if t.Role == "maker" {
+				maker++
+			}
+		}
+		if t.Size >= 10 && math.Mod(t.Size, 10) == 0 {
+			round++
+		}
+		if i > 0 && t.Timestamp.Sub(sorted[i-1].Timestamp) < time.Second {
+			subSecond++
+		}
+		byMarket[t.MarketID] = append(byMarket[t.MarketID], t)
+	}
+
+	if withRole > 0 {
+		c.MakerRatio = float64(maker) / float64(withRole)
+	}
+	c.RoundSizeRatio = float64(round) / float64(len(sorted))
+	if len(sorted) > 1 {
+		c.SubSecondRatio = float64(subSecond) / float64(len(sorted)-1)
+	}
+
+	twoSided := 0
+	for _, mtrades := range byMarket {
+		if quotesBothSides(mtrades) {
+			twoSided++
+		}
+	}
+	c.TwoSidedRatio = float64(twoSided) / float64(len(byMarket))
+
+	days := sorted[len(sorted)-1].Timestamp.Sub(sorted[0].Timestamp).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+	c.TradesPerDay = float64(len(sorted)) / days
+
+	if len(sorted) < minClassifyTrades {
+		return c
+	}
+
+	frequency := math.Min(c.TradesPerDay/highFrequencyTradesPerDay, 1)
+	makerScore := 0.4*c.MakerRatio + 0.4*c.TwoSidedRatio + 0.2*frequency
+	botScore := 0.35*c.SubSecondRatio + 0.25*c.RoundSizeRatio + 0.4*frequency
+
+	switch {
+	case makerScore >= labelThreshold && makerScore >= botScore:
+		c.Label = db.TraderTypeMarketMaker
+		c.Confidence = makerScore
+	case botScore >= labelThreshold:
+		c.Label = db.TraderTypeBot
+		c.Confidence = botScore
+	default:
+		c.Label = db.TraderTypeDirectional
+		c.Confidence = 1 - math.Max(makerScore, botScore)
+	}
+
+	return c
+}
+
+// quotesBothSides reports whether a market's trades (sorted by time) contain a buy
+// and a sell within twoSidedWindow of each other.
+func quotesBothSides(trades []db.Trade) bool {
+	for i, t := range trades {
+		for _, other := range trades[i+1:] {
+			if other.Timestamp.Sub(t.Timestamp) > twoSidedWindow {
+				break
+			}
+			if IsBuy(t) != IsBuy(other) {
+				return true
+			}
+		}
+	}
+	return false
+}
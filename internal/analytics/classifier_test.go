@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify_MarketMaker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var trades []db.Trade
+	for i := 0; i < 40; i++ {
+		tradeType := "BUY"
+		if (i/4)%2 == 1 {
+			tradeType = "SELL"
+		}
+		trades = append(trades, db.Trade{
+			ID:        fmt.Sprintf("mm%d", i),
+			MarketID:  fmt.Sprintf("m%d", i%4),
+			Type:      tradeType,
+			Side:      "YES",
+			Price:     0.5,
+			Size:      37.5,
+			Role:      "maker",
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Minute),
+		})
+	}
+
+	c := Classify("0xmm", trades)
+
+	assert.Equal(t, db.TraderTypeMarketMaker, c.Label)
+	assert.InDelta(t, 1.0, c.MakerRatio, 0.001)
+	assert.InDelta(t, 1.0, c.TwoSidedRatio, 0.001)
+	assert.Greater(t, c.Confidence, 0.5)
+}
+
+func TestClassify_Bot(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var trades []db.Trade
+	for i := 0; i < 200; i++ {
+		trades = append(trades, db.Trade{
+			ID:        fmt.Sprintf("bot%d", i),
+			MarketID:  fmt.Sprintf("m%d", i),
+			Type:      "BUY",
+			Side:      "YES",
+			Price:     0.5,
+			Size:      100,
+			Role:      "taker",
+			Timestamp: start.Add(time.Duration(i/2) * time.Second),
+		})
+	}
+
+	c := Classify("0xbot", trades)
+
+	assert.Equal(t, db.TraderTypeBot, c.Label)
+	assert.InDelta(t, 1.0, c.RoundSizeRatio, 0.001)
+	assert.Greater(t, c.SubSecondRatio, 0.4)
+}
+
+func TestClassify_Directional(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var trades []db.Trade
+	for i := 0; i < 12; i++ {
+		trades = append(trades, db.Trade{
+			ID:        fmt.Sprintf("d%d", i),
+			MarketID:  fmt.Sprintf("m%d", i),
+			Type:      "BUY",
+			Side:      "YES",
+			Price:     0.4,
+			Size:      123.45,
+			Role:      "taker",
+			Timestamp: start.Add(time.Duration(i) * 36 * time.Hour),
+		})
+	}
+
+	c := Classify("0xdir", trades)
+
+	assert.Equal(t, db.TraderTypeDirectional, c.Label)
+	assert.Greater(t, c.Confidence, 0.5)
+}
+
+func TestClassify_TooFewTrades(t *testing.T) {
+	trades := []db.Trade{
+		{ID: "t1", MarketID: "m1", Type: "BUY", Size: 100, Timestamp: time.Now()},
+	}
+
+	c := Classify("0xnew", trades)
+
+	assert.Equal(t, db.TraderTypeUnknown, c.Label)
+	assert.Equal(t, 0.0, c.Confidence)
+}
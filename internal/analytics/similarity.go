@@ -0,0 +1,243 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+const (
+	// DefaultClusterThreshold is the similarity score at which two wallets are linked.
+	DefaultClusterThreshold = 0.6
+	// coTimingWindow is how close two entries in the same market must be to count as co-timed.
+	coTimingWindow = 15 * time.Minute
+	// minStoredSimilarity drops weak pairs so the similarities table stays small.
+	minStoredSimilarity = 0.2
+)
+
+// Clusterer computes trader-to-trader similarity and groups wallets that are
+// likely controlled by the same entity.
+type Clusterer struct {
+	db        *db.DB
+	Threshold float64
+}
+
+func NewClusterer(database *db.DB) *Clusterer {
+	return &Clusterer{
+		db:        database,
+		Threshold: DefaultClusterThreshold,
+	}
+}
+
+// Run scores every pair of traders that share at least one market, stores the
+// similarities and resulting clusters, and returns clusters of two or more wallets.
+func (c *Clusterer) Run() ([][]string, error) {
+	traders, err := c.db.ListTraders()
+	if err != nil {
+		return nil, err
+	}
+
+	tradesByTrader := make(map[string][]db.Trade)
+	tradersByMarket := make(map[string][]string)
+	for _, t := range traders {
+		trades, err := c.db.GetTradesByTrader(t.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trades for %s: %w", t.Address, err)
+		}
+		if len(trades) == 0 {
+			continue
+		}
+		tradesByTrader[t.Address] = trades
+
+		seen := make(map[string]bool)
+		for _, tr := range trades {
+			if !seen[tr.MarketID] {
+				seen[tr.MarketID] = true
+				tradersByMarket[tr.MarketID] = append(tradersByMarket[tr.MarketID], t.Address)
+			}
+		}
+	}
+
+	// Only pairs that share a market can score above zero on overlap or co-timing.
+	pairs := make(map[[2]string]bool)
+	for _, addrs := range tradersByMarket {
+		for i := 0; i < len(addrs); i++ {
+			for j := i + 1; j < len(addrs); j++ {
+				a, b := addrs[i], addrs[j]
+				if b < a {
+					a, b = b, a
+				}
+				pairs[[2]string{a, b}] = true
+			}
+		}
+	}
+
+	var sims []db.TraderSimilarity
+	for pair := range pairs {
+		s := Similarity(pair[0], tradesByTrader[pair[0]], pair[1], tradesByTrader[pair[1]])
+		if s.Score >= minStoredSimilarity {
+			sims = append(sims, s)
+		}
+	}
+
+	clusters := ClusterWallets(sims, c.Threshold)
+
+	now := time.Now()
+	var memberships []db.WalletCluster
+	for i, members := range clusters {
+		for _, addr := range members {
+			memberships = append(memberships, db.WalletCluster{TraderID: addr, ClusterID: i + 1, UpdatedAt: now})
+		}
+	}
+
+	if err := c.db.ReplaceSimilarities(sims, memberships); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// Similarity scores two traders on market overlap, co-timed entries and sizing.
+func Similarity(addrA string, tradesA []db.Trade, addrB string, tradesB []db.Trade) db.TraderSimilarity {
+	if addrB < addrA {
+		addrA, addrB = addrB, addrA
+		tradesA, tradesB = tradesB, tradesA
+	}
+
+	s := db.TraderSimilarity{
+		TraderA:   addrA,
+		TraderB:   addrB,
+		UpdatedAt: time.Now(),
+	}
+
+	s.MarketOverlap = marketOverlap(tradesA, tradesB)
+	s.CoTiming = coTiming(tradesA, tradesB)
+	s.Sizing = sizingSimilarity(tradesA, tradesB)
+	s.Score = 0.4*s.MarketOverlap + 0.4*s.CoTiming + 0.2*s.Sizing
+	return s
+}
+
+// ClusterWallets links pairs scoring at least threshold and returns the connected
+// groups of two or more wallets, largest first.
+func ClusterWallets(sims []db.TraderSimilarity, threshold float64) [][]string {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(x string) string {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+
+	for _, s := range sims {
+		if s.Score < threshold {
+			continue
+		}
+		for _, addr := range []string{s.TraderA, s.TraderB} {
+			if _, ok := parent[addr]; !ok {
+				parent[addr] = addr
+			}
+		}
+		ra, rb := find(s.TraderA), find(s.TraderB)
+		if ra != rb {
+			if rb < ra {
+				ra, rb = rb, ra
+			}
+			parent[rb] = ra
+		}
+	}
+
+	groups := make(map[string][]string)
+	for addr := range parent {
+		root := find(addr)
+		groups[root] = append(groups[root], addr)
+	}
+
+	var clusters [][]string
+	for _, members := range groups {
+		sort.Strings(members)
+		clusters = append(clusters, members)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+		return clusters[i][0] < clusters[j][0]
+	})
+	return clusters
+}
+
+func marketOverlap(a, b []db.Trade) float64 {
+	marketsA := make(map[string]bool)
+	for _, t := range a {
+		marketsA[t.MarketID] = true
+	}
+	marketsB := make(map[string]bool)
+	for _, t := range b {
+		marketsB[t.MarketID] = true
+	}
+
+	shared := 0
+	for m := range marketsA {
+		if marketsB[m] {
+			shared++
+		}
+	}
+	union := len(marketsA) + len(marketsB) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// coTiming is the share of both traders' entries that have a matching entry by the
+// other trader in the same market and direction within coTimingWindow.
+func coTiming(a, b []db.Trade) float64 {
+	matched := func(from, against []db.Trade) int {
+		n := 0
+		for _, t := range from {
+			for _, o := range against {
+				if o.MarketID != t.MarketID || IsBuy(o) != IsBuy(t) {
+					continue
+				}
+				gap := t.Timestamp.Sub(o.Timestamp)
+				if gap < 0 {
+					gap = -gap
+				}
+				if gap <= coTimingWindow {
+					n++
+					break
+				}
+			}
+		}
+		return n
+	}
+
+	total := len(a) + len(b)
+	if total == 0 {
+		return 0
+	}
+	return float64(matched(a, b)+matched(b, a)) / float64(total)
+}
+
+// sizingSimilarity compares median trade notional: 1 when equal, approaching 0
+// as one trader's typical size dwarfs the other's.
+func sizingSimilarity(a, b []db.Trade) float64 {
+	notionals := func(trades []db.Trade) []float64 {
+		values := make([]float64, 0, len(trades))
+		for _, t := range trades {
+			values = append(values, t.Price*t.Size)
+		}
+		return values
+	}
+
+	ma, mb := median(notionals(a)), median(notionals(b))
+	if ma <= 0 || mb <= 0 {
+		return 0
+	}
+	if ma > mb {
+		return mb / ma
+	}
+	return ma / mb
+}
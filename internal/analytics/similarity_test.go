@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarity(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := []db.Trade{
+		{ID: "a1", MarketID: "m1", Type: "BUY", Price: 0.5, Size: 100, Timestamp: start},
+		{ID: "a2", MarketID: "m2", Type: "BUY", Price: 0.5, Size: 100, Timestamp: start.Add(time.Hour)},
+	}
+	b := []db.Trade{
+		{ID: "b1", MarketID: "m1", Type: "BUY", Price: 0.5, Size: 100, Timestamp: start.Add(5 * time.Minute)},
+		{ID: "b2", MarketID: "m2", Type: "BUY", Price: 0.5, Size: 100, Timestamp: start.Add(time.Hour + 2*time.Minute)},
+	}
+
+	s := Similarity("0xb", b, "0xa", a)
+
+	assert.Equal(t, "0xa", s.TraderA)
+	assert.Equal(t, "0xb", s.TraderB)
+	assert.InDelta(t, 1.0, s.MarketOverlap, 0.001)
+	assert.InDelta(t, 1.0, s.CoTiming, 0.001)
+	assert.InDelta(t, 1.0, s.Sizing, 0.001)
+	assert.InDelta(t, 1.0, s.Score, 0.001)
+
+	c := []db.Trade{
+		{ID: "c1", MarketID: "m1", Type: "SELL", Price: 0.5, Size: 10, Timestamp: start.Add(48 * time.Hour)},
+		{ID: "c2", MarketID: "m3", Type: "BUY", Price: 0.5, Size: 10, Timestamp: start.Add(48 * time.Hour)},
+	}
+
+	s = Similarity("0xa", a, "0xc", c)
+	assert.InDelta(t, 1.0/3.0, s.MarketOverlap, 0.001)
+	assert.Equal(t, 0.0, s.CoTiming)
+	assert.InDelta(t, 0.1, s.Sizing, 0.001)
+	assert.Less(t, s.Score, DefaultClusterThreshold)
+}
+
+func TestClusterWallets(t *testing.T) {
+	sims := []db.TraderSimilarity{
+		{TraderA: "0xa", TraderB: "0xb", Score: 0.9},
+		{TraderA: "0xb", TraderB: "0xc", Score: 0.7},
+		{TraderA: "0xd", TraderB: "0xe", Score: 0.8},
+		{TraderA: "0xc", TraderB: "0xd", Score: 0.3},
+	}
+
+	clusters := ClusterWallets(sims, 0.6)
+
+	require.Len(t, clusters, 2)
+	assert.Equal(t, []string{"0xa", "0xb", "0xc"}, clusters[0])
+	assert.Equal(t, []string{"0xd", "0xe"}, clusters[1])
+}
+
+func TestClusterer_Run(t *testing.T) {
+	dbPath := "test_clusterer.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, addr := range []string{"0xa", "0xb", "0xloner"} {
+		require.NoError(t, database.SaveTrader(&db.Trader{Address: addr, LastScanned: start}))
+	}
+	for i := 0; i < 3; i++ {
+		market := fmt.Sprintf("m%d", i)
+		at := start.Add(time.Duration(i) * 24 * time.Hour)
+		require.NoError(t, database.SaveTrade(&db.Trade{ID: "a" + market, TraderID: "0xa", MarketID: market, Type: "BUY", Price: 0.4, Size: 50, Timestamp: at}))
+		require.NoError(t, database.SaveTrade(&db.Trade{ID: "b" + market, TraderID: "0xb", MarketID: market, Type: "BUY", Price: 0.4, Size: 55, Timestamp: at.Add(3 * time.Minute)}))
+	}
+	require.NoError(t, database.SaveTrade(&db.Trade{ID: "l1", TraderID: "0xloner", MarketID: "m9", Type: "BUY", Price: 0.4, Size: 50, Timestamp: start}))
+
+	clusters, err := NewClusterer(database).Run()
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, []string{"0xa", "0xb"}, clusters[0])
+
+	similar, err := database.GetSimilarTraders("0xa", 5)
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Equal(t, "0xb", similar[0].Other("0xa"))
+
+	cluster, err := database.GetWalletCluster("0xb")
+	require.NoError(t, err)
+	require.NotNil(t, cluster)
+	assert.Equal(t, 1, cluster.ClusterID)
+
+	loner, err := database.GetWalletCluster("0xloner")
+	require.NoError(t, err)
+	assert.Nil(t, loner)
+}
@@ -0,0 +1,215 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+const (
+	DefaultMoveWindow    = 24 * time.Hour
+	DefaultMoveThreshold = 0.15
+	DefaultMinEntries    = 5
+)
+
+// TimingReport summarizes how often a trader's entries were followed by a large
+// favorable price move or a surprise resolution, compared with random entries in
+// the same markets.
+type TimingReport struct {
+	TraderID     string
+	Entries      int
+	Hits         int
+	HitRate      float64
+	BaselineRate float64 // expected hit rate for random entries in the same markets
+	Lift         float64 // HitRate / BaselineRate
+	ZScore       float64 // standard deviations of hits above the baseline expectation
+}
+
+// Detector flags wallets whose entries consistently precede large price moves or
+// surprise resolutions.
+type Detector struct {
+	db         *db.DB
+	Window     time.Duration
+	Threshold  float64
+	MinEntries int
+}
+
+func NewDetector(database *db.DB) *Detector {
+	return &Detector{
+		db:         database,
+		Window:     DefaultMoveWindow,
+		Threshold:  DefaultMoveThreshold,
+		MinEntries: DefaultMinEntries,
+	}
+}
+
+// pricePoint is a YES-price observation from a snapshot or a trade.
+type pricePoint struct {
+	at    time.Time
+	price float64
+}
+
+// entryStats accumulates hits and the baseline expectation for one trader.
+type entryStats struct {
+	entries  int
+	hits     int
+	expected float64
+	variance float64
+}
+
+// Detect evaluates every stored entry and returns traders ranked by z-score, most
+// suspicious first. Traders with fewer than MinEntries evaluated entries are omitted.
+func (d *Detector) Detect() ([]TimingReport, error) {
+	marketIDs, err := d.db.GetTradedMarketIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*entryStats)
+	for _, marketID := range marketIDs {
+		trades, err := d.db.GetTradesByMarket(marketID)
+		if err != nil {
+			return nil, err
+		}
+		snapshots, err := d.db.GetMarketSnapshots(marketID)
+		if err != nil {
+			return nil, err
+		}
+		market, err := d.db.GetMarket(marketID)
+		if err != nil {
+			return nil, err
+		}
+		d.scoreMarket(trades, snapshots, market, stats)
+	}
+
+	var reports []TimingReport
+	for traderID, s := range stats {
+		if s.entries < d.MinEntries {
+			continue
+		}
+		r := TimingReport{
+			TraderID:     traderID,
+			Entries:      s.entries,
+			Hits:         s.hits,
+			HitRate:      float64(s.hits) / float64(s.entries),
+			BaselineRate: s.expected / float64(s.entries),
+		}
+		if r.BaselineRate > 0 {
+			r.Lift = r.HitRate / r.BaselineRate
+		}
+		if s.variance > 0 {
+			r.ZScore = (float64(s.hits) - s.expected) / math.Sqrt(s.variance)
+		}
+		reports = append(reports, r)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].ZScore != reports[j].ZScore {
+			return reports[i].ZScore > reports[j].ZScore
+		}
+		return reports[i].TraderID < reports[j].TraderID
+	})
+	return reports, nil
+}
+
+// scoreMarket records, for each buy in the market, whether it was followed by a
+// favorable move and the chance that a random entry on the same side would be.
+// market is nil when it is not stored.
+func (d *Detector) scoreMarket(trades []db.Trade, snapshots []db.MarketSnapshot, market *db.Market, stats map[string]*entryStats) {
+	series := priceSeries(trades, snapshots, market)
+	if len(series) < 2 {
+		return
+	}
+
+	baseline := map[bool]float64{
+		true:  d.baselineRate(series, true),
+		false: d.baselineRate(series, false),
+	}
+
+	for _, t := range trades {
+		if !IsBuy(t) {
+			continue
+		}
+		yes := !strings.EqualFold(t.Side, "no")
+		entryPrice := t.Price
+		if !yes {
+			entryPrice = 1 - t.Price
+		}
+
+		s, ok := stats[t.TraderID]
+		if !ok {
+			s = &entryStats{}
+			stats[t.TraderID] = s
+		}
+		p := baseline[yes]
+		s.entries++
+		s.expected += p
+		s.variance += p * (1 - p)
+		if d.favorableMove(series, t.Timestamp, entryPrice, yes) >= d.Threshold {
+			s.hits++
+		}
+	}
+}
+
+// baselineRate is the share of observations in the series that were followed by a
+// favorable move above the threshold for the given side.
+func (d *Detector) baselineRate(series []pricePoint, yes bool) float64 {
+	hits := 0
+	for _, p := range series {
+		if d.favorableMove(series, p.at, p.price, yes) >= d.Threshold {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(series))
+}
+
+// favorableMove returns the largest move in the entry's favor within Window after from.
+func (d *Detector) favorableMove(series []pricePoint, from time.Time, entryPrice float64, yes bool) float64 {
+	end := from.Add(d.Window)
+	best := 0.0
+	for _, p := range series {
+		if !p.at.After(from) || p.at.After(end) {
+			continue
+		}
+		move := p.price - entryPrice
+		if !yes {
+			move = -move
+		}
+		if move > best {
+			best = move
+		}
+	}
+	return best
+}
+
+// priceSeries merges snapshots and trades into a time-ordered YES price series.
+// A resolved market ends the series at 1 or 0, so a resolution against the
+// prevailing price counts as a move like any other.
+func priceSeries(trades []db.Trade, snapshots []db.MarketSnapshot, market *db.Market) []pricePoint {
+	series := make([]pricePoint, 0, len(trades)+len(snapshots)+1)
+	for _, s := range snapshots {
+		series = append(series, pricePoint{at: s.Timestamp, price: s.YesPrice})
+	}
+	for _, t := range trades {
+		price := t.Price
+		if strings.EqualFold(t.Side, "no") {
+			price = 1 - t.Price
+		}
+		series = append(series, pricePoint{at: t.Timestamp, price: price})
+	}
+	if market != nil && !market.ResolvedAt.IsZero() {
+		switch {
+		case strings.EqualFold(market.Resolution, "yes"):
+			series = append(series, pricePoint{at: market.ResolvedAt, price: 1})
+		case strings.EqualFold(market.Resolution, "no"):
+			series = append(series, pricePoint{at: market.ResolvedAt, price: 0})
+		}
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].at.Before(series[j].at)
+	})
+	return series
+}
@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	dbPath := "test_detector.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	save := func(tr db.Trade) {
+		require.NoError(t, database.SaveTrade(&tr))
+	}
+
+	// Each market drifts flat for several days, then jumps 0.30 on day 5.
+	// The insider buys hours before each jump; the noise trader buys on flat days.
+	for m := 0; m < 6; m++ {
+		marketID := fmt.Sprintf("m%d", m)
+		for day := 0; day < 8; day++ {
+			price := 0.40
+			if day >= 5 {
+				price = 0.70
+			}
+			require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{
+				MarketID:  marketID,
+				YesPrice:  price,
+				NoPrice:   1 - price,
+				Timestamp: start.Add(time.Duration(day) * 24 * time.Hour),
+			}))
+		}
+
+		save(db.Trade{
+			ID: fmt.Sprintf("ins-%d", m), TraderID: "0xinsider", MarketID: marketID,
+			Type: "BUY", Side: "YES", Price: 0.40, Size: 100,
+			Timestamp: start.Add(4*24*time.Hour + 20*time.Hour),
+		})
+		save(db.Trade{
+			ID: fmt.Sprintf("noise-%d", m), TraderID: "0xnoise", MarketID: marketID,
+			Type: "BUY", Side: "YES", Price: 0.40, Size: 100,
+			Timestamp: start.Add(time.Duration(m%3)*24*time.Hour + 2*time.Hour),
+		})
+	}
+
+	detector := NewDetector(database)
+	reports, err := detector.Detect()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	top := reports[0]
+	assert.Equal(t, "0xinsider", top.TraderID)
+	assert.Equal(t, 6, top.Entries)
+	assert.Equal(t, 6, top.Hits)
+	assert.Greater(t, top.Lift, 1.0)
+	assert.Greater(t, top.ZScore, 0.0)
+
+	assert.Equal(t, "0xnoise", reports[1].TraderID)
+	assert.Equal(t, 0, reports[1].Hits)
+	assert.Less(t, reports[1].ZScore, 0.0)
+}
+
+func TestDetector_MinEntries(t *testing.T) {
+	dbPath := "test_detector_min.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	now := time.Now()
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t1", TraderID: "0xfew", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: now,
+	}))
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{
+		MarketID: "m1", YesPrice: 0.9, NoPrice: 0.1, Timestamp: now.Add(time.Hour),
+	}))
+
+	detector := NewDetector(database)
+	reports, err := detector.Detect()
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+
+	detector.MinEntries = 1
+	reports, err = detector.Detect()
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, 1, reports[0].Hits)
+}
+
+func TestDetector_SurpriseResolution(t *testing.T) {
+	dbPath := "test_detector_resolution.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resolvedAt := start.Add(3*24*time.Hour + 12*time.Hour)
+	// m1 trades at 0.20 and resolves Yes, a surprise; m2 trades at 0.10 and
+	// resolves No, as expected. Neither price moves before resolving.
+	for _, m := range []struct {
+		id, resolution string
+		yes            float64
+	}{{"m1", "Yes", 0.20}, {"m2", "No", 0.10}} {
+		require.NoError(t, database.SaveMarket(&db.Market{ID: m.id, Status: "resolved", Resolution: m.resolution, ResolvedAt: resolvedAt}))
+		for day := 0; day < 4; day++ {
+			require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{
+				MarketID: m.id, YesPrice: m.yes, NoPrice: 1 - m.yes, Timestamp: start.Add(time.Duration(day) * 24 * time.Hour),
+			}))
+		}
+	}
+	entry := resolvedAt.Add(-6 * time.Hour)
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "a", TraderID: "0xsurprise", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.20, Size: 100, Timestamp: entry,
+	}))
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "b", TraderID: "0xexpected", MarketID: "m2", Type: "BUY", Side: "NO", Price: 0.90, Size: 100, Timestamp: entry,
+	}))
+
+	detector := NewDetector(database)
+	detector.MinEntries = 1
+	reports, err := detector.Detect()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	hits := map[string]int{}
+	for _, r := range reports {
+		hits[r.TraderID] = r.Hits
+	}
+	assert.Equal(t, 1, hits["0xsurprise"], "a resolution against the prevailing price is a hit")
+	assert.Equal(t, 0, hits["0xexpected"], "an expected resolution is not")
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+)
+
+var sortFields = map[string]db.SortField{
+	"profit_loss": db.SortByProfitLoss,
+	"win_rate":    db.SortByWinRate,
+	"roi":         db.SortByROI,
+	"volume":      db.SortByVolume,
+}
+
+type traderDetail struct {
+	db.Trader
+	Profile        *db.TraderProfile        `json:"profile,omitempty"`
+	Classification *db.TraderClassification `json:"classification,omitempty"`
+	Watched        bool                     `json:"watched"`
+}
+
+type watchlistRequest struct {
+	Notes string `json:"notes"`
+}
+
+func (s *Server) listTraders(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	q := r.URL.Query()
+	opts := db.ListTradersOptions{
+		SortBy:     db.SortByProfitLoss,
+		Order:      db.SortDesc,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		TraderType: q.Get("type"),
+		Category:   q.Get("category"),
+	}
+	if v := q.Get("sort"); v != "" {
+		field, ok := sortFields[v]
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown sort field %q", v))
+			return
+		}
+		opts.SortBy = field
+	}
+	switch strings.ToLower(q.Get("order")) {
+	case "", "desc":
+	case "asc":
+		opts.Order = db.SortAsc
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("order must be asc or desc"))
+		return
+	}
+
+	traders, err := s.db.ListTradersWithOptions(opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if page.Total, err = s.db.CountTradersWithOptions(opts); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, nonNil(traders), page)
+}
+
+func (s *Server) getTrader(w http.ResponseWriter, r *http.Request) {
+	trader, ok := s.loadTrader(w, r)
+	if !ok {
+		return
+	}
+
+	detail := traderDetail{Trader: *trader}
+	var err error
+	if detail.Profile, err = s.db.GetTraderProfile(trader.Address); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if detail.Classification, err = s.db.GetTraderClassification(trader.Address); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	item, err := s.db.GetWatchlistItem(trader.Address)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	detail.Watched = item != nil
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) listTraderTrades(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	trades, err := s.db.GetTradesByTrader(r.PathValue("address"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, paginate(trades, &page), page)
+}
+
+func (s *Server) listTraderAnalyses(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	analyses, err := s.db.GetAllAnalysesByTrader(r.PathValue("address"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, paginate(analyses, &page), page)
+}
+
+// createAnalysis runs a Claude analysis of the trader's stored data and saves it.
+func (s *Server) createAnalysis(w http.ResponseWriter, r *http.Request) {
+	if s.Analyzer == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("analysis is not available: claude.api_key is not configured"))
+		return
+	}
+	trader, ok := s.loadTrader(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := claude.LoadTraderData(s.db, trader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result, err := s.Analyzer.AnalyzeTrader(r.Context(), data)
+	// A truncated thesis is still worth keeping.
+	if err != nil && !errors.Is(err, claude.ErrTokenLimit) {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("analysis failed: %w", err))
+		return
+	}
+
+	analysis := result.Analysis(trader.Address)
+	if err := s.db.SaveAnalysis(analysis); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, analysis)
+}
+
+func (s *Server) listMarkets(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts := db.ListMarketsOptions{
+		Query:  r.URL.Query().Get("q"),
+		Status: r.URL.Query().Get("status"),
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	markets, err := s.db.ListMarkets(opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if page.Total, err = s.db.CountMarkets(opts); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, nonNil(markets), page)
+}
+
+func (s *Server) getMarket(w http.ResponseWriter, r *http.Request) {
+	market, err := s.db.GetMarket(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if market == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("market %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, market)
+}
+
+func (s *Server) listSnapshots(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	snapshots, err := s.db.GetMarketSnapshots(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, paginate(snapshots, &page), page)
+}
+
+func (s *Server) listAnalyses(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	analyses, err := s.db.ListAnalyses(page.Limit, page.Offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if page.Total, err = s.db.CountAnalyses(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, nonNil(analyses), page)
+}
+
+func (s *Server) listWatchlist(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	items, err := s.db.ListWatchlist()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeList(w, paginate(items, &page), page)
+}
+
+// putWatchlist adds a trader to the watchlist or updates their notes.
+func (s *Server) putWatchlist(w http.ResponseWriter, r *http.Request) {
+	var req watchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	address := r.PathValue("address")
+	if err := s.db.AddToWatchlist(address, req.Notes); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	item, err := s.db.GetWatchlistItem(address)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) deleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	if err := s.db.RemoveFromWatchlist(r.PathValue("address")); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadTrader fetches the trader named in the path, writing a 404 if it does not exist.
+func (s *Server) loadTrader(w http.ResponseWriter, r *http.Request) (*db.Trader, bool) {
+	address := r.PathValue("address")
+	trader, err := s.db.GetTrader(address)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if trader == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("trader %s not found", address))
+		return nil, false
+	}
+	return trader, true
+}
+
+// nonNil makes empty results encode as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
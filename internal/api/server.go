@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Analyzer produces a thesis for a trader. *claude.Client satisfies it.
+type Analyzer interface {
+	AnalyzeTrader(ctx context.Context, data claude.TraderData) (*claude.AnalysisResult, error)
+}
+
+// Server exposes the database as a JSON REST API.
+type Server struct {
+	db *db.DB
+	// Token, when set, must be presented as "Authorization: Bearer <token>".
+	Token string
+	// Analyzer runs analyses triggered through the API; nil disables them.
+	Analyzer Analyzer
+}
+
+func NewServer(database *db.DB) *Server {
+	return &Server{db: database}
+}
+
+// Page is the pagination block of list responses.
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type listResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination Page        `json:"pagination"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the API's routes wrapped in authentication.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/traders", s.listTraders)
+	mux.HandleFunc("GET /api/traders/{address}", s.getTrader)
+	mux.HandleFunc("GET /api/traders/{address}/trades", s.listTraderTrades)
+	mux.HandleFunc("GET /api/traders/{address}/analyses", s.listTraderAnalyses)
+	mux.HandleFunc("POST /api/traders/{address}/analyses", s.createAnalysis)
+	mux.HandleFunc("GET /api/markets", s.listMarkets)
+	mux.HandleFunc("GET /api/markets/{id}", s.getMarket)
+	mux.HandleFunc("GET /api/markets/{id}/snapshots", s.listSnapshots)
+	mux.HandleFunc("GET /api/analyses", s.listAnalyses)
+	mux.HandleFunc("GET /api/watchlist", s.listWatchlist)
+	mux.HandleFunc("PUT /api/watchlist/{address}", s.putWatchlist)
+	mux.HandleFunc("DELETE /api/watchlist/{address}", s.deleteWatchlist)
+	mux.Handle("GET /metrics", metrics.Handler())
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeList(w http.ResponseWriter, data interface{}, page Page) {
+	writeJSON(w, http.StatusOK, listResponse{Data: data, Pagination: page})
+}
+
+// pageParams reads limit and offset, applying DefaultLimit and MaxLimit.
+func pageParams(r *http.Request) (Page, error) {
+	page := Page{Limit: DefaultLimit}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return page, errors.New("limit must be a positive integer")
+		}
+		page.Limit = min(n, MaxLimit)
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, errors.New("offset must be a non-negative integer")
+		}
+		page.Offset = n
+	}
+	return page, nil
+}
+
+// paginate slices items already loaded in full according to page.
+func paginate[T any](items []T, page *Page) []T {
+	page.Total = len(items)
+	if page.Offset >= len(items) {
+		return []T{}
+	}
+	end := min(page.Offset+page.Limit, len(items))
+	return items[page.Offset:end]
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAnalyzer struct {
+	calls int
+}
+
+func (f *fakeAnalyzer) AnalyzeTrader(ctx context.Context, data claude.TraderData) (*claude.AnalysisResult, error) {
+	f.calls++
+	return &claude.AnalysisResult{
+		Thesis:    fmt.Sprintf("%s trades %d times.", data.Trader.Address, len(data.Trades)),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func setupServer(t *testing.T) (*Server, *db.DB) {
+	dbPath := "test_api.db"
+	t.Cleanup(func() { os.Remove(dbPath) })
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, database.SaveTrader(&db.Trader{
+			Address:     fmt.Sprintf("0x%d", i),
+			ProfitLoss:  float64(i * 100),
+			WinRate:     float64(5-i) / 10,
+			LastScanned: time.Now(),
+		}))
+	}
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain in Paris?", Status: "active"}))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m2", Question: "Will BTC hit 100k?", Status: "closed"}))
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{MarketID: "m1", YesPrice: 0.4, NoPrice: 0.6, Timestamp: time.Now()}))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, database.SaveTrade(&db.Trade{
+			ID: fmt.Sprintf("t%d", i), TraderID: "0x1", MarketID: "m1", Type: "BUY", Side: "YES",
+			Price: 0.4, Size: 10, Timestamp: time.Now().Add(-time.Duration(i) * time.Hour),
+		}))
+	}
+	return NewServer(database), database
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeList(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) Page {
+	var resp struct {
+		Data       json.RawMessage `json:"data"`
+		Pagination Page            `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NoError(t, json.Unmarshal(resp.Data, data))
+	return resp.Pagination
+}
+
+func TestListTraders_SortAndPaginate(t *testing.T) {
+	s, _ := setupServer(t)
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodGet, "/api/traders?limit=2&offset=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var traders []db.Trader
+	page := decodeList(t, rec, &traders)
+	assert.Equal(t, Page{Limit: 2, Offset: 1, Total: 5}, page)
+	require.Len(t, traders, 2)
+	assert.Equal(t, "0x3", traders[0].Address)
+	assert.Equal(t, "0x2", traders[1].Address)
+
+	rec = do(t, h, http.MethodGet, "/api/traders?sort=win_rate&order=desc&limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	decodeList(t, rec, &traders)
+	assert.Equal(t, "0x0", traders[0].Address)
+
+	assert.Equal(t, http.StatusBadRequest, do(t, h, http.MethodGet, "/api/traders?sort=luck", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, h, http.MethodGet, "/api/traders?limit=-1", "").Code)
+}
+
+func TestTraderEndpoints(t *testing.T) {
+	s, _ := setupServer(t)
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodGet, "/api/traders/0x1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var detail map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	assert.Equal(t, "0x1", detail["address"])
+	assert.Equal(t, false, detail["watched"])
+
+	assert.Equal(t, http.StatusNotFound, do(t, h, http.MethodGet, "/api/traders/0xnope", "").Code)
+
+	rec = do(t, h, http.MethodGet, "/api/traders/0x1/trades?limit=2", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trades []db.Trade
+	page := decodeList(t, rec, &trades)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, trades, 2)
+	assert.Equal(t, "t0", trades[0].ID)
+
+	rec = do(t, h, http.MethodGet, "/api/traders/0x1/trades?offset=10", "")
+	assert.JSONEq(t, `{"data": [], "pagination": {"limit": 50, "offset": 10, "total": 3}}`, rec.Body.String())
+}
+
+func TestMarketEndpoints(t *testing.T) {
+	s, _ := setupServer(t)
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodGet, "/api/markets?q=rain", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var markets []db.Market
+	page := decodeList(t, rec, &markets)
+	assert.Equal(t, 1, page.Total)
+	require.Len(t, markets, 1)
+	assert.Equal(t, "m1", markets[0].ID)
+
+	rec = do(t, h, http.MethodGet, "/api/markets?status=closed", "")
+	decodeList(t, rec, &markets)
+	require.Len(t, markets, 1)
+	assert.Equal(t, "m2", markets[0].ID)
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/api/markets/m1", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(t, h, http.MethodGet, "/api/markets/m9", "").Code)
+
+	rec = do(t, h, http.MethodGet, "/api/markets/m1/snapshots", "")
+	var snapshots []db.MarketSnapshot
+	decodeList(t, rec, &snapshots)
+	require.Len(t, snapshots, 1)
+	assert.InDelta(t, 0.4, snapshots[0].YesPrice, 1e-9)
+}
+
+func TestWatchlistEndpoints(t *testing.T) {
+	s, database := setupServer(t)
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodPut, "/api/watchlist/0x2", `{"notes": "sharp on weather"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	item, err := database.GetWatchlistItem("0x2")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, "sharp on weather", item.Notes)
+
+	rec = do(t, h, http.MethodGet, "/api/watchlist", "")
+	var items []db.WatchlistItem
+	decodeList(t, rec, &items)
+	require.Len(t, items, 1)
+
+	assert.Equal(t, http.StatusBadRequest, do(t, h, http.MethodPut, "/api/watchlist/0x2", `{notes`).Code)
+
+	assert.Equal(t, http.StatusNoContent, do(t, h, http.MethodDelete, "/api/watchlist/0x2", "").Code)
+	item, err = database.GetWatchlistItem("0x2")
+	require.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func TestCreateAnalysis(t *testing.T) {
+	s, database := setupServer(t)
+
+	assert.Equal(t, http.StatusServiceUnavailable, do(t, s.Handler(), http.MethodPost, "/api/traders/0x1/analyses", "").Code)
+
+	analyzer := &fakeAnalyzer{}
+	s.Analyzer = analyzer
+	h := s.Handler()
+
+	rec := do(t, h, http.MethodPost, "/api/traders/0x1/analyses", "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var analysis db.Analysis
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &analysis))
+	assert.Equal(t, "0x1 trades 3 times.", analysis.Thesis)
+	assert.NotZero(t, analysis.ID)
+
+	saved, err := database.GetAnalysisByTrader("0x1")
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+
+	rec = do(t, h, http.MethodGet, "/api/analyses", "")
+	var analyses []db.Analysis
+	page := decodeList(t, rec, &analyses)
+	assert.Equal(t, 1, page.Total)
+
+	assert.Equal(t, http.StatusNotFound, do(t, h, http.MethodPost, "/api/traders/0xnope/analyses", "").Code)
+	assert.Equal(t, 1, analyzer.calls)
+}
+
+func TestBearerToken(t *testing.T) {
+	s, _ := setupServer(t)
+	s.Token = "secret"
+	h := s.Handler()
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodGet, "/api/traders", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, http.MethodGet, "/api/traders", "", "Authorization", "Bearer wrong").Code)
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/api/traders", "", "Authorization", "Bearer secret").Code)
+}
@@ -0,0 +1,339 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+)
+
+// SizingRule decides how much to commit when copying a trader's buy.
+type SizingRule string
+
+const (
+	// SizingFixed spends FixedNotional on every copied buy.
+	SizingFixed SizingRule = "fixed"
+	// SizingProportional spends Ratio times the source trade's notional.
+	SizingProportional SizingRule = "proportional"
+	// SizingKelly stakes the Kelly fraction of equity implied by the source
+	// trader's win rate and the entry price, capped at KellyCap.
+	SizingKelly SizingRule = "kelly"
+)
+
+const (
+	DefaultDelay         = time.Minute
+	DefaultStartingCash  = 10000.0
+	DefaultFixedNotional = 100.0
+	DefaultRatio         = 0.1
+	DefaultKellyCap      = 0.05
+	DefaultSpreadBps     = 50.0
+
+	// minNotional is the smallest order worth copying.
+	minNotional = 1.0
+)
+
+// ParseSizingRule validates a sizing rule name.
+func ParseSizingRule(s string) (SizingRule, error) {
+	switch r := SizingRule(strings.ToLower(s)); r {
+	case SizingFixed, SizingProportional, SizingKelly:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown sizing rule %q (want fixed, proportional or kelly)", s)
+}
+
+// EquityPoint is the portfolio value after a copied fill.
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Result summarizes a backtest run.
+type Result struct {
+	StartingCash float64
+	FinalEquity  float64
+	ProfitLoss   float64
+	Return       float64
+	MaxDrawdown  float64 // largest peak-to-trough fall as a fraction of the peak
+	HitRate      float64 // share of copied positions that ended in profit
+	Fills        int
+	Skipped      int // source trades that could not be copied (no cash, no position, too small)
+	Positions    int
+	Curve        []EquityPoint
+}
+
+// Backtester replays traders' stored trades as if they had been copied.
+type Backtester struct {
+	db            *db.DB
+	Delay         time.Duration
+	Sizing        SizingRule
+	StartingCash  float64
+	FixedNotional float64
+	Ratio         float64
+	KellyCap      float64
+	Slippage      SlippageModel
+}
+
+func NewBacktester(database *db.DB) *Backtester {
+	return &Backtester{
+		db:            database,
+		Delay:         DefaultDelay,
+		Sizing:        SizingFixed,
+		StartingCash:  DefaultStartingCash,
+		FixedNotional: DefaultFixedNotional,
+		Ratio:         DefaultRatio,
+		KellyCap:      DefaultKellyCap,
+		Slippage:      SpreadModel{Bps: DefaultSpreadBps},
+	}
+}
+
+// pricePoint is a YES-price observation from a snapshot or a trade.
+type pricePoint struct {
+	at    time.Time
+	price float64
+}
+
+// position is the copied holding in one market outcome.
+type position struct {
+	marketID string
+	side     string
+	shares   float64
+	cost     float64
+	proceeds float64
+}
+
+// Run copies every trade by the given traders and returns the simulated result.
+func (b *Backtester) Run(traderIDs []string) (*Result, error) {
+	var trades []db.Trade
+	winRates := make(map[string]float64)
+	for _, id := range traderIDs {
+		tt, err := b.db.GetTradesByTrader(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trades for %s: %w", id, err)
+		}
+		trades = append(trades, tt...)
+
+		trader, err := b.db.GetTrader(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trader %s: %w", id, err)
+		}
+		if trader != nil {
+			winRates[id] = trader.WinRate
+		}
+	}
+
+	series := make(map[string][]pricePoint)
+	for _, t := range trades {
+		if _, ok := series[t.MarketID]; ok {
+			continue
+		}
+		s, err := b.marketSeries(t.MarketID)
+		if err != nil {
+			return nil, err
+		}
+		series[t.MarketID] = s
+	}
+
+	return b.replay(trades, series, winRates), nil
+}
+
+// marketSeries merges a market's snapshots and prints into a time-ordered YES price series.
+func (b *Backtester) marketSeries(marketID string) ([]pricePoint, error) {
+	snapshots, err := b.db.GetMarketSnapshots(marketID)
+	if err != nil {
+		return nil, err
+	}
+	prints, err := b.db.GetTradesByMarket(marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make([]pricePoint, 0, len(snapshots)+len(prints))
+	for _, snap := range snapshots {
+		s = append(s, pricePoint{at: snap.Timestamp, price: snap.YesPrice})
+	}
+	for _, t := range prints {
+		s = append(s, pricePoint{at: t.Timestamp, price: yesPrice(t.Side, t.Price)})
+	}
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].at.Before(s[j].at)
+	})
+	return s, nil
+}
+
+func (b *Backtester) replay(trades []db.Trade, series map[string][]pricePoint, winRates map[string]float64) *Result {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp.Before(trades[j].Timestamp)
+	})
+
+	res := &Result{StartingCash: b.StartingCash}
+	cash := b.StartingCash
+	positions := make(map[string]*position)
+	var order []string
+	sourceHoldings := make(map[string]float64)
+
+	// priceAt is the last observed price for the outcome at or before at, falling
+	// back to the source trade's own price when nothing was observed yet.
+	priceAt := func(marketID, side string, at time.Time, fallback float64) float64 {
+		s := series[marketID]
+		i := sort.Search(len(s), func(i int) bool { return s[i].at.After(at) })
+		if i == 0 {
+			return fallback
+		}
+		return sidePrice(side, s[i-1].price)
+	}
+
+	equityAt := func(at time.Time) float64 {
+		equity := cash
+		for _, p := range positions {
+			equity += p.shares * priceAt(p.marketID, p.side, at, p.cost/math.Max(p.shares, 1e-9))
+		}
+		return equity
+	}
+
+	peak := b.StartingCash
+	for _, t := range trades {
+		side := normalizeSide(t.Side)
+		fillAt := t.Timestamp.Add(b.Delay)
+		reference := priceAt(t.MarketID, side, fillAt, t.Price)
+		key := t.MarketID + "|" + side
+		sourceKey := t.TraderID + "|" + key
+
+		if analytics.IsBuy(t) {
+			sourceHoldings[sourceKey] += t.Size
+
+			notional := math.Min(b.notional(t, winRates[t.TraderID], equityAt(fillAt)), cash)
+			if notional < minNotional {
+				res.Skipped++
+				continue
+			}
+			// A side priced at zero, such as one its market resolved against,
+			// cannot be bought.
+			if reference <= 0 {
+				res.Skipped++
+				continue
+			}
+			shares := notional / reference
+			fill := b.Slippage.FillPrice(t.MarketID, side, true, reference, shares)
+			if fill <= 0 {
+				res.Skipped++
+				continue
+			}
+			shares = notional / fill
+
+			p, ok := positions[key]
+			if !ok {
+				p = &position{marketID: t.MarketID, side: side}
+				positions[key] = p
+				order = append(order, key)
+			}
+			p.shares += shares
+			p.cost += notional
+			cash -= notional
+		} else {
+			held := sourceHoldings[sourceKey]
+			p, ok := positions[key]
+			if held <= 0 || !ok || p.shares <= 0 {
+				res.Skipped++
+				continue
+			}
+			frac := math.Min(1, t.Size/held)
+			sourceHoldings[sourceKey] = math.Max(0, held-t.Size)
+
+			shares := p.shares * frac
+			fill := b.Slippage.FillPrice(t.MarketID, side, false, reference, shares)
+			p.shares -= shares
+			p.proceeds += shares * fill
+			cash += shares * fill
+		}
+
+		res.Fills++
+		equity := equityAt(fillAt)
+		res.Curve = append(res.Curve, EquityPoint{Time: fillAt, Equity: equity})
+		peak = math.Max(peak, equity)
+		if peak > 0 {
+			res.MaxDrawdown = math.Max(res.MaxDrawdown, (peak-equity)/peak)
+		}
+	}
+
+	end := time.Now()
+	if len(res.Curve) > 0 {
+		end = res.Curve[len(res.Curve)-1].Time
+	}
+	for _, s := range series {
+		if len(s) > 0 && s[len(s)-1].at.After(end) {
+			end = s[len(s)-1].at
+		}
+	}
+
+	hits := 0
+	for _, key := range order {
+		p := positions[key]
+		value := p.shares * priceAt(p.marketID, p.side, end, p.cost/math.Max(p.shares, 1e-9))
+		if p.proceeds+value > p.cost {
+			hits++
+		}
+	}
+	res.Positions = len(order)
+	if res.Positions > 0 {
+		res.HitRate = float64(hits) / float64(res.Positions)
+	}
+
+	res.FinalEquity = equityAt(end)
+	res.ProfitLoss = res.FinalEquity - b.StartingCash
+	if b.StartingCash > 0 {
+		res.Return = res.ProfitLoss / b.StartingCash
+	}
+	if len(res.Curve) > 0 && !end.Equal(res.Curve[len(res.Curve)-1].Time) {
+		res.Curve = append(res.Curve, EquityPoint{Time: end, Equity: res.FinalEquity})
+		if peak > 0 {
+			res.MaxDrawdown = math.Max(res.MaxDrawdown, (peak-res.FinalEquity)/peak)
+		}
+	}
+	return res
+}
+
+// notional is the cash to commit when copying a buy under the configured sizing rule.
+func (b *Backtester) notional(t db.Trade, winRate, equity float64) float64 {
+	switch b.Sizing {
+	case SizingProportional:
+		return b.Ratio * t.Price * t.Size
+	case SizingKelly:
+		return math.Min(kellyFraction(winRate, t.Price), b.KellyCap) * equity
+	default:
+		return b.FixedNotional
+	}
+}
+
+// kellyFraction is the Kelly stake for a binary contract bought at price that pays
+// 1 with probability p: f* = (p - price) / (1 - price). Negative edges stake nothing.
+func kellyFraction(p, price float64) float64 {
+	if price <= 0 || price >= 1 {
+		return 0
+	}
+	return math.Max(0, (p-price)/(1-price))
+}
+
+func normalizeSide(side string) string {
+	if strings.EqualFold(side, "no") {
+		return "NO"
+	}
+	return "YES"
+}
+
+func yesPrice(side string, price float64) float64 {
+	if normalizeSide(side) == "NO" {
+		return 1 - price
+	}
+	return price
+}
+
+func sidePrice(side string, yes float64) float64 {
+	if side == "NO" {
+		return 1 - yes
+	}
+	return yes
+}
@@ -0,0 +1,139 @@
+package backtest
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+	"polytracker/internal/polymarket"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacktester_Run(t *testing.T) {
+	dbPath := "test_backtest.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xa", WinRate: 0.6, LastScanned: start}))
+
+	// m1: bought at 0.40 and sold at 0.60, a closed winner.
+	// m2: bought at 0.50 and left open while the price falls to 0.25.
+	for _, tr := range []db.Trade{
+		{ID: "t1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.40, Size: 100, Timestamp: start.Add(time.Minute)},
+		{ID: "t2", TraderID: "0xa", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.60, Size: 100, Timestamp: start.Add(3 * time.Hour)},
+		{ID: "t3", TraderID: "0xa", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.50, Size: 100, Timestamp: start.Add(4 * time.Hour)},
+	} {
+		require.NoError(t, database.SaveTrade(&tr))
+	}
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{
+		MarketID: "m2", YesPrice: 0.25, NoPrice: 0.75, Timestamp: start.Add(5 * time.Hour),
+	}))
+
+	bt := NewBacktester(database)
+	bt.Slippage = SpreadModel{}
+	res, err := bt.Run([]string{"0xa"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, res.Fills)
+	assert.Equal(t, 2, res.Positions)
+	assert.InDelta(t, 0.5, res.HitRate, 0.001)
+	assert.InDelta(t, 10000.0, res.FinalEquity, 0.001)
+	assert.InDelta(t, 0.0, res.ProfitLoss, 0.001)
+	assert.InDelta(t, 50.0/10050.0, res.MaxDrawdown, 0.0001)
+	require.NotEmpty(t, res.Curve)
+	assert.Equal(t, start.Add(5*time.Hour), res.Curve[len(res.Curve)-1].Time)
+
+	// A delay long enough to miss the exit print fills the sell at the later price.
+	bt.Delay = 90 * time.Minute
+	bt.Sizing = SizingKelly
+	res, err = bt.Run([]string{"0xa"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Fills)
+	assert.Less(t, res.FinalEquity, DefaultStartingCash+DefaultKellyCap*DefaultStartingCash)
+}
+
+func TestBacktester_SkipsSellsWithoutPosition(t *testing.T) {
+	bt := &Backtester{StartingCash: 1000, Sizing: SizingFixed, FixedNotional: 100, Slippage: SpreadModel{}}
+	now := time.Now()
+	res := bt.replay([]db.Trade{
+		{TraderID: "0xa", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.5, Size: 10, Timestamp: now},
+	}, map[string][]pricePoint{}, nil)
+
+	assert.Equal(t, 0, res.Fills)
+	assert.Equal(t, 1, res.Skipped)
+	assert.Equal(t, 1000.0, res.FinalEquity)
+}
+
+func TestBacktester_SkipsZeroPricedSide(t *testing.T) {
+	bt := &Backtester{StartingCash: 1000, Sizing: SizingFixed, FixedNotional: 100, Slippage: SpreadModel{}}
+	now := time.Now()
+	// The market has resolved YES, so NO is worth nothing.
+	res := bt.replay([]db.Trade{
+		{TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.1, Size: 10, Timestamp: now},
+	}, map[string][]pricePoint{"m1": {{at: now.Add(-time.Hour), price: 1}}}, nil)
+
+	assert.Equal(t, 0, res.Fills)
+	assert.Equal(t, 1, res.Skipped)
+	assert.Equal(t, 1000.0, res.FinalEquity)
+	assert.Equal(t, 0.0, res.MaxDrawdown)
+}
+
+func TestSizing(t *testing.T) {
+	trade := db.Trade{Price: 0.4, Size: 500}
+
+	bt := &Backtester{Sizing: SizingProportional, Ratio: 0.1}
+	assert.InDelta(t, 20.0, bt.notional(trade, 0, 1000), 0.001)
+
+	bt = &Backtester{Sizing: SizingKelly, KellyCap: 0.05}
+	assert.InDelta(t, 50.0, bt.notional(trade, 0.6, 1000), 0.001)
+	assert.Equal(t, 0.0, bt.notional(trade, 0.3, 1000))
+
+	assert.InDelta(t, 1.0/3.0, kellyFraction(0.6, 0.4), 0.001)
+
+	_, err := ParseSizingRule("martingale")
+	assert.Error(t, err)
+	rule, err := ParseSizingRule("Kelly")
+	require.NoError(t, err)
+	assert.Equal(t, SizingKelly, rule)
+}
+
+func TestSlippageModels(t *testing.T) {
+	spread := SpreadModel{Bps: 100}
+	assert.InDelta(t, 0.505, spread.FillPrice("m1", "YES", true, 0.5, 10), 0.0001)
+	assert.InDelta(t, 0.495, spread.FillPrice("m1", "YES", false, 0.5, 10), 0.0001)
+
+	book := BookModel{
+		Books: map[BookKey]*polymarket.Orderbook{
+			NewBookKey("m1", "YES"): {Asks: []polymarket.Level{{Price: 0.50, Size: 100}, {Price: 0.60, Size: 100}}},
+			NewBookKey("m1", "no"):  {Asks: []polymarket.Level{{Price: 0.30, Size: 10}, {Price: 0.40, Size: 1000}}},
+		},
+		Fallback: spread,
+	}
+	// 200 shares: half at 0.50, half at 0.60, average 0.55 -> 0.05 impact.
+	assert.InDelta(t, 0.45, book.FillPrice("m1", "YES", true, 0.40, 200), 0.0001)
+	assert.InDelta(t, 0.40, book.FillPrice("m1", "YES", true, 0.40, 50), 0.0001)
+	assert.InDelta(t, 0.505, book.FillPrice("m2", "YES", true, 0.5, 10), 0.0001)
+	// NO copies walk the NO book: 100 shares average 0.39, 0.09 above its top.
+	assert.InDelta(t, 0.39, book.FillPrice("m1", "NO", true, 0.30, 100), 0.0001)
+}
+
+func TestBookModel_UnsortedBook(t *testing.T) {
+	book := BookModel{Books: map[BookKey]*polymarket.Orderbook{
+		NewBookKey("m1", "YES"): {
+			Asks: []polymarket.Level{{Price: 0.60, Size: 100}, {Price: 0.50, Size: 100}, {Price: 0.70, Size: 100}},
+			Bids: []polymarket.Level{{Price: 0.30, Size: 100}, {Price: 0.45, Size: 100}, {Price: 0.40, Size: 100}},
+		},
+	}}
+	// Buys fill from the lowest ask: 200 shares average 0.55, 0.05 above 0.50.
+	assert.InDelta(t, 0.45, book.FillPrice("m1", "YES", true, 0.40, 200), 0.0001)
+	// Sells fill from the highest bid: 200 shares average 0.425, 0.025 below 0.45.
+	assert.InDelta(t, 0.425, book.FillPrice("m1", "YES", false, 0.45, 200), 0.0001)
+	// The book itself is left as the API listed it.
+	assert.Equal(t, 0.60, book.Books[NewBookKey("m1", "YES")].Asks[0].Price)
+}
@@ -0,0 +1,116 @@
+package backtest
+
+import (
+	"math"
+	"sort"
+
+	"polytracker/internal/polymarket"
+)
+
+// SlippageModel turns the price observed at fill time into the price actually
+// paid (buys) or received (sells) for a copied order of the given share count
+// of the side ("YES" or "NO") traded.
+type SlippageModel interface {
+	FillPrice(marketID, side string, buy bool, reference, shares float64) float64
+}
+
+// SpreadModel crosses a fixed half-spread, in basis points of the reference price.
+// The reference price itself comes from the market's snapshots and prints at the
+// delayed fill time, so delay slippage is captured before this model applies.
+type SpreadModel struct {
+	Bps float64
+}
+
+func (m SpreadModel) FillPrice(marketID, side string, buy bool, reference, shares float64) float64 {
+	adj := reference * m.Bps / 10000
+	if buy {
+		return clampPrice(reference + adj)
+	}
+	return clampPrice(reference - adj)
+}
+
+// BookKey identifies the order book of one side of a market.
+type BookKey struct {
+	MarketID string
+	Side     string
+}
+
+// NewBookKey returns the key of the market's book for side, normalized to
+// "YES" or "NO".
+func NewBookKey(marketID, side string) BookKey {
+	return BookKey{MarketID: marketID, Side: normalizeSide(side)}
+}
+
+// BookModel adds the price impact of walking an order book for the copied size on
+// top of the reference price. Each side of a market trades its own token, so books
+// are keyed by market and side; sides without a book fall back to Fallback.
+type BookModel struct {
+	Books    map[BookKey]*polymarket.Orderbook
+	Fallback SlippageModel
+}
+
+func (m BookModel) FillPrice(marketID, side string, buy bool, reference, shares float64) float64 {
+	var levels []polymarket.Level
+	if book := m.Books[NewBookKey(marketID, side)]; book != nil {
+		levels = book.Bids
+		if buy {
+			levels = book.Asks
+		}
+	}
+	if len(levels) == 0 {
+		if m.Fallback != nil {
+			return m.Fallback.FillPrice(marketID, side, buy, reference, shares)
+		}
+		return reference
+	}
+
+	impact := bookImpact(bestFirst(levels, buy), shares)
+	if buy {
+		return clampPrice(reference + impact)
+	}
+	return clampPrice(reference - impact)
+}
+
+// bestFirst returns a copy of the levels ordered from the top of book: asks
+// ascending for buys, bids descending for sells. The API does not guarantee
+// the order it lists them in.
+func bestFirst(levels []polymarket.Level, buy bool) []polymarket.Level {
+	sorted := append([]polymarket.Level(nil), levels...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if buy {
+			return sorted[i].Price < sorted[j].Price
+		}
+		return sorted[i].Price > sorted[j].Price
+	})
+	return sorted
+}
+
+// bookImpact is how far the average fill price for shares sits from the top of
+// book, given levels ordered best first. Size beyond the visible depth fills at
+// the last level.
+func bookImpact(levels []polymarket.Level, shares float64) float64 {
+	best := levels[0].Price
+	remaining := shares
+	cost := 0.0
+	last := best
+	for _, l := range levels {
+		if remaining <= 0 {
+			break
+		}
+		take := math.Min(remaining, l.Size)
+		cost += take * l.Price
+		remaining -= take
+		last = l.Price
+	}
+	if remaining > 0 {
+		cost += remaining * last
+	}
+	if shares <= 0 {
+		return 0
+	}
+	return math.Abs(cost/shares - best)
+}
+
+func clampPrice(p float64) float64 {
+	return math.Max(0.001, math.Min(0.999, p))
+}
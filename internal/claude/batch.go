@@ -0,0 +1,153 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// BatchDiscount is the share of the standard price charged for requests sent
+// through the Message Batches API.
+const BatchDiscount = 0.5
+
+// ErrEmptyBatch is returned when a batch is submitted without traders.
+var ErrEmptyBatch = errors.New("a batch needs at least one trader")
+
+// BatchStatus is the progress of a submitted batch.
+type BatchStatus struct {
+	ID string
+	// Ended is set once every request has finished; results are then available.
+	Ended      bool
+	Processing int64
+	Succeeded  int64
+	Errored    int64
+	Canceled   int64
+	Expired    int64
+}
+
+// BatchResult is the outcome of one trader's request in an ended batch.
+// Result is nil when Err is set, except for a truncated thesis, which is
+// returned together with ErrTokenLimit like AnalyzeTrader does.
+type BatchResult struct {
+	TraderID string
+	Result   *AnalysisResult
+	Err      error
+}
+
+// SubmitBatch renders the thesis prompt for each trader and submits them as
+// one message batch, identifying each request by the trader's address. The
+// returned record holds the batch ID and every prompt, so results can be
+// collected after a restart; its Source is left for the caller to set.
+func (c *Client) SubmitBatch(ctx context.Context, traders []TraderData) (*db.AnalysisBatch, error) {
+	if len(traders) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	batch := &db.AnalysisBatch{
+		Model:          c.config.Model,
+		PromptTemplate: c.config.Template.Name,
+		PromptHash:     c.config.Template.Hash,
+	}
+	requests := make([]anthropic.MessageBatchNewParamsRequest, 0, len(traders))
+	for _, data := range traders {
+		if data.Trader == nil {
+			return nil, ErrInvalidTrader
+		}
+		params, prompt, err := c.thesisParams(data)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, anthropic.MessageBatchNewParamsRequest{
+			CustomID: data.Trader.Address,
+			Params: anthropic.MessageBatchNewParamsRequestParams{
+				Model:       params.Model,
+				MaxTokens:   params.MaxTokens,
+				System:      params.System,
+				Messages:    params.Messages,
+				Tools:       params.Tools,
+				Temperature: params.Temperature,
+			},
+		})
+		batch.Items = append(batch.Items, db.AnalysisBatchItem{TraderID: data.Trader.Address, Prompt: prompt.Text})
+	}
+
+	resp, err := c.client.Messages.Batches.New(ctx, anthropic.MessageBatchNewParams{Requests: requests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit batch: %w", err)
+	}
+	batch.ID = resp.ID
+	batch.CreatedAt = resp.CreatedAt
+	return batch, nil
+}
+
+// GetBatch returns the progress of a submitted batch.
+func (c *Client) GetBatch(ctx context.Context, id string) (*BatchStatus, error) {
+	resp, err := c.client.Messages.Batches.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+	counts := resp.RequestCounts
+	return &BatchStatus{
+		ID:         resp.ID,
+		Ended:      resp.ProcessingStatus == anthropic.MessageBatchProcessingStatusEnded,
+		Processing: counts.Processing,
+		Succeeded:  counts.Succeeded,
+		Errored:    counts.Errored,
+		Canceled:   counts.Canceled,
+		Expired:    counts.Expired,
+	}, nil
+}
+
+// BatchResults fetches the results of an ended batch. Each result records the
+// prompt and template the batch was submitted with, and is costed at the
+// batch discount. Results for traders not in the batch are skipped.
+func (c *Client) BatchResults(ctx context.Context, batch *db.AnalysisBatch) ([]BatchResult, error) {
+	prompts := make(map[string]string, len(batch.Items))
+	for _, item := range batch.Items {
+		prompts[item.TraderID] = item.Prompt
+	}
+
+	stream := c.client.Messages.Batches.ResultsStreaming(ctx, batch.ID)
+	defer stream.Close()
+
+	var results []BatchResult
+	for stream.Next() {
+		resp := stream.Current()
+		prompt, ok := prompts[resp.CustomID]
+		if !ok {
+			continue
+		}
+		results = append(results, c.batchResult(resp, batch, prompt))
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch results: %w", err)
+	}
+	return results, nil
+}
+
+func (c *Client) batchResult(resp anthropic.MessageBatchIndividualResponse, batch *db.AnalysisBatch, prompt string) BatchResult {
+	br := BatchResult{TraderID: resp.CustomID}
+	switch resp.Result.Type {
+	case "succeeded":
+		message := resp.Result.Message
+		result, err := c.messageResult(&message, nil, PromptVersion)
+		if result != nil {
+			result.CostUSD *= BatchDiscount
+			result.PromptTemplate, result.PromptHash = batch.PromptTemplate, batch.PromptHash
+			result.Summary, result.SummaryErr = findSummary(&message)
+			result.Prompt = prompt
+		}
+		br.Result, br.Err = result, err
+	case "errored":
+		metrics.ObserveClaudeUsage("error", "", 0, 0)
+		br.Err = fmt.Errorf("request failed: %s", resp.Result.Error.Error.Message)
+	default:
+		br.Err = fmt.Errorf("request %s", resp.Result.Type)
+	}
+	return br
+}
@@ -0,0 +1,101 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitBatch_Empty(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "test-api-key"})
+	require.NoError(t, err)
+
+	_, err = client.SubmitBatch(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrEmptyBatch)
+}
+
+func TestBatch_MockAPI(t *testing.T) {
+	var submitted struct {
+		Requests []struct {
+			CustomID string `json:"custom_id"`
+			Params   struct {
+				Model string            `json:"model"`
+				Tools []json.RawMessage `json:"tools"`
+			} `json:"params"`
+		} `json:"requests"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages/batches", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msgbatch_1","type":"message_batch","processing_status":"in_progress","created_at":"2026-01-02T03:04:05Z","request_counts":{"processing":3}}`)
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msgbatch_1","type":"message_batch","processing_status":"ended","request_counts":{"processing":0,"succeeded":1,"errored":1,"expired":1}}`)
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1/results", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-jsonl")
+		fmt.Fprintln(w, `{"custom_id":"0xa","result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"Patient whale."}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":1000000,"output_tokens":0}}}}`)
+		fmt.Fprintln(w, `{"custom_id":"0xb","result":{"type":"errored","error":{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}}}`)
+		fmt.Fprintln(w, `{"custom_id":"0xc","result":{"type":"expired"}}`)
+		fmt.Fprintln(w, `{"custom_id":"0xunknown","result":{"type":"expired"}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		APIKey:   "test-api-key",
+		Endpoint: server.URL,
+		Model:    "claude-test",
+		Prices:   map[string]Price{"claude-test": {Input: 3}},
+	})
+	require.NoError(t, err)
+
+	batch, err := client.SubmitBatch(context.Background(), []TraderData{
+		{Trader: &db.Trader{Address: "0xa"}},
+		{Trader: &db.Trader{Address: "0xb"}},
+		{Trader: &db.Trader{Address: "0xc"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "msgbatch_1", batch.ID)
+	assert.Equal(t, "claude-test", batch.Model)
+	assert.Equal(t, defaultTemplate.Name, batch.PromptTemplate)
+	assert.Equal(t, defaultTemplate.Hash, batch.PromptHash)
+	require.Len(t, batch.Items, 3)
+	assert.Equal(t, "0xa", batch.Items[0].TraderID)
+	assert.Contains(t, batch.Items[0].Prompt, "0xa")
+
+	require.Len(t, submitted.Requests, 3)
+	assert.Equal(t, "0xb", submitted.Requests[1].CustomID)
+	assert.Equal(t, "claude-test", submitted.Requests[1].Params.Model)
+	assert.Len(t, submitted.Requests[1].Params.Tools, 1)
+
+	status, err := client.GetBatch(context.Background(), "msgbatch_1")
+	require.NoError(t, err)
+	assert.True(t, status.Ended)
+	assert.Equal(t, int64(1), status.Succeeded)
+	assert.Equal(t, int64(1), status.Expired)
+
+	results, err := client.BatchResults(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "Patient whale.", results[0].Result.Thesis)
+	assert.Equal(t, batch.Items[0].Prompt, results[0].Result.Prompt)
+	assert.Equal(t, defaultTemplate.Hash, results[0].Result.PromptHash)
+	assert.InDelta(t, 1.5, results[0].Result.CostUSD, 1e-9, "batch requests cost half price")
+
+	assert.Equal(t, "0xb", results[1].TraderID)
+	assert.ErrorContains(t, results[1].Err, "Overloaded")
+	assert.ErrorContains(t, results[2].Err, "expired")
+}
@@ -0,0 +1,95 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// ChatPromptVersion identifies the system instructions sent with follow-up
+// questions; bump it when they change.
+const ChatPromptVersion = "chat-v1"
+
+var (
+	ErrEmptyQuestion  = errors.New("question is empty")
+	ErrNoConversation = errors.New("conversation needs the original prompt and thesis")
+)
+
+const chatInstructions = `You wrote the trading thesis earlier in this conversation from the trader data in the first message.
+Answer the user's follow-up questions about the trader concisely, grounded in that data. Say so when the data cannot answer a question.`
+
+// Conversation is the history a follow-up question builds on: the prompt the
+// thesis answered, the thesis, and earlier follow-ups alternating between
+// user and assistant, oldest first.
+type Conversation struct {
+	Prompt   string
+	Thesis   string
+	Messages []db.AnalysisMessage
+}
+
+// StreamChat asks a follow-up question about an analysis and streams the
+// reply like StreamAnalyzeTrader. The final Result holds the reply as its
+// Thesis along with its usage.
+func (c *Client) StreamChat(ctx context.Context, conv Conversation, question string) <-chan StreamEvent {
+	params, err := c.chatParams(conv, question)
+	if err != nil {
+		return failedStream(err)
+	}
+	return c.streamMessage(ctx, params, func(resp *anthropic.Message) (*AnalysisResult, error) {
+		return c.messageResult(resp, nil, ChatPromptVersion)
+	})
+}
+
+func (c *Client) chatParams(conv Conversation, question string) (anthropic.MessageNewParams, error) {
+	if strings.TrimSpace(question) == "" {
+		return anthropic.MessageNewParams{}, ErrEmptyQuestion
+	}
+	if conv.Prompt == "" || conv.Thesis == "" {
+		return anthropic.MessageNewParams{}, ErrNoConversation
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(conv.Prompt)),
+		anthropic.NewAssistantMessage(anthropic.NewTextBlock(conv.Thesis)),
+	}
+	for _, m := range conv.Messages {
+		switch m.Role {
+		case db.MessageRoleUser:
+			messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(m.Content)))
+		case db.MessageRoleAssistant:
+			messages = append(messages, anthropic.NewAssistantMessage(anthropic.NewTextBlock(m.Content)))
+		default:
+			return anthropic.MessageNewParams{}, fmt.Errorf("unknown message role: %s", m.Role)
+		}
+	}
+	messages = append(messages, anthropic.NewUserMessage(anthropic.NewTextBlock(question)))
+
+	return c.baseParams(chatInstructions, messages...), nil
+}
+
+// Messages returns the question and reply as conversation turns of the
+// analysis, ready to be saved.
+func (r *AnalysisResult) Messages(analysisID int64, question string) (*db.AnalysisMessage, *db.AnalysisMessage) {
+	asked := &db.AnalysisMessage{
+		AnalysisID: analysisID,
+		Role:       db.MessageRoleUser,
+		Content:    question,
+		CreatedAt:  r.CreatedAt,
+	}
+	reply := &db.AnalysisMessage{
+		AnalysisID:   analysisID,
+		Role:         db.MessageRoleAssistant,
+		Content:      r.Thesis,
+		Model:        r.Model,
+		InputTokens:  r.InputTokens,
+		OutputTokens: r.OutputTokens,
+		CostUSD:      r.CostUSD,
+		CreatedAt:    r.CreatedAt,
+	}
+	return asked, reply
+}
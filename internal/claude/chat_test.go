@@ -0,0 +1,99 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatParams(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "test-api-key"})
+	require.NoError(t, err)
+
+	conv := Conversation{
+		Prompt: "trader data",
+		Thesis: "the thesis",
+		Messages: []db.AnalysisMessage{
+			{Role: db.MessageRoleUser, Content: "first question"},
+			{Role: db.MessageRoleAssistant, Content: "first answer"},
+		},
+	}
+	params, err := client.chatParams(conv, "second question")
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(params)
+	require.NoError(t, err)
+	var body struct {
+		System []struct {
+			Text string `json:"text"`
+		} `json:"system"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+		Tools []json.RawMessage `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &body))
+
+	var turns []string
+	for _, m := range body.Messages {
+		require.Len(t, m.Content, 1)
+		turns = append(turns, m.Role+": "+m.Content[0].Text)
+	}
+	assert.Equal(t, []string{
+		"user: trader data",
+		"assistant: the thesis",
+		"user: first question",
+		"assistant: first answer",
+		"user: second question",
+	}, turns)
+	require.Len(t, body.System, 1)
+	assert.Equal(t, chatInstructions, body.System[0].Text)
+	assert.Empty(t, body.Tools)
+
+	_, err = client.chatParams(conv, "  ")
+	assert.ErrorIs(t, err, ErrEmptyQuestion)
+	_, err = client.chatParams(Conversation{Thesis: "the thesis"}, "why?")
+	assert.ErrorIs(t, err, ErrNoConversation)
+	conv.Messages = append(conv.Messages, db.AnalysisMessage{Role: "system", Content: "x"})
+	_, err = client.chatParams(conv, "why?")
+	assert.Error(t, err)
+}
+
+func TestStreamChat(t *testing.T) {
+	server := sseServer(t, "end_turn", "Mostly ", "sports.")
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	var text strings.Builder
+	var final StreamEvent
+	for ev := range client.StreamChat(context.Background(), Conversation{Prompt: "p", Thesis: "t"}, "What do they trade?") {
+		text.WriteString(ev.Text)
+		final = ev
+	}
+	require.NoError(t, final.Err)
+	require.NotNil(t, final.Result)
+	assert.Equal(t, "Mostly sports.", text.String())
+	assert.Equal(t, ChatPromptVersion, final.Result.PromptVersion)
+	assert.Empty(t, final.Result.PromptTemplate)
+
+	asked, reply := final.Result.Messages(7, "What do they trade?")
+	assert.Equal(t, db.MessageRoleUser, asked.Role)
+	assert.Equal(t, "What do they trade?", asked.Content)
+	assert.Equal(t, int64(7), reply.AnalysisID)
+	assert.Equal(t, "Mostly sports.", reply.Content)
+	assert.Equal(t, int64(42), reply.OutputTokens)
+
+	ev := <-client.StreamChat(context.Background(), Conversation{Prompt: "p", Thesis: "t"}, "")
+	assert.ErrorIs(t, ev.Err, ErrEmptyQuestion)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"polytracker/internal/db"
+	"polytracker/internal/metrics"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -20,10 +21,29 @@ var (
 	ErrInvalidTrader  = errors.New("trader data is invalid or missing")
 )
 
+// Defaults used when Config leaves the model or token limit unset.
+const (
+	DefaultModel     = "claude-sonnet-4-20250514"
+	DefaultMaxTokens = 4096
+)
+
 // Config holds the configuration for the Claude client.
 type Config struct {
 	APIKey   string
 	Endpoint string
+	// Model is the model ID to use; DefaultModel when empty.
+	Model string
+	// MaxTokens caps the length of each response; DefaultMaxTokens when zero.
+	MaxTokens int64
+	// Temperature is sent with each request when set; nil uses the API default.
+	Temperature *float64
+	// Prices is used to cost each request; see Price.
+	Prices map[string]Price
+	// Template renders the thesis prompt; the built-in prompt when nil.
+	Template *PromptTemplate
+	// ContextBudget caps the estimated prompt size in tokens;
+	// DefaultContextBudget when zero.
+	ContextBudget int
 }
 
 // Client wraps the Anthropic SDK client.
@@ -45,6 +65,18 @@ func NewClient(cfg Config) (*Client, error) {
 	if cfg.Endpoint != "" {
 		opts = append(opts, option.WithBaseURL(cfg.Endpoint))
 	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.MaxTokens == 0 {
+		cfg.MaxTokens = DefaultMaxTokens
+	}
+	if cfg.Template == nil {
+		cfg.Template = defaultTemplate
+	}
+	if cfg.ContextBudget == 0 {
+		cfg.ContextBudget = DefaultContextBudget
+	}
 
 	client := anthropic.NewClient(opts...)
 
@@ -59,6 +91,10 @@ type TraderData struct {
 	Trader  *db.Trader
 	Trades  []db.Trade
 	Markets map[string]*db.Market
+	Profile *db.TraderProfile
+	// Snapshots holds the latest snapshot of each traded market, used to
+	// mark open positions and trades; markets without one may be omitted.
+	Snapshots map[string]*db.MarketSnapshot
 }
 
 // AnalysisResult contains the result of a trader analysis.
@@ -69,6 +105,19 @@ type AnalysisResult struct {
 	OutputTokens int64
 	StopReason  string
 	CreatedAt   time.Time
+	// CostUSD is the request's price under Config.Prices; zero for unpriced models.
+	CostUSD       float64
+	PromptVersion string
+	// PromptTemplate and PromptHash identify the template that rendered the prompt.
+	PromptTemplate string
+	PromptHash     string
+	// Summary is the structured summary Claude recorded with the summary
+	// tool. When it is nil, SummaryErr explains why; the thesis is still usable.
+	Summary     *db.ThesisSummary
+	SummaryErr  error
+	// Prompt is the rendered prompt the thesis answers; empty for comparisons
+	// and chat replies.
+	Prompt string
 }
 
 // AnalyzeTrader generates a trading thesis for the given trader using Claude.
@@ -77,20 +126,163 @@ func (c *Client) AnalyzeTrader(ctx context.Context, data TraderData) (*AnalysisR
 		return nil, ErrInvalidTrader
 	}
 
-	prompt := GenerateThesisPrompt(data)
+	params, prompt, err := c.thesisParams(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		metrics.ObserveClaudeUsage("error", "", 0, 0)
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+	return c.analysisResult(resp, prompt)
+}
+
+// StreamEvent is one event of a streamed analysis. Text events carry a delta
+// of the thesis; the final event carries the Result (and ErrTokenLimit if the
+// thesis was truncated) or an Err.
+type StreamEvent struct {
+	Text   string
+	Result *AnalysisResult
+	Err    error
+}
+
+// StreamAnalyzeTrader is AnalyzeTrader with the thesis streamed as it is
+// written. The returned channel yields text deltas followed by exactly one
+// final event, then closes. Cancelling ctx aborts the stream.
+func (c *Client) StreamAnalyzeTrader(ctx context.Context, data TraderData) <-chan StreamEvent {
+	if data.Trader == nil {
+		return failedStream(ErrInvalidTrader)
+	}
+	params, prompt, err := c.thesisParams(data)
+	if err != nil {
+		return failedStream(err)
+	}
+	return c.streamMessage(ctx, params, func(resp *anthropic.Message) (*AnalysisResult, error) {
+		return c.analysisResult(resp, prompt)
+	})
+}
+
+// failedStream returns a closed stream holding only err.
+func failedStream(err error) <-chan StreamEvent {
+	events := make(chan StreamEvent, 1)
+	events <- StreamEvent{Err: err}
+	close(events)
+	return events
+}
+
+// streamMessage streams a request, forwarding text deltas, and finishes with
+// the event that finish builds from the completed message.
+func (c *Client) streamMessage(ctx context.Context, params anthropic.MessageNewParams, finish func(*anthropic.Message) (*AnalysisResult, error)) <-chan StreamEvent {
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+		send := func(ev StreamEvent) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			select {
+			case events <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		stream := c.client.Messages.NewStreaming(ctx, params)
+		defer stream.Close()
 
+		var message anthropic.Message
+		for stream.Next() {
+			event := stream.Current()
+			if err := message.Accumulate(event); err != nil {
+				metrics.ObserveClaudeUsage("error", "", 0, 0)
+				send(StreamEvent{Err: fmt.Errorf("failed to read stream: %w", err)})
+				return
+			}
+			if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+				if text, ok := delta.Delta.AsAny().(anthropic.TextDelta); ok && text.Text != "" {
+					if !send(StreamEvent{Text: text.Text}) {
+						return
+					}
+				}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			metrics.ObserveClaudeUsage("error", "", 0, 0)
+			send(StreamEvent{Err: fmt.Errorf("failed to stream message: %w", err)})
+			return
+		}
+
+		result, err := finish(&message)
+		send(StreamEvent{Result: result, Err: err})
+	}()
+	return events
+}
+
+// Model returns the model the client requests.
+func (c *Client) Model() string {
+	return c.config.Model
+}
+
+// Template returns the template the client renders prompts with.
+func (c *Client) Template() *PromptTemplate {
+	return c.config.Template
+}
+
+// BuildPrompt renders the prompt AnalyzeTrader would send for the trader,
+// so its size can be reported before the call.
+func (c *Client) BuildPrompt(data TraderData) (*Prompt, error) {
+	return c.config.Template.Render(data, c.config.ContextBudget)
+}
+
+// baseParams returns a request for the client's model, token limit and
+// temperature with the given system instructions and messages.
+func (c *Client) baseParams(system string, messages ...anthropic.MessageParam) anthropic.MessageNewParams {
 	params := anthropic.MessageNewParams{
-		Model:     anthropic.Model("claude-sonnet-4-20250514"),
-		MaxTokens: 4096,
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-		},
+		Model:     anthropic.Model(c.config.Model),
+		MaxTokens: c.config.MaxTokens,
+		System:    []anthropic.TextBlockParam{{Text: system}},
+		Messages:  messages,
+	}
+	if c.config.Temperature != nil {
+		params.Temperature = anthropic.Float(*c.config.Temperature)
 	}
+	return params
+}
 
-	resp, err := c.client.Messages.New(ctx, params)
+func (c *Client) thesisParams(data TraderData) (anthropic.MessageNewParams, *Prompt, error) {
+	prompt, err := c.BuildPrompt(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create message: %w", err)
+		return anthropic.MessageNewParams{}, nil, err
 	}
+	params := c.baseParams(summaryInstructions, anthropic.NewUserMessage(anthropic.NewTextBlock(prompt.Text)))
+	params.Tools = []anthropic.ToolUnionParam{summaryTool()}
+	return params, prompt, nil
+}
+
+// analysisResult extracts the thesis and structured summary from a completed
+// message, keeping the prompt it answered. A truncated thesis is returned
+// together with ErrTokenLimit.
+func (c *Client) analysisResult(resp *anthropic.Message, prompt *Prompt) (*AnalysisResult, error) {
+	result, err := c.messageResult(resp, c.config.Template, PromptVersion)
+	if result != nil {
+		result.Summary, result.SummaryErr = findSummary(resp)
+		result.Prompt = prompt.Text
+	}
+	return result, err
+}
+
+// messageResult extracts the text from a completed message and records its
+// usage against the template and prompt version that produced it; tmpl is nil
+// for requests not rendered from a template. A truncated response is returned
+// together with ErrTokenLimit.
+func (c *Client) messageResult(resp *anthropic.Message, tmpl *PromptTemplate, version string) (*AnalysisResult, error) {
+	outcome := "ok"
+	if resp.StopReason == anthropic.StopReasonMaxTokens {
+		outcome = "truncated"
+	}
+	metrics.ObserveClaudeUsage(outcome, string(resp.Model), resp.Usage.InputTokens, resp.Usage.OutputTokens)
 
 	// Extract text content from response
 	var thesis strings.Builder
@@ -111,6 +303,11 @@ func (c *Client) AnalyzeTrader(ctx context.Context, data TraderData) (*AnalysisR
 		OutputTokens: resp.Usage.OutputTokens,
 		StopReason:   string(resp.StopReason),
 		CreatedAt:    time.Now(),
+		CostUSD:      cost(c.config.Prices, string(resp.Model), resp.Usage.InputTokens, resp.Usage.OutputTokens),
+		PromptVersion: version,
+	}
+	if tmpl != nil {
+		result.PromptTemplate, result.PromptHash = tmpl.Name, tmpl.Hash
 	}
 
 	// Check if response was truncated
@@ -121,68 +318,13 @@ func (c *Client) AnalyzeTrader(ctx context.Context, data TraderData) (*AnalysisR
 	return result, nil
 }
 
-// GenerateThesisPrompt creates a structured prompt for Claude to analyze a trader.
+// GenerateThesisPrompt renders the built-in thesis prompt for a trader within
+// the default context budget.
 func GenerateThesisPrompt(data TraderData) string {
-	var sb strings.Builder
-
-	sb.WriteString("You are an expert crypto trading analyst specializing in prediction markets. ")
-	sb.WriteString("Analyze the following trader's activity on Polymarket and generate a detailed trading thesis.\n\n")
-
-	// Trader profile section
-	sb.WriteString("## Trader Profile\n\n")
-	sb.WriteString(fmt.Sprintf("- **Address:** %s\n", data.Trader.Address))
-	if data.Trader.Username != "" {
-		sb.WriteString(fmt.Sprintf("- **Username:** %s\n", data.Trader.Username))
-	}
-	sb.WriteString(fmt.Sprintf("- **Win Rate:** %.2f%%\n", data.Trader.WinRate*100))
-	sb.WriteString(fmt.Sprintf("- **Profit/Loss:** $%.2f\n", data.Trader.ProfitLoss))
-	sb.WriteString(fmt.Sprintf("- **ROI:** %.2f%%\n", data.Trader.ROI*100))
-	sb.WriteString(fmt.Sprintf("- **Total Volume:** $%.2f\n", data.Trader.Volume))
-	sb.WriteString(fmt.Sprintf("- **Last Scanned:** %s\n\n", data.Trader.LastScanned.Format("2006-01-02 15:04:05")))
-
-	// Trading history section
-	sb.WriteString("## Recent Trading Activity\n\n")
-
-	if len(data.Trades) == 0 {
-		sb.WriteString("No trades available for analysis.\n\n")
-	} else {
-		// Limit to most recent 50 trades for prompt size management
-		tradesToShow := data.Trades
-		if len(tradesToShow) > 50 {
-			tradesToShow = tradesToShow[:50]
-		}
-
-		for i, trade := range tradesToShow {
-			marketQuestion := "Unknown Market"
-			if market, ok := data.Markets[trade.MarketID]; ok && market != nil {
-				marketQuestion = market.Question
-			}
-
-			sb.WriteString(fmt.Sprintf("### Trade %d\n", i+1))
-			sb.WriteString(fmt.Sprintf("- **Market:** %s\n", marketQuestion))
-			sb.WriteString(fmt.Sprintf("- **Type:** %s\n", trade.Type))
-			sb.WriteString(fmt.Sprintf("- **Side:** %s\n", trade.Side))
-			sb.WriteString(fmt.Sprintf("- **Price:** $%.4f\n", trade.Price))
-			sb.WriteString(fmt.Sprintf("- **Size:** %.4f\n", trade.Size))
-			sb.WriteString(fmt.Sprintf("- **Time:** %s\n\n", trade.Timestamp.Format("2006-01-02 15:04:05")))
-		}
-
-		if len(data.Trades) > 50 {
-			sb.WriteString(fmt.Sprintf("_(Showing 50 of %d total trades)_\n\n", len(data.Trades)))
-		}
+	// The built-in template only fails on a missing trader.
+	prompt, err := defaultTemplate.Render(data, DefaultContextBudget)
+	if err != nil {
+		return ""
 	}
-
-	// Analysis request
-	sb.WriteString("## Analysis Request\n\n")
-	sb.WriteString("Based on the trader profile and trading history above, please provide:\n\n")
-	sb.WriteString("1. **Trading Strategy Summary:** What patterns do you observe in their trading behavior?\n")
-	sb.WriteString("2. **Market Focus:** What types of markets or events does this trader focus on?\n")
-	sb.WriteString("3. **Risk Profile:** How would you characterize their risk tolerance and position sizing?\n")
-	sb.WriteString("4. **Timing Analysis:** Do they tend to trade early in market lifecycles or closer to resolution?\n")
-	sb.WriteString("5. **Strengths:** What appears to be working well in their strategy?\n")
-	sb.WriteString("6. **Weaknesses/Risks:** What potential weaknesses or risks do you identify?\n")
-	sb.WriteString("7. **Overall Thesis:** A concise thesis statement summarizing this trader's approach and edge.\n\n")
-	sb.WriteString("Please format your response in clear markdown sections.")
-
-	return sb.String()
+	return prompt.Text
 }
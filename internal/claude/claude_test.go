@@ -3,6 +3,7 @@ package claude
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -179,9 +180,9 @@ func TestGenerateThesisPrompt_ManyTrades(t *testing.T) {
 		LastScanned: time.Now(),
 	}
 
-	// Generate 100 trades
-	trades := make([]db.Trade, 100)
-	for i := 0; i < 100; i++ {
+	// Generate more trades than fit in the default context budget
+	trades := make([]db.Trade, 2000)
+	for i := 0; i < 2000; i++ {
 		trades[i] = db.Trade{
 			ID:        string(rune('A' + i)),
 			TraderID:  "0xheavytrader",
@@ -209,12 +210,15 @@ func TestGenerateThesisPrompt_ManyTrades(t *testing.T) {
 
 	prompt := GenerateThesisPrompt(data)
 
-	// Should show truncation notice
-	assert.Contains(t, prompt, "Showing 50 of 100 total trades")
-
-	// Should have exactly 50 trade sections
+	// Should sample trades to fit the budget and say so
 	count := strings.Count(prompt, "### Trade")
-	assert.Equal(t, 50, count)
+	assert.Greater(t, count, 0)
+	assert.Less(t, count, 2000)
+	assert.Contains(t, prompt, fmt.Sprintf("Showing %d of 2000 total trades", count))
+	assert.LessOrEqual(t, estimateTokens(prompt), DefaultContextBudget)
+
+	// Aggregates still cover every trade
+	assert.Contains(t, prompt, "| Test Market | uncategorized | YES | 2000 |")
 }
 
 func TestGenerateThesisPrompt_UnknownMarket(t *testing.T) {
@@ -247,6 +251,33 @@ func TestGenerateThesisPrompt_UnknownMarket(t *testing.T) {
 	assert.Contains(t, prompt, "Unknown Market")
 }
 
+func TestGenerateThesisPrompt_WithProfile(t *testing.T) {
+	data := TraderData{
+		Trader: &db.Trader{
+			Address:     "0xprofiled",
+			LastScanned: time.Now(),
+		},
+		Trades:  []db.Trade{},
+		Markets: make(map[string]*db.Market),
+		Profile: &db.TraderProfile{
+			TraderID:           "0xprofiled",
+			MedianHoldingHours: 36.5,
+			LateEntryRatio:     0.25,
+			ScaleInRatio:       0.5,
+			MomentumRatio:      0.6,
+			ContrarianRatio:    0.2,
+			Entries:            12,
+		},
+	}
+
+	prompt := GenerateThesisPrompt(data)
+
+	assert.Contains(t, prompt, "Behavioral Profile")
+	assert.Contains(t, prompt, "36.5 hours")
+	assert.Contains(t, prompt, "25.0%")
+	assert.Contains(t, prompt, "Momentum Entries")
+}
+
 func TestAnalyzeTrader_InvalidTrader(t *testing.T) {
 	client, err := NewClient(Config{APIKey: "test-key"})
 	require.NoError(t, err)
@@ -372,3 +403,124 @@ func TestAnalyzeTrader_TokenLimit(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Contains(t, result.Thesis, "Partial response")
 }
+
+// sseServer replays a Messages API stream that writes the given text deltas.
+func sseServer(t *testing.T, stopReason string, deltas ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		write := func(event, data string) {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+			w.(http.Flusher).Flush()
+		}
+		write("message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude-test","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":120,"output_tokens":1}}}`)
+		write("content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+		for _, d := range deltas {
+			text, _ := json.Marshal(d)
+			write("content_block_delta", fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":%s}}`, text))
+		}
+		write("content_block_stop", `{"type":"content_block_stop","index":0}`)
+		write("message_delta", fmt.Sprintf(`{"type":"message_delta","delta":{"stop_reason":%q,"stop_sequence":null},"usage":{"output_tokens":42}}`, stopReason))
+		write("message_stop", `{"type":"message_stop"}`)
+	}))
+}
+
+func TestStreamAnalyzeTrader(t *testing.T) {
+	server := sseServer(t, "end_turn", "## Strategy\n\n", "Buys ", "early.")
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	var text strings.Builder
+	var final *StreamEvent
+	for ev := range client.StreamAnalyzeTrader(context.Background(), TraderData{Trader: &db.Trader{Address: "0x123"}}) {
+		if ev.Result != nil || ev.Err != nil {
+			final = &ev
+			continue
+		}
+		text.WriteString(ev.Text)
+	}
+
+	assert.Equal(t, "## Strategy\n\nBuys early.", text.String())
+	require.NotNil(t, final)
+	require.NoError(t, final.Err)
+	assert.Equal(t, text.String(), final.Result.Thesis)
+	assert.Equal(t, "claude-test", final.Result.Model)
+	assert.Equal(t, int64(120), final.Result.InputTokens)
+	assert.Equal(t, int64(42), final.Result.OutputTokens)
+	assert.Equal(t, "end_turn", final.Result.StopReason)
+	assert.Contains(t, final.Result.Prompt, "0x123")
+}
+
+func TestStreamAnalyzeTrader_TokenLimit(t *testing.T) {
+	server := sseServer(t, "max_tokens", "Partial")
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	var final StreamEvent
+	for ev := range client.StreamAnalyzeTrader(context.Background(), TraderData{Trader: &db.Trader{Address: "0x123"}}) {
+		final = ev
+	}
+	assert.ErrorIs(t, final.Err, ErrTokenLimit)
+	require.NotNil(t, final.Result)
+	assert.Equal(t, "Partial", final.Result.Thesis)
+}
+
+func TestStreamAnalyzeTrader_Cancel(t *testing.T) {
+	server := sseServer(t, "end_turn", "one ", "two ", "three")
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := client.StreamAnalyzeTrader(ctx, TraderData{Trader: &db.Trader{Address: "0x123"}})
+	first := <-events
+	assert.Equal(t, "one ", first.Text)
+	cancel()
+
+	// The channel must close after cancellation without a result.
+	for ev := range events {
+		assert.Nil(t, ev.Result)
+	}
+}
+
+func TestAnalyzeTrader_RequestSettings(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"model":"claude-opus-4-1","stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`)
+	}))
+	defer server.Close()
+
+	data := TraderData{Trader: &db.Trader{Address: "0x123"}}
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+	_, err = client.AnalyzeTrader(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultModel, body["model"])
+	assert.Equal(t, float64(DefaultMaxTokens), body["max_tokens"])
+	assert.NotContains(t, body, "temperature")
+
+	temperature := 0.2
+	client, err = NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL, Model: "claude-opus-4-1", MaxTokens: 8000, Temperature: &temperature,
+		Prices: map[string]Price{"claude-opus-4": {Input: 15, Output: 75}}})
+	require.NoError(t, err)
+	assert.Equal(t, "claude-opus-4-1", client.Model())
+	result, err := client.AnalyzeTrader(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, "claude-opus-4-1", body["model"])
+	assert.Equal(t, float64(8000), body["max_tokens"])
+	assert.Equal(t, 0.2, body["temperature"])
+	assert.Equal(t, "claude-opus-4-1", result.Model)
+	assert.InDelta(t, (15.0+75.0)/1e6, result.CostUSD, 1e-12)
+	assert.Equal(t, PromptVersion, result.PromptVersion)
+}
@@ -0,0 +1,131 @@
+package claude
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Limits on the number of traders in one comparison.
+const (
+	MinCompareTraders = 2
+	MaxCompareTraders = 6
+)
+
+// ComparePromptVersion identifies the system instructions sent with every
+// comparison prompt; bump it when they change.
+const ComparePromptVersion = "compare-v1"
+
+// CompareTemplateName names the built-in comparison prompt.
+const CompareTemplateName = "compare"
+
+// ErrCompareTraders is returned when a comparison has too few or too many traders.
+var ErrCompareTraders = fmt.Errorf("a comparison needs between %d and %d traders", MinCompareTraders, MaxCompareTraders)
+
+//go:embed templates/compare.tmpl
+var compareTemplateSource string
+
+// compareTemplate frames the comparison. Each trader is rendered with its
+// associated "trader" template, fitted to an equal share of the budget.
+var compareTemplate = mustParsePromptTemplate(CompareTemplateName, compareTemplateSource)
+
+const compareInstructions = `You compare prediction market traders for a copy-trading research tool.
+Be specific and ground every claim in the statistics and trades provided.`
+
+// CompareData is the data the comparison template is executed with.
+type CompareData struct {
+	Traders []ComparedTrader
+}
+
+// ComparedTrader is one trader's part of a comparison prompt.
+type ComparedTrader struct {
+	Trader *db.Trader
+	// Section is the trader's rendered statistics and sampled trades.
+	Section string
+}
+
+// CompareTraders asks Claude to compare the traders. The result's Thesis holds
+// the comparison; it has no structured summary.
+func (c *Client) CompareTraders(ctx context.Context, traders []TraderData) (*AnalysisResult, error) {
+	prompt, err := c.BuildComparePrompt(traders)
+	if err != nil {
+		return nil, err
+	}
+
+	params := c.baseParams(compareInstructions, anthropic.NewUserMessage(anthropic.NewTextBlock(prompt.Text)))
+
+	resp, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		metrics.ObserveClaudeUsage("error", "", 0, 0)
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+	return c.messageResult(resp, compareTemplate, ComparePromptVersion)
+}
+
+// BuildComparePrompt renders the prompt CompareTraders would send. The
+// context budget is split evenly between the traders, after the shared frame.
+// Trade and market counts in the result are summed over all traders.
+func (c *Client) BuildComparePrompt(traders []TraderData) (*Prompt, error) {
+	return renderComparison(traders, c.config.ContextBudget)
+}
+
+func renderComparison(traders []TraderData, budget int) (*Prompt, error) {
+	if len(traders) < MinCompareTraders || len(traders) > MaxCompareTraders {
+		return nil, ErrCompareTraders
+	}
+	if budget <= 0 {
+		budget = DefaultContextBudget
+	}
+
+	cd := CompareData{Traders: make([]ComparedTrader, len(traders))}
+	for i, data := range traders {
+		if data.Trader == nil {
+			return nil, ErrInvalidTrader
+		}
+		cd.Traders[i].Trader = data.Trader
+	}
+	frame, err := executeComparison(cd)
+	if err != nil {
+		return nil, err
+	}
+	share := (budget - estimateTokens(frame)) / len(traders)
+	if share < 1 {
+		share = 1
+	}
+
+	prompt := &Prompt{Budget: budget}
+	section := compareTemplate.tmpl.Lookup("trader")
+	for i, data := range traders {
+		p, err := compareTemplate.fit(section, data, share)
+		if err != nil {
+			return nil, err
+		}
+		cd.Traders[i].Section = p.Text
+		prompt.SampledTrades += p.SampledTrades
+		prompt.TotalTrades += p.TotalTrades
+		prompt.ShownMarkets += p.ShownMarkets
+		prompt.TotalMarkets += p.TotalMarkets
+	}
+
+	text, err := executeComparison(cd)
+	if err != nil {
+		return nil, err
+	}
+	prompt.Text = text
+	prompt.EstimatedTokens = estimateTokens(text)
+	return prompt, nil
+}
+
+func executeComparison(cd CompareData) (string, error) {
+	var sb strings.Builder
+	if err := compareTemplate.tmpl.Execute(&sb, cd); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", CompareTemplateName, err)
+	}
+	return sb.String(), nil
+}
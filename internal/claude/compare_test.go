@@ -0,0 +1,83 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderComparison(t *testing.T) {
+	big := contextFixture(2000)
+	small := TraderData{
+		Trader: &db.Trader{Address: "0xsmall", Username: "smalltime", WinRate: 0.6},
+		Trades: []db.Trade{{ID: "s1", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.3, Size: 20}},
+		Markets: map[string]*db.Market{
+			"m1": {ID: "m1", Question: "Will it rain?", Category: "weather"},
+		},
+	}
+
+	prompt, err := renderComparison([]TraderData{big, small}, 6000)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, prompt.EstimatedTokens, 6000)
+	assert.Equal(t, 2001, prompt.TotalTrades)
+	assert.Less(t, prompt.SampledTrades, prompt.TotalTrades)
+
+	assert.Contains(t, prompt.Text, "Compare the following 2 Polymarket traders")
+	assert.Contains(t, prompt.Text, "## Trader 1: 0xprolific")
+	assert.Contains(t, prompt.Text, "## Trader 2: smalltime")
+	assert.Contains(t, prompt.Text, `in "Will it rain?"`)
+	assert.Contains(t, prompt.Text, "- weather: 1 trades")
+	assert.Less(t, strings.Index(prompt.Text, "0xprolific"), strings.Index(prompt.Text, "0xsmall"))
+
+	_, err = renderComparison([]TraderData{big}, 6000)
+	assert.ErrorIs(t, err, ErrCompareTraders)
+	_, err = renderComparison(make([]TraderData, MaxCompareTraders+1), 6000)
+	assert.ErrorIs(t, err, ErrCompareTraders)
+	_, err = renderComparison([]TraderData{big, {}}, 6000)
+	assert.ErrorIs(t, err, ErrInvalidTrader)
+}
+
+func TestCompareTraders_MockAPI(t *testing.T) {
+	var body struct {
+		System []struct {
+			Text string `json:"text"`
+		} `json:"system"`
+		Tools []json.RawMessage `json:"tools"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"Trader 1 wins."}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":500,"output_tokens":50}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	result, err := client.CompareTraders(context.Background(), []TraderData{
+		{Trader: &db.Trader{Address: "0xa"}},
+		{Trader: &db.Trader{Address: "0xb"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Trader 1 wins.", result.Thesis)
+	assert.Equal(t, ComparePromptVersion, result.PromptVersion)
+	assert.Equal(t, CompareTemplateName, result.PromptTemplate)
+	assert.Nil(t, result.Summary)
+	assert.Empty(t, body.Tools)
+	require.Len(t, body.System, 1)
+	assert.Equal(t, compareInstructions, body.System[0].Text)
+
+	c := result.Comparison([]string{"0xa", "0xb"})
+	assert.Equal(t, []string{"0xa", "0xb"}, c.TraderIDs)
+	assert.Equal(t, int64(500), c.InputTokens)
+	assert.Equal(t, compareTemplate.Hash, c.PromptHash)
+}
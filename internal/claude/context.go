@@ -0,0 +1,391 @@
+package claude
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+)
+
+// DefaultContextBudget is the token budget for a rendered prompt when the
+// client config leaves it unset.
+const DefaultContextBudget = 12000
+
+// Reasons a trade was sampled into the prompt.
+const (
+	SampleRecent  = "most recent"
+	SampleLargest = "largest"
+	SampleWinner  = "biggest winner"
+	SampleLoser   = "biggest loser"
+)
+
+// MarketPosition aggregates a trader's trades on one side of a market; a
+// trader holding both YES and NO has a position for each. Open size is valued
+// at the latest snapshot price for the side, or at the average entry price
+// without a snapshot, as in the category breakdown.
+type MarketPosition struct {
+	MarketID string
+	Question string
+	Category string
+	Side     string
+	Trades   int
+	Volume   float64
+	NetSize  float64
+	PnL      float64
+}
+
+// CategorySplit is the share of a trader's volume in one market category.
+type CategorySplit struct {
+	Category string
+	Trades   int
+	Volume   float64
+	Share    float64
+}
+
+// HourBucket counts trades placed in one hour of the day (UTC).
+type HourBucket struct {
+	Hour   int
+	Trades int
+	Share  float64
+}
+
+// Prompt is a rendered thesis prompt and what went into it.
+type Prompt struct {
+	Text string
+	// EstimatedTokens approximates the prompt size at four characters a token.
+	EstimatedTokens int
+	Budget          int
+	SampledTrades   int
+	TotalTrades     int
+	ShownMarkets    int
+	TotalMarkets    int
+}
+
+// LoadTraderData gathers everything the prompt needs about a trader from the
+// database: all trades, their markets and latest snapshots, and the stored
+// behavioral profile.
+func LoadTraderData(database *db.DB, trader *db.Trader) (TraderData, error) {
+	data := TraderData{
+		Trader:    trader,
+		Markets:   make(map[string]*db.Market),
+		Snapshots: make(map[string]*db.MarketSnapshot),
+	}
+	if trader == nil {
+		return data, ErrInvalidTrader
+	}
+
+	trades, err := database.GetTradesByTrader(trader.Address)
+	if err != nil {
+		return data, fmt.Errorf("failed to fetch trades: %w", err)
+	}
+	data.Trades = trades
+
+	for _, t := range trades {
+		if _, seen := data.Markets[t.MarketID]; seen {
+			continue
+		}
+		market, err := database.GetMarket(t.MarketID)
+		if err != nil {
+			return data, fmt.Errorf("failed to fetch market %s: %w", t.MarketID, err)
+		}
+		data.Markets[t.MarketID] = market
+		snapshot, err := database.GetLatestMarketSnapshot(t.MarketID)
+		if err != nil {
+			return data, fmt.Errorf("failed to fetch snapshot for market %s: %w", t.MarketID, err)
+		}
+		if snapshot != nil {
+			data.Snapshots[t.MarketID] = snapshot
+		}
+	}
+
+	if data.Profile, err = database.GetTraderProfile(trader.Address); err != nil {
+		return data, fmt.Errorf("failed to get trader profile: %w", err)
+	}
+	return data, nil
+}
+
+// Render fits the trader's history into the budget and executes the template.
+// Aggregates cover every trade; positions are cut to the largest by P&L and the
+// sampled trades to as many as still fit. A prompt whose fixed parts alone
+// exceed the budget is returned with EstimatedTokens over Budget.
+func (p *PromptTemplate) Render(data TraderData, budget int) (*Prompt, error) {
+	return p.fit(p.tmpl, data, budget)
+}
+
+// fit is Render executing tmpl, which is the template itself or one of its
+// associated templates.
+func (p *PromptTemplate) fit(tmpl *template.Template, data TraderData, budget int) (*Prompt, error) {
+	if data.Trader == nil {
+		return nil, ErrInvalidTrader
+	}
+	if budget <= 0 {
+		budget = DefaultContextBudget
+	}
+
+	full := buildPromptData(data)
+	positions, samples := full.Positions, full.Trades
+
+	render := func(nPositions, nTrades int) (string, error) {
+		pd := full
+		pd.Positions = positions[:nPositions]
+		pd.OmittedMarkets = len(positions) - nPositions
+		pd.Trades = newestFirst(samples[:nTrades])
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, pd); err != nil {
+			return "", fmt.Errorf("failed to render prompt template %s: %w", p.Name, err)
+		}
+		return sb.String(), nil
+	}
+	// Positions may take half the budget, then trades fill the rest. When every
+	// trade fits, positions get whatever room is left.
+	nPositions, err := largestFitting(len(positions), budget/2, func(n int) (string, error) { return render(n, 0) })
+	if err != nil {
+		return nil, err
+	}
+	nTrades, err := largestFitting(len(samples), budget, func(n int) (string, error) { return render(nPositions, n) })
+	if err != nil {
+		return nil, err
+	}
+	if nTrades == len(samples) {
+		more, err := largestFitting(len(positions)-nPositions, budget, func(n int) (string, error) { return render(nPositions+n, nTrades) })
+		if err != nil {
+			return nil, err
+		}
+		nPositions += more
+	}
+
+	text, err := render(nPositions, nTrades)
+	if err != nil {
+		return nil, err
+	}
+	return &Prompt{
+		Text:            text,
+		EstimatedTokens: estimateTokens(text),
+		Budget:          budget,
+		SampledTrades:   nTrades,
+		TotalTrades:     len(data.Trades),
+		ShownMarkets:    nPositions,
+		TotalMarkets:    len(positions),
+	}, nil
+}
+
+// largestFitting returns the largest n in [0, max] whose render fits within
+// limit tokens, assuming renders grow with n.
+func largestFitting(max, limit int, rendered func(n int) (string, error)) (int, error) {
+	lo, hi := 0, max
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		text, err := rendered(mid)
+		if err != nil {
+			return 0, err
+		}
+		if estimateTokens(text) <= limit {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, nil
+}
+
+// estimateTokens approximates a token count at four characters per token.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// buildPromptData aggregates the full history and ranks every trade for
+// sampling. Positions come sorted by absolute P&L; Trades holds the ranked
+// sample order, to be cut and re-sorted by Render.
+func buildPromptData(data TraderData) PromptData {
+	pd := PromptData{
+		Trader:      data.Trader,
+		TotalTrades: len(data.Trades),
+	}
+	if data.Profile != nil && data.Profile.Entries > 0 {
+		pd.Profile = data.Profile
+	}
+
+	type positionKey struct{ market, side string }
+	positions := make(map[positionKey]*MarketPosition)
+	var order []positionKey
+	buySize := make(map[positionKey]float64)
+	buyCost := make(map[positionKey]float64)
+	categories := make(map[string]*CategorySplit)
+	var totalVolume float64
+	trades := make([]PromptTrade, 0, len(data.Trades))
+
+	for _, t := range data.Trades {
+		market := data.Markets[t.MarketID]
+		question, category := "Unknown Market", db.UncategorizedLabel
+		if market != nil {
+			question = market.Question
+			if market.Category != "" {
+				category = market.Category
+			}
+		}
+
+		key := positionKey{t.MarketID, strings.ToUpper(t.Side)}
+		pos, ok := positions[key]
+		if !ok {
+			pos = &MarketPosition{MarketID: t.MarketID, Question: question, Category: category, Side: t.Side}
+			positions[key] = pos
+			order = append(order, key)
+		}
+		notional := t.Price * t.Size
+		pos.Trades++
+		pos.Volume += notional
+		if analytics.IsBuy(t) {
+			pos.NetSize += t.Size
+			pos.PnL -= notional
+			buySize[key] += t.Size
+			buyCost[key] += notional
+		} else {
+			pos.NetSize -= t.Size
+			pos.PnL += notional
+		}
+
+		c, ok := categories[category]
+		if !ok {
+			c = &CategorySplit{Category: category}
+			categories[category] = c
+		}
+		c.Trades++
+		c.Volume += notional
+		totalVolume += notional
+
+		pd.Hours[t.Timestamp.UTC().Hour()].Trades++
+
+		pt := PromptTrade{Trade: t, Market: question, Notional: notional}
+		if mark, ok := markPrice(data.Snapshots[t.MarketID], t.Side); ok {
+			pt.PnL = (mark - t.Price) * t.Size
+			if !analytics.IsBuy(t) {
+				pt.PnL = -pt.PnL
+			}
+			pt.Marked = true
+		}
+		trades = append(trades, pt)
+	}
+
+	for _, key := range order {
+		pos := positions[key]
+		mark, ok := markPrice(data.Snapshots[key.market], key.side)
+		if !ok && buySize[key] > 0 {
+			mark = buyCost[key] / buySize[key]
+		}
+		pos.PnL += pos.NetSize * mark
+		pd.Positions = append(pd.Positions, *pos)
+	}
+	sort.SliceStable(pd.Positions, func(i, j int) bool {
+		return abs(pd.Positions[i].PnL) > abs(pd.Positions[j].PnL)
+	})
+
+	for _, c := range categories {
+		if totalVolume > 0 {
+			c.Share = c.Volume / totalVolume
+		}
+		pd.Categories = append(pd.Categories, *c)
+	}
+	sort.Slice(pd.Categories, func(i, j int) bool {
+		if pd.Categories[i].Volume != pd.Categories[j].Volume {
+			return pd.Categories[i].Volume > pd.Categories[j].Volume
+		}
+		return pd.Categories[i].Category < pd.Categories[j].Category
+	})
+
+	for h := range pd.Hours {
+		pd.Hours[h].Hour = h
+		if len(data.Trades) > 0 {
+			pd.Hours[h].Share = float64(pd.Hours[h].Trades) / float64(len(data.Trades))
+		}
+	}
+
+	pd.Trades = rankSamples(trades)
+	return pd
+}
+
+// rankSamples orders trades for sampling by interleaving the most recent,
+// the largest, the biggest winners and the biggest losers, so any prefix of
+// the result is a balanced sample. Each trade appears once, tagged with the
+// reason it was first picked.
+func rankSamples(trades []PromptTrade) []PromptTrade {
+	byRecent := sortedIndexes(trades, func(a, b PromptTrade) bool { return a.Timestamp.After(b.Timestamp) })
+	byLargest := sortedIndexes(trades, func(a, b PromptTrade) bool { return a.Notional > b.Notional })
+	var winners, losers []int
+	for _, i := range sortedIndexes(trades, func(a, b PromptTrade) bool { return a.PnL > b.PnL }) {
+		if trades[i].Marked && trades[i].PnL > 0 {
+			winners = append(winners, i)
+		}
+	}
+	for _, i := range sortedIndexes(trades, func(a, b PromptTrade) bool { return a.PnL < b.PnL }) {
+		if trades[i].Marked && trades[i].PnL < 0 {
+			losers = append(losers, i)
+		}
+	}
+
+	lists := []struct {
+		reason  string
+		indexes []int
+	}{
+		{SampleRecent, byRecent},
+		{SampleLargest, byLargest},
+		{SampleWinner, winners},
+		{SampleLoser, losers},
+	}
+
+	picked := make([]bool, len(trades))
+	ranked := make([]PromptTrade, 0, len(trades))
+	for len(ranked) < len(trades) {
+		for l := range lists {
+			for len(lists[l].indexes) > 0 {
+				i := lists[l].indexes[0]
+				lists[l].indexes = lists[l].indexes[1:]
+				if picked[i] {
+					continue
+				}
+				picked[i] = true
+				t := trades[i]
+				t.Reason = lists[l].reason
+				ranked = append(ranked, t)
+				break
+			}
+		}
+	}
+	return ranked
+}
+
+func sortedIndexes(trades []PromptTrade, less func(a, b PromptTrade) bool) []int {
+	idx := make([]int, len(trades))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return less(trades[idx[i]], trades[idx[j]]) })
+	return idx
+}
+
+// newestFirst returns a copy of the trades sorted newest first.
+func newestFirst(trades []PromptTrade) []PromptTrade {
+	out := append([]PromptTrade(nil), trades...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.After(out[j].Timestamp) })
+	return out
+}
+
+// markPrice is the latest price of the traded side.
+func markPrice(s *db.MarketSnapshot, side string) (float64, bool) {
+	if s == nil {
+		return 0, false
+	}
+	if strings.EqualFold(side, "NO") {
+		return s.NoPrice, true
+	}
+	return s.YesPrice, true
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
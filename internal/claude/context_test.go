@@ -0,0 +1,185 @@
+package claude
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contextFixture(n int) TraderData {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := make([]db.Trade, n)
+	for i := range trades {
+		trades[i] = db.Trade{
+			ID:        fmt.Sprintf("t%d", i),
+			MarketID:  fmt.Sprintf("m%d", i%20),
+			Type:      "BUY",
+			Side:      "YES",
+			Price:     0.5,
+			Size:      10,
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		}
+	}
+	// A huge trade, a big loser and a big winner buried in the middle.
+	trades[n/2].Size = 5000
+	trades[n/3].MarketID, trades[n/3].Price = "loser", 0.9
+	trades[n/4].MarketID, trades[n/4].Price = "winner", 0.05
+
+	markets := map[string]*db.Market{
+		"loser":  {ID: "loser", Question: "Loser market?", Category: "sports"},
+		"winner": {ID: "winner", Question: "Winner market?", Category: "politics"},
+	}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("m%d", i)
+		markets[id] = &db.Market{ID: id, Question: fmt.Sprintf("Market %d?", i), Category: "crypto"}
+	}
+	return TraderData{
+		Trader:  &db.Trader{Address: "0xprolific"},
+		Trades:  trades,
+		Markets: markets,
+		Snapshots: map[string]*db.MarketSnapshot{
+			"loser":  {YesPrice: 0.1, NoPrice: 0.9},
+			"winner": {YesPrice: 0.95, NoPrice: 0.05},
+		},
+	}
+}
+
+func TestRender_FitsBudget(t *testing.T) {
+	data := contextFixture(3000)
+
+	prompt, err := DefaultPromptTemplate().Render(data, 4000)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, prompt.EstimatedTokens, 4000)
+	assert.Equal(t, 4000, prompt.Budget)
+	assert.Equal(t, 3000, prompt.TotalTrades)
+	assert.Greater(t, prompt.SampledTrades, 0)
+	assert.Less(t, prompt.SampledTrades, 3000)
+	assert.Equal(t, 22, prompt.TotalMarkets)
+	assert.Equal(t, prompt.SampledTrades, strings.Count(prompt.Text, "### Trade"))
+
+	// The sample always includes the extremes.
+	assert.Contains(t, prompt.Text, "- **Size:** 5000.0000")
+	assert.Contains(t, prompt.Text, "Sampled As:** "+SampleWinner)
+	assert.Contains(t, prompt.Text, "Sampled As:** "+SampleLoser)
+	assert.Contains(t, prompt.Text, datetimeOf(data.Trades[2999].Timestamp))
+
+	// A bigger budget fits more.
+	larger, err := DefaultPromptTemplate().Render(data, 20000)
+	require.NoError(t, err)
+	assert.Greater(t, larger.SampledTrades, prompt.SampledTrades)
+	assert.LessOrEqual(t, larger.EstimatedTokens, 20000)
+}
+
+func TestRender_AllFit(t *testing.T) {
+	prompt, err := DefaultPromptTemplate().Render(contextFixture(30), DefaultContextBudget)
+	require.NoError(t, err)
+	assert.Equal(t, 30, prompt.SampledTrades)
+	assert.Equal(t, prompt.TotalMarkets, prompt.ShownMarkets)
+	assert.NotContains(t, prompt.Text, "total trades, sampled")
+	assert.NotContains(t, prompt.Text, "positions omitted")
+}
+
+func TestBuildPromptData(t *testing.T) {
+	data := TraderData{
+		Trader: &db.Trader{Address: "0xa"},
+		Trades: []db.Trade{
+			{ID: "1", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 100, Timestamp: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)},
+			{ID: "2", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.6, Size: 50, Timestamp: time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)},
+			{ID: "3", MarketID: "m2", Type: "BUY", Side: "NO", Price: 0.5, Size: 20, Timestamp: time.Date(2026, 1, 3, 22, 0, 0, 0, time.UTC)},
+		},
+		Markets: map[string]*db.Market{
+			"m1": {Question: "M1?", Category: "politics"},
+			"m2": {Question: "M2?"},
+		},
+		Snapshots: map[string]*db.MarketSnapshot{"m1": {YesPrice: 0.7, NoPrice: 0.3}},
+	}
+
+	pd := buildPromptData(data)
+
+	require.Len(t, pd.Positions, 2)
+	// m1: -40 + 30 cash, 50 shares left at 0.7 = 25. m2: -10 cash, 20 shares at entry 0.5 = 0.
+	assert.Equal(t, "M1?", pd.Positions[0].Question)
+	assert.InDelta(t, 25, pd.Positions[0].PnL, 1e-9)
+	assert.InDelta(t, 50, pd.Positions[0].NetSize, 1e-9)
+	assert.InDelta(t, 0, pd.Positions[1].PnL, 1e-9)
+	assert.Equal(t, db.UncategorizedLabel, pd.Positions[1].Category)
+
+	require.Len(t, pd.Categories, 2)
+	assert.Equal(t, "politics", pd.Categories[0].Category)
+	assert.InDelta(t, 70.0/80.0, pd.Categories[0].Share, 1e-9)
+
+	assert.Equal(t, 2, pd.Hours[9].Trades)
+	assert.Equal(t, 1, pd.Hours[22].Trades)
+
+	require.Len(t, pd.Trades, 3)
+	byID := map[string]PromptTrade{}
+	for _, tr := range pd.Trades {
+		byID[tr.ID] = tr
+	}
+	assert.InDelta(t, 30, byID["1"].PnL, 1e-9) // bought at 0.4, now 0.7
+	assert.InDelta(t, -5, byID["2"].PnL, 1e-9) // sold at 0.6, now 0.7
+	assert.False(t, byID["3"].Marked)
+}
+
+func TestBuildPromptData_BothSides(t *testing.T) {
+	at := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	data := TraderData{
+		Trader: &db.Trader{Address: "0xa"},
+		Trades: []db.Trade{
+			{ID: "1", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 100, Timestamp: at},
+			{ID: "2", MarketID: "m1", Type: "BUY", Side: "no", Price: 0.5, Size: 100, Timestamp: at},
+			{ID: "3", MarketID: "m1", Type: "SELL", Side: "NO", Price: 0.6, Size: 50, Timestamp: at},
+		},
+		Markets:   map[string]*db.Market{"m1": {Question: "M1?"}},
+		Snapshots: map[string]*db.MarketSnapshot{"m1": {YesPrice: 0.3, NoPrice: 0.7}},
+	}
+
+	pd := buildPromptData(data)
+
+	// NO: -50 + 30 cash, 50 shares left at 0.7 = 15. YES: -40 cash, 100 shares at 0.3 = -10.
+	require.Len(t, pd.Positions, 2)
+	assert.Equal(t, "no", pd.Positions[0].Side)
+	assert.Equal(t, 2, pd.Positions[0].Trades)
+	assert.InDelta(t, 50, pd.Positions[0].NetSize, 1e-9)
+	assert.InDelta(t, 15, pd.Positions[0].PnL, 1e-9)
+	assert.Equal(t, "YES", pd.Positions[1].Side)
+	assert.InDelta(t, 100, pd.Positions[1].NetSize, 1e-9)
+	assert.InDelta(t, -10, pd.Positions[1].PnL, 1e-9)
+}
+
+func TestLoadTraderData(t *testing.T) {
+	dbPath := "test_load_trader_data.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	trader := &db.Trader{Address: "0xload"}
+	require.NoError(t, database.SaveTrader(trader))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "M1?"}))
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{MarketID: "m1", YesPrice: 0.6, NoPrice: 0.4, Timestamp: time.Now()}))
+	require.NoError(t, database.SaveTrade(&db.Trade{ID: "t1", TraderID: "0xload", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: time.Now()}))
+	require.NoError(t, database.SaveTrade(&db.Trade{ID: "t2", TraderID: "0xload", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: time.Now()}))
+
+	data, err := LoadTraderData(database, trader)
+	require.NoError(t, err)
+	assert.Len(t, data.Trades, 2)
+	assert.Equal(t, "M1?", data.Markets["m1"].Question)
+	assert.Nil(t, data.Markets["m2"])
+	assert.InDelta(t, 0.6, data.Snapshots["m1"].YesPrice, 1e-9)
+	assert.NotContains(t, data.Snapshots, "m2")
+
+	_, err = LoadTraderData(database, nil)
+	assert.ErrorIs(t, err, ErrInvalidTrader)
+}
+
+func datetimeOf(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
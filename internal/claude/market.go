@@ -0,0 +1,342 @@
+package claude
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// MarketPromptVersion identifies the system instructions sent with every
+// market analysis prompt; bump it when they change.
+const MarketPromptVersion = "market-v1"
+
+// MarketTemplateName names the built-in market analysis prompt.
+const MarketTemplateName = "market"
+
+// ErrInvalidMarket is returned when market data has no market.
+var ErrInvalidMarket = errors.New("market data is invalid or missing")
+
+//go:embed templates/market.tmpl
+var marketTemplateSource string
+
+var marketTemplate = mustParsePromptTemplate(MarketTemplateName, marketTemplateSource)
+
+const marketInstructions = `You analyze prediction market order flow for a copy-trading research tool.
+Be specific and ground every claim in the prices, flows and participants provided.`
+
+// MarketData is everything a market analysis draws on.
+type MarketData struct {
+	Market *db.Market
+	// Snapshots holds the market's price history, oldest first.
+	Snapshots []db.MarketSnapshot
+	Trades    []db.Trade
+	// Traders holds the stored record of each participant; traders seen only
+	// through their trades are missing.
+	Traders map[string]*db.Trader
+	// Labels holds the classifier label of each classified participant.
+	Labels map[string]string
+}
+
+// MarketPromptData is the data the market template is executed with. Flows
+// cover every stored trade; Prices and Participants are cut to fit the
+// context budget.
+type MarketPromptData struct {
+	Market *db.Market
+	// Latest is the newest snapshot, nil when the market has none.
+	Latest *db.MarketSnapshot
+	// Prices samples the price history evenly, oldest first.
+	Prices         []db.MarketSnapshot
+	TotalSnapshots int
+	Flows          []OutcomeFlow
+	// Participants lists traders by volume in the market, largest first;
+	// OmittedParticipants counts those cut for space.
+	Participants        []MarketParticipant
+	OmittedParticipants int
+	TotalParticipants   int
+	TotalTrades         int
+}
+
+// OutcomeFlow is the notional bought and sold of one outcome. ProfitableNet
+// counts only traders with a positive overall P&L.
+type OutcomeFlow struct {
+	Outcome       string
+	Bought        float64
+	Sold          float64
+	Net           float64
+	ProfitableNet float64
+	Traders       int
+}
+
+// MarketParticipant aggregates one trader's trades in the market alongside
+// the scores from their full history.
+type MarketParticipant struct {
+	Address    string
+	Name       string
+	Label      string
+	WinRate    float64
+	ROI        float64
+	ProfitLoss float64
+	Trades     int
+	Volume     float64
+	// NetYes and NetNo are shares bought minus shares sold of each outcome.
+	NetYes float64
+	NetNo  float64
+	// AvgPrice is the average price paid on buys.
+	AvgPrice  float64
+	LastTrade time.Time
+}
+
+// MarketPrompt is a rendered market analysis prompt and what went into it.
+type MarketPrompt struct {
+	Prompt
+	ShownParticipants int
+	TotalParticipants int
+	ShownSnapshots    int
+	TotalSnapshots    int
+}
+
+// LoadMarketData gathers the market's price history, stored trades, and the
+// record and classifier label of every trader in it.
+func LoadMarketData(database *db.DB, market *db.Market) (MarketData, error) {
+	data := MarketData{Market: market, Traders: make(map[string]*db.Trader)}
+	if market == nil {
+		return data, ErrInvalidMarket
+	}
+
+	var err error
+	if data.Snapshots, err = database.GetMarketSnapshots(market.ID); err != nil {
+		return data, err
+	}
+	if data.Trades, err = database.GetTradesByMarket(market.ID); err != nil {
+		return data, err
+	}
+	for _, t := range data.Trades {
+		if _, seen := data.Traders[t.TraderID]; seen {
+			continue
+		}
+		trader, err := database.GetTrader(t.TraderID)
+		if err != nil {
+			return data, fmt.Errorf("failed to fetch trader %s: %w", t.TraderID, err)
+		}
+		data.Traders[t.TraderID] = trader
+	}
+	if data.Labels, err = database.GetTraderLabels(); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// AnalyzeMarket asks Claude for a read on who is trading the market and how
+// the better-scoring traders are positioned. The result has no structured
+// summary.
+func (c *Client) AnalyzeMarket(ctx context.Context, data MarketData) (*AnalysisResult, error) {
+	prompt, err := c.BuildMarketPrompt(data)
+	if err != nil {
+		return nil, err
+	}
+
+	params := c.baseParams(marketInstructions, anthropic.NewUserMessage(anthropic.NewTextBlock(prompt.Text)))
+
+	resp, err := c.client.Messages.New(ctx, params)
+	if err != nil {
+		metrics.ObserveClaudeUsage("error", "", 0, 0)
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+	return c.messageResult(resp, marketTemplate, MarketPromptVersion)
+}
+
+// BuildMarketPrompt renders the prompt AnalyzeMarket would send.
+func (c *Client) BuildMarketPrompt(data MarketData) (*MarketPrompt, error) {
+	return renderMarket(data, c.config.ContextBudget)
+}
+
+// renderMarket fits the market into the budget: participants may take half
+// of it, then price history fills the rest. When the whole history fits,
+// participants get whatever room is left.
+func renderMarket(data MarketData, budget int) (*MarketPrompt, error) {
+	if data.Market == nil {
+		return nil, ErrInvalidMarket
+	}
+	if budget <= 0 {
+		budget = DefaultContextBudget
+	}
+
+	full := BuildMarketPromptData(data)
+	participants := full.Participants
+
+	render := func(nParticipants, nSnapshots int) (string, error) {
+		md := full
+		md.Participants = participants[:nParticipants]
+		md.OmittedParticipants = len(participants) - nParticipants
+		md.Prices = samplePrices(data.Snapshots, nSnapshots)
+		var sb strings.Builder
+		if err := marketTemplate.tmpl.Execute(&sb, md); err != nil {
+			return "", fmt.Errorf("failed to render prompt template %s: %w", MarketTemplateName, err)
+		}
+		return sb.String(), nil
+	}
+
+	nParticipants, err := largestFitting(len(participants), budget/2, func(n int) (string, error) { return render(n, 0) })
+	if err != nil {
+		return nil, err
+	}
+	nSnapshots, err := largestFitting(len(data.Snapshots), budget, func(n int) (string, error) { return render(nParticipants, n) })
+	if err != nil {
+		return nil, err
+	}
+	if nSnapshots == len(data.Snapshots) {
+		more, err := largestFitting(len(participants)-nParticipants, budget, func(n int) (string, error) { return render(nParticipants+n, nSnapshots) })
+		if err != nil {
+			return nil, err
+		}
+		nParticipants += more
+	}
+
+	text, err := render(nParticipants, nSnapshots)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketPrompt{
+		Prompt: Prompt{
+			Text:            text,
+			EstimatedTokens: estimateTokens(text),
+			Budget:          budget,
+			TotalTrades:     len(data.Trades),
+		},
+		ShownParticipants: nParticipants,
+		TotalParticipants: len(participants),
+		ShownSnapshots:    nSnapshots,
+		TotalSnapshots:    len(data.Snapshots),
+	}, nil
+}
+
+// BuildMarketPromptData aggregates flows and participants over every trade,
+// without cutting anything for the context budget.
+func BuildMarketPromptData(data MarketData) MarketPromptData {
+	md := MarketPromptData{
+		Market:         data.Market,
+		TotalSnapshots: len(data.Snapshots),
+		TotalTrades:    len(data.Trades),
+	}
+	if n := len(data.Snapshots); n > 0 {
+		md.Latest = &data.Snapshots[n-1]
+	}
+
+	participants := make(map[string]*MarketParticipant)
+	var order []string
+	buySize := make(map[string]float64)
+	buyCost := make(map[string]float64)
+	flows := make(map[string]*OutcomeFlow)
+	flowTraders := make(map[string]map[string]bool)
+
+	for _, t := range data.Trades {
+		trader := data.Traders[t.TraderID]
+		p, ok := participants[t.TraderID]
+		if !ok {
+			p = &MarketParticipant{Address: t.TraderID, Name: t.TraderID, Label: data.Labels[t.TraderID]}
+			if trader != nil {
+				if trader.Username != "" {
+					p.Name = trader.Username
+				}
+				p.WinRate, p.ROI, p.ProfitLoss = trader.WinRate, trader.ROI, trader.ProfitLoss
+			}
+			participants[t.TraderID] = p
+			order = append(order, t.TraderID)
+		}
+
+		outcome := strings.ToUpper(t.Side)
+		if outcome == "" {
+			outcome = "YES"
+		}
+		notional := t.Price * t.Size
+		size := t.Size
+		if !analytics.IsBuy(t) {
+			size = -size
+		}
+
+		p.Trades++
+		p.Volume += notional
+		if outcome == "NO" {
+			p.NetNo += size
+		} else {
+			p.NetYes += size
+		}
+		if analytics.IsBuy(t) {
+			buySize[t.TraderID] += t.Size
+			buyCost[t.TraderID] += notional
+		}
+		if t.Timestamp.After(p.LastTrade) {
+			p.LastTrade = t.Timestamp
+		}
+
+		f, ok := flows[outcome]
+		if !ok {
+			f = &OutcomeFlow{Outcome: outcome}
+			flows[outcome] = f
+			flowTraders[outcome] = make(map[string]bool)
+		}
+		if analytics.IsBuy(t) {
+			f.Bought += notional
+		} else {
+			f.Sold += notional
+		}
+		if trader != nil && trader.ProfitLoss > 0 {
+			if analytics.IsBuy(t) {
+				f.ProfitableNet += notional
+			} else {
+				f.ProfitableNet -= notional
+			}
+		}
+		flowTraders[outcome][t.TraderID] = true
+	}
+
+	for _, id := range order {
+		p := participants[id]
+		if buySize[id] > 0 {
+			p.AvgPrice = buyCost[id] / buySize[id]
+		}
+		md.Participants = append(md.Participants, *p)
+	}
+	sort.SliceStable(md.Participants, func(i, j int) bool {
+		return md.Participants[i].Volume > md.Participants[j].Volume
+	})
+	md.TotalParticipants = len(md.Participants)
+
+	for outcome, f := range flows {
+		f.Net = f.Bought - f.Sold
+		f.Traders = len(flowTraders[outcome])
+		md.Flows = append(md.Flows, *f)
+	}
+	sort.Slice(md.Flows, func(i, j int) bool {
+		return md.Flows[i].Outcome > md.Flows[j].Outcome
+	})
+	return md
+}
+
+// samplePrices picks n snapshots spread evenly over the history, always
+// keeping the first and the latest.
+func samplePrices(snapshots []db.MarketSnapshot, n int) []db.MarketSnapshot {
+	switch {
+	case n <= 0:
+		return nil
+	case n >= len(snapshots):
+		return snapshots
+	case n == 1:
+		return snapshots[len(snapshots)-1:]
+	}
+	out := make([]db.MarketSnapshot, n)
+	for i := range out {
+		out[i] = snapshots[i*(len(snapshots)-1)/(n-1)]
+	}
+	return out
+}
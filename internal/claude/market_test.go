@@ -0,0 +1,148 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func marketFixture(participants, snapshots int) MarketData {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := MarketData{
+		Market:  &db.Market{ID: "m1", Question: "Will it rain?", Category: "weather", Status: "active"},
+		Traders: map[string]*db.Trader{},
+		Labels:  map[string]string{"0xwhale": "directional"},
+	}
+	data.Traders["0xwhale"] = &db.Trader{Address: "0xwhale", Username: "whale", WinRate: 0.7, ROI: 0.4, ProfitLoss: 5000}
+	data.Traders["0xfish"] = &db.Trader{Address: "0xfish", ProfitLoss: -200}
+	data.Trades = []db.Trade{
+		{ID: "w1", TraderID: "0xwhale", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 1000, Timestamp: start},
+		{ID: "w2", TraderID: "0xwhale", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.5, Size: 200, Timestamp: start.Add(time.Hour)},
+		{ID: "f1", TraderID: "0xfish", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.6, Size: 100, Timestamp: start.Add(2 * time.Hour)},
+	}
+	for i := 0; i < participants; i++ {
+		data.Trades = append(data.Trades, db.Trade{
+			ID: fmt.Sprintf("x%d", i), TraderID: fmt.Sprintf("0xsmall%04d", i), MarketID: "m1",
+			Type: "BUY", Side: "NO", Price: 0.5, Size: 1, Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	for i := 0; i < snapshots; i++ {
+		data.Snapshots = append(data.Snapshots, db.MarketSnapshot{
+			MarketID: "m1", YesPrice: 0.4 + float64(i)/float64(10*snapshots), NoPrice: 0.6, Timestamp: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return data
+}
+
+func TestBuildMarketPromptData(t *testing.T) {
+	md := BuildMarketPromptData(marketFixture(0, 3))
+
+	require.Len(t, md.Participants, 2)
+	whale := md.Participants[0]
+	assert.Equal(t, "whale", whale.Name)
+	assert.Equal(t, "directional", whale.Label)
+	assert.Equal(t, 2, whale.Trades)
+	assert.InDelta(t, 800, whale.NetYes, 1e-9)
+	assert.InDelta(t, 0.4, whale.AvgPrice, 1e-9)
+	assert.Equal(t, "0xfish", md.Participants[1].Name)
+	assert.InDelta(t, 100, md.Participants[1].NetNo, 1e-9)
+
+	require.Len(t, md.Flows, 2)
+	yes, no := md.Flows[0], md.Flows[1]
+	assert.Equal(t, "YES", yes.Outcome)
+	assert.InDelta(t, 400, yes.Bought, 1e-9)
+	assert.InDelta(t, 100, yes.Sold, 1e-9)
+	assert.InDelta(t, 300, yes.Net, 1e-9)
+	assert.InDelta(t, 300, yes.ProfitableNet, 1e-9)
+	assert.InDelta(t, 60, no.Net, 1e-9)
+	assert.Zero(t, no.ProfitableNet, "losing traders do not count as profitable flow")
+
+	require.NotNil(t, md.Latest)
+	assert.Equal(t, md.Latest.Timestamp, time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC))
+}
+
+func TestRenderMarket(t *testing.T) {
+	prompt, err := renderMarket(marketFixture(0, 3), 6000)
+	require.NoError(t, err)
+	assert.Contains(t, prompt.Text, "- **Question:** Will it rain?")
+	assert.Contains(t, prompt.Text, "- **Stored Trades:** 3 by 2 traders")
+	assert.Contains(t, prompt.Text, "| YES | $400.00 | $100.00 | $300.00 | $300.00 | 1 |")
+	assert.Contains(t, prompt.Text, "| whale | directional | 70.0% | 40.0% | $5000.00 |")
+	assert.Equal(t, 2, prompt.ShownParticipants)
+	assert.Equal(t, 3, prompt.ShownSnapshots)
+
+	// Large markets are cut to the budget, keeping the biggest participants
+	// and the latest price.
+	data := marketFixture(2000, 500)
+	prompt, err = renderMarket(data, 4000)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, prompt.EstimatedTokens, 4000)
+	assert.Less(t, prompt.ShownParticipants, prompt.TotalParticipants)
+	assert.Less(t, prompt.ShownSnapshots, prompt.TotalSnapshots)
+	assert.Equal(t, 2002, prompt.TotalParticipants)
+	assert.Contains(t, prompt.Text, "| whale |")
+	assert.Contains(t, prompt.Text, "smaller participants omitted")
+	assert.Contains(t, prompt.Text, fmt.Sprintf("_(Showing %d of 500 snapshots)_", prompt.ShownSnapshots))
+	assert.Contains(t, prompt.Text, data.Snapshots[499].Timestamp.Format("2006-01-02 15:04:05"))
+
+	_, err = renderMarket(MarketData{}, 4000)
+	assert.ErrorIs(t, err, ErrInvalidMarket)
+}
+
+func TestSamplePrices(t *testing.T) {
+	snapshots := marketFixture(0, 10).Snapshots
+	assert.Nil(t, samplePrices(snapshots, 0))
+	assert.Equal(t, snapshots[9:], samplePrices(snapshots, 1))
+	assert.Len(t, samplePrices(snapshots, 20), 10)
+
+	sampled := samplePrices(snapshots, 4)
+	require.Len(t, sampled, 4)
+	assert.Equal(t, snapshots[0], sampled[0])
+	assert.Equal(t, snapshots[9], sampled[3])
+}
+
+func TestAnalyzeMarket_MockAPI(t *testing.T) {
+	var body struct {
+		System []struct {
+			Text string `json:"text"`
+		} `json:"system"`
+		Messages []struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"Smart money is long YES."}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":700,"output_tokens":60}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	result, err := client.AnalyzeMarket(context.Background(), marketFixture(0, 2))
+	require.NoError(t, err)
+	assert.Equal(t, "Smart money is long YES.", result.Thesis)
+	assert.Equal(t, MarketPromptVersion, result.PromptVersion)
+	require.Len(t, body.System, 1)
+	assert.Equal(t, marketInstructions, body.System[0].Text)
+	require.Len(t, body.Messages, 1)
+	assert.True(t, strings.Contains(body.Messages[0].Content[0].Text, "Will it rain?"))
+
+	a := result.MarketAnalysis("m1")
+	assert.Equal(t, "m1", a.MarketID)
+	assert.Equal(t, int64(700), a.InputTokens)
+	assert.Equal(t, marketTemplate.Hash, a.PromptHash)
+}
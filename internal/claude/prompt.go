@@ -0,0 +1,155 @@
+package claude
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+// DefaultTemplateName names the built-in thesis prompt. A file of the same
+// name in the prompt directory overrides it.
+const DefaultTemplateName = "default"
+
+// templateExt is the extension of prompt template files.
+const templateExt = ".tmpl"
+
+//go:embed templates/default.tmpl
+var defaultTemplateSource string
+
+var defaultTemplate = mustParsePromptTemplate(DefaultTemplateName, defaultTemplateSource)
+
+// PromptTemplate is a text/template that renders the thesis prompt for a
+// trader. Templates are executed with a PromptData value and may use the
+// functions pct (ratio to percent), datetime and inc.
+type PromptTemplate struct {
+	Name string
+	// Hash is a short SHA-256 of the template source, so analyses record
+	// exactly which revision of a template produced them.
+	Hash string
+	tmpl *template.Template
+}
+
+// PromptData is the data a prompt template is executed with. Aggregates
+// cover the trader's full history; Positions and Trades are cut to fit the
+// context budget.
+type PromptData struct {
+	Trader *db.Trader
+	// Profile is nil when no behavioral profile has been computed.
+	Profile *db.TraderProfile
+	// Positions lists positions per market and side, largest P&L first;
+	// OmittedMarkets counts those cut for space.
+	Positions      []MarketPosition
+	OmittedMarkets int
+	Categories     []CategorySplit
+	Hours          [24]HourBucket
+	// Trades holds the sampled trades, newest first; TotalTrades counts all.
+	Trades      []PromptTrade
+	TotalTrades int
+}
+
+// PromptTrade is a sampled trade together with its market question.
+type PromptTrade struct {
+	db.Trade
+	Market   string
+	Notional float64
+	// PnL marks the trade against the market's latest price: buys gain when
+	// the price has risen since, sells when it has fallen. Marked is false
+	// when the market has no snapshot.
+	PnL    float64
+	Marked bool
+	// Reason says why the trade was sampled, e.g. "largest".
+	Reason string
+}
+
+var promptFuncs = template.FuncMap{
+	"pct":      func(ratio float64) float64 { return ratio * 100 },
+	"datetime": func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+	"inc":      func(i int) int { return i + 1 },
+}
+
+// ParsePromptTemplate parses template source under the given name.
+func ParsePromptTemplate(name, source string) (*PromptTemplate, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %w", name, err)
+	}
+	sum := sha256.Sum256([]byte(source))
+	return &PromptTemplate{
+		Name: name,
+		Hash: hex.EncodeToString(sum[:])[:12],
+		tmpl: tmpl,
+	}, nil
+}
+
+func mustParsePromptTemplate(name, source string) *PromptTemplate {
+	t, err := ParsePromptTemplate(name, source)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// DefaultPromptTemplate returns the built-in thesis prompt.
+func DefaultPromptTemplate() *PromptTemplate {
+	return defaultTemplate
+}
+
+// LoadPromptTemplate loads the template <dir>/<name>.tmpl. An empty name
+// selects the default template, which falls back to the built-in prompt when
+// dir is empty or has no default.tmpl.
+func LoadPromptTemplate(dir, name string) (*PromptTemplate, error) {
+	if name == "" {
+		name = DefaultTemplateName
+	}
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid prompt template name: %s", name)
+	}
+
+	if dir != "" {
+		source, err := os.ReadFile(filepath.Join(dir, name+templateExt))
+		if err == nil {
+			return ParsePromptTemplate(name, string(source))
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read prompt template %s: %w", name, err)
+		}
+	}
+	if name == DefaultTemplateName {
+		return defaultTemplate, nil
+	}
+
+	available, _ := ListPromptTemplates(dir)
+	return nil, fmt.Errorf("prompt template %q not found (available: %s)", name, strings.Join(available, ", "))
+}
+
+// ListPromptTemplates returns the names of the templates in dir, always
+// including the default.
+func ListPromptTemplates(dir string) ([]string, error) {
+	names := []string{DefaultTemplateName}
+	if dir == "" {
+		return names, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names, fmt.Errorf("failed to read prompt directory: %w", err)
+	}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), templateExt)
+		if e.IsDir() || name == e.Name() || name == DefaultTemplateName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
@@ -0,0 +1,104 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPromptTemplate(t *testing.T) {
+	tmpl, err := LoadPromptTemplate("", "")
+	require.NoError(t, err)
+	assert.Same(t, DefaultPromptTemplate(), tmpl)
+	assert.Len(t, tmpl.Hash, 12)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sports.tmpl"), []byte("Sports review of {{.Trader.Address}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "risk-review.tmpl"), []byte("Risk review"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	// Without a default.tmpl the built-in prompt is still the default.
+	tmpl, err = LoadPromptTemplate(dir, "")
+	require.NoError(t, err)
+	assert.Same(t, DefaultPromptTemplate(), tmpl)
+
+	tmpl, err = LoadPromptTemplate(dir, "sports")
+	require.NoError(t, err)
+	assert.Equal(t, "sports", tmpl.Name)
+	assert.NotEqual(t, DefaultPromptTemplate().Hash, tmpl.Hash)
+	prompt, err := tmpl.Render(TraderData{Trader: &db.Trader{Address: "0xabc"}}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Sports review of 0xabc", prompt.Text)
+
+	names, err := ListPromptTemplates(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "risk-review", "sports"}, names)
+
+	_, err = LoadPromptTemplate(dir, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "risk-review, sports")
+
+	_, err = LoadPromptTemplate(dir, "../sports")
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "default.tmpl"), []byte("Custom default"), 0644))
+	tmpl, err = LoadPromptTemplate(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTemplateName, tmpl.Name)
+	assert.NotEqual(t, DefaultPromptTemplate().Hash, tmpl.Hash)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte("{{.Trader"), 0644))
+	_, err = LoadPromptTemplate(dir, "broken")
+	assert.Error(t, err)
+}
+
+func TestPromptTemplate_RenderError(t *testing.T) {
+	tmpl, err := ParsePromptTemplate("bad", "{{.Trader.NoSuchField}}")
+	require.NoError(t, err)
+	_, err = tmpl.Render(TraderData{Trader: &db.Trader{Address: "0xabc"}}, 0)
+	assert.Error(t, err)
+
+	_, err = tmpl.Render(TraderData{}, 0)
+	assert.ErrorIs(t, err, ErrInvalidTrader)
+}
+
+func TestAnalyzeTrader_Template(t *testing.T) {
+	var body struct {
+		Messages []struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"model":"claude-test","stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`)
+	}))
+	defer server.Close()
+
+	tmpl, err := ParsePromptTemplate("risk-review", "Review the risks of {{.Trader.Address}} across {{.TotalTrades}} trades.")
+	require.NoError(t, err)
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL, Template: tmpl})
+	require.NoError(t, err)
+
+	result, err := client.AnalyzeTrader(context.Background(), TraderData{
+		Trader: &db.Trader{Address: "0xabc"},
+		Trades: []db.Trade{{ID: "t1"}, {ID: "t2"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, body.Messages, 1)
+	assert.Equal(t, "Review the risks of 0xabc across 2 trades.", body.Messages[0].Content[0].Text)
+	assert.Equal(t, "risk-review", result.PromptTemplate)
+	assert.Equal(t, tmpl.Hash, result.PromptHash)
+}
@@ -0,0 +1,152 @@
+package claude
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// SummaryToolName is the tool Claude calls to record the structured summary of
+// a thesis after writing the narrative.
+const SummaryToolName = "record_thesis_summary"
+
+// ErrNoSummary is reported in AnalysisResult.SummaryErr when Claude finished
+// the thesis without calling the summary tool.
+var ErrNoSummary = errors.New("response did not include a structured summary")
+
+// Allowed values of the enumerated summary fields.
+var (
+	Archetypes = []string{
+		"momentum", "contrarian", "informed", "market_maker",
+		"arbitrage", "value", "event_specialist", "generalist",
+	}
+	TimingStyles = []string{"early", "mid_life", "late", "mixed"}
+	Levels       = []string{"low", "medium", "high"}
+)
+
+// Limits on the free-form summary lists.
+const (
+	maxFocusTags = 8
+	maxKeyRisks  = 6
+)
+
+const summaryInstructions = "Write the thesis as markdown first. Then call the " + SummaryToolName +
+	" tool exactly once to record your conclusions in structured form. Do not write anything after the tool call."
+
+func summaryTool() anthropic.ToolUnionParam {
+	enum := func(values []string, description string) map[string]any {
+		return map[string]any{"type": "string", "enum": values, "description": description}
+	}
+	list := func(max int, description string) map[string]any {
+		return map[string]any{
+			"type":        "array",
+			"items":       map[string]any{"type": "string"},
+			"minItems":    1,
+			"maxItems":    max,
+			"description": description,
+		}
+	}
+
+	return anthropic.ToolUnionParam{OfTool: &anthropic.ToolParam{
+		Name:        SummaryToolName,
+		Description: anthropic.String("Record the structured conclusions of the trading thesis you just wrote."),
+		InputSchema: anthropic.ToolInputSchemaParam{
+			Properties: map[string]any{
+				"archetype":    enum(Archetypes, "The strategy archetype that best describes the trader."),
+				"market_focus": list(maxFocusTags, "Short lowercase tags for the kinds of markets traded, e.g. politics, crypto, sports."),
+				"risk_score": map[string]any{
+					"type": "integer", "minimum": 1, "maximum": 10,
+					"description": "Risk appetite from 1 (conservative) to 10 (reckless).",
+				},
+				"timing_style":    enum(TimingStyles, "When in a market's life the trader usually enters."),
+				"edge_confidence": enum(Levels, "How confident you are that the trader has a real, repeatable edge."),
+				"copyability":     enum(Levels, "How practical it would be to copy this trader's positions."),
+				"key_risks":       list(maxKeyRisks, "The main risks of following this trader, one short sentence each."),
+			},
+			Required: []string{"archetype", "market_focus", "risk_score", "timing_style", "edge_confidence", "copyability", "key_risks"},
+		},
+	}}
+}
+
+// parseSummary decodes and validates the input of a summary tool call. List
+// entries are trimmed and focus tags lowercased and de-duplicated.
+func parseSummary(input json.RawMessage) (*db.ThesisSummary, error) {
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.DisallowUnknownFields()
+	var s db.ThesisSummary
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid summary: %w", err)
+	}
+
+	if !oneOf(s.Archetype, Archetypes) {
+		return nil, fmt.Errorf("invalid summary: unknown archetype %q", s.Archetype)
+	}
+	if !oneOf(s.TimingStyle, TimingStyles) {
+		return nil, fmt.Errorf("invalid summary: unknown timing style %q", s.TimingStyle)
+	}
+	if !oneOf(s.EdgeConfidence, Levels) {
+		return nil, fmt.Errorf("invalid summary: unknown edge confidence %q", s.EdgeConfidence)
+	}
+	if !oneOf(s.Copyability, Levels) {
+		return nil, fmt.Errorf("invalid summary: unknown copyability %q", s.Copyability)
+	}
+	if s.RiskScore < 1 || s.RiskScore > 10 {
+		return nil, fmt.Errorf("invalid summary: risk score %d is outside 1-10", s.RiskScore)
+	}
+
+	s.MarketFocus = cleanList(s.MarketFocus, maxFocusTags, true)
+	s.KeyRisks = cleanList(s.KeyRisks, maxKeyRisks, false)
+	if len(s.MarketFocus) == 0 {
+		return nil, fmt.Errorf("invalid summary: no market focus tags")
+	}
+	return &s, nil
+}
+
+// findSummary returns the summary from the last summary tool call in resp.
+func findSummary(resp *anthropic.Message) (*db.ThesisSummary, error) {
+	var input json.RawMessage
+	for _, block := range resp.Content {
+		if block.Type == "tool_use" && block.Name == SummaryToolName {
+			input = block.Input
+		}
+	}
+	if input == nil {
+		return nil, ErrNoSummary
+	}
+	return parseSummary(input)
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func cleanList(list []string, max int, lower bool) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if lower {
+			item = strings.ToLower(item)
+		}
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		out = append(out, item)
+		if len(out) == max {
+			break
+		}
+	}
+	return out
+}
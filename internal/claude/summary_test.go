@@ -0,0 +1,129 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validSummary = `{"archetype":"momentum","market_focus":["Politics"," crypto","politics"],"risk_score":7,"timing_style":"late","edge_confidence":"medium","copyability":"high","key_risks":["Thin books near resolution"]}`
+
+func TestParseSummary(t *testing.T) {
+	s, err := parseSummary(json.RawMessage(validSummary))
+	require.NoError(t, err)
+	assert.Equal(t, &db.ThesisSummary{
+		Archetype:      "momentum",
+		MarketFocus:    []string{"politics", "crypto"},
+		RiskScore:      7,
+		TimingStyle:    "late",
+		EdgeConfidence: "medium",
+		Copyability:    "high",
+		KeyRisks:       []string{"Thin books near resolution"},
+	}, s)
+
+	invalid := map[string]string{
+		"unknown archetype": `{"archetype":"wizard","market_focus":["x"],"risk_score":5,"timing_style":"early","edge_confidence":"low","copyability":"low","key_risks":[]}`,
+		"risk out of range": `{"archetype":"value","market_focus":["x"],"risk_score":11,"timing_style":"early","edge_confidence":"low","copyability":"low","key_risks":[]}`,
+		"bad timing":        `{"archetype":"value","market_focus":["x"],"risk_score":5,"timing_style":"whenever","edge_confidence":"low","copyability":"low","key_risks":[]}`,
+		"bad level":         `{"archetype":"value","market_focus":["x"],"risk_score":5,"timing_style":"early","edge_confidence":"huge","copyability":"low","key_risks":[]}`,
+		"no focus":          `{"archetype":"value","market_focus":[" "],"risk_score":5,"timing_style":"early","edge_confidence":"low","copyability":"low","key_risks":[]}`,
+		"unknown field":     `{"archetype":"value","market_focus":["x"],"risk_score":5,"timing_style":"early","edge_confidence":"low","copyability":"low","key_risks":[],"mood":"happy"}`,
+		"not json":          `{"archetype":`,
+	}
+	for name, input := range invalid {
+		_, err := parseSummary(json.RawMessage(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAnalyzeTrader_Summary(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-test","stop_reason":"tool_use",
+			"content":[{"type":"text","text":"## Thesis\n\nLate momentum."},{"type":"tool_use","id":"toolu_1","name":%q,"input":%s}],
+			"usage":{"input_tokens":10,"output_tokens":20}}`, SummaryToolName, validSummary)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	result, err := client.AnalyzeTrader(context.Background(), TraderData{Trader: &db.Trader{Address: "0x123"}})
+	require.NoError(t, err)
+	assert.Equal(t, "## Thesis\n\nLate momentum.", result.Thesis)
+	require.NotNil(t, result.Summary)
+	assert.NoError(t, result.SummaryErr)
+	assert.Equal(t, "momentum", result.Summary.Archetype)
+	assert.Equal(t, 7, result.Summary.RiskScore)
+
+	tools, ok := body["tools"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, tools, 1)
+	assert.Equal(t, SummaryToolName, tools[0].(map[string]interface{})["name"])
+	assert.NotEmpty(t, body["system"])
+}
+
+func TestAnalyzeTrader_NoSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","model":"claude-test","stop_reason":"end_turn",
+			"content":[{"type":"text","text":"Just prose."}],"usage":{"input_tokens":10,"output_tokens":20}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	result, err := client.AnalyzeTrader(context.Background(), TraderData{Trader: &db.Trader{Address: "0x123"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Just prose.", result.Thesis)
+	assert.Nil(t, result.Summary)
+	assert.ErrorIs(t, result.SummaryErr, ErrNoSummary)
+}
+
+func TestStreamAnalyzeTrader_Summary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		write := func(event, data string) {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+			w.(http.Flusher).Flush()
+		}
+		write("message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude-test","stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":120,"output_tokens":1}}}`)
+		write("content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+		write("content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Late momentum."}}`)
+		write("content_block_stop", `{"type":"content_block_stop","index":0}`)
+		write("content_block_start", fmt.Sprintf(`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":%q,"input":{}}}`, SummaryToolName))
+		half := len(validSummary) / 2
+		for _, part := range []string{validSummary[:half], validSummary[half:]} {
+			partial, _ := json.Marshal(part)
+			write("content_block_delta", fmt.Sprintf(`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":%s}}`, partial))
+		}
+		write("content_block_stop", `{"type":"content_block_stop","index":1}`)
+		write("message_delta", `{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":42}}`)
+		write("message_stop", `{"type":"message_stop"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{APIKey: "test-api-key", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	var final StreamEvent
+	for ev := range client.StreamAnalyzeTrader(context.Background(), TraderData{Trader: &db.Trader{Address: "0x123"}}) {
+		final = ev
+	}
+	require.NoError(t, final.Err)
+	require.NotNil(t, final.Result)
+	assert.Equal(t, "Late momentum.", final.Result.Thesis)
+	require.NotNil(t, final.Result.Summary, "summary error: %v", final.Result.SummaryErr)
+	assert.Equal(t, []string{"politics", "crypto"}, final.Result.Summary.MarketFocus)
+}
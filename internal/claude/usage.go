@@ -0,0 +1,88 @@
+package claude
+
+import (
+	"strings"
+
+	"polytracker/internal/db"
+)
+
+// PromptVersion identifies the system instructions and summary tool sent with
+// every thesis prompt. Bump it whenever either changes; the prompt itself is
+// identified by its template name and hash.
+const PromptVersion = "thesis-v2"
+
+// Price is the USD price of a model per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// cost prices a request from the table. Keys match a model ID exactly or as a
+// prefix, the longest match winning, so "claude-sonnet-4" covers its dated
+// snapshots. Models without a price cost nothing.
+func cost(prices map[string]Price, model string, inputTokens, outputTokens int64) float64 {
+	var best string
+	var price Price
+	found := false
+	for key, p := range prices {
+		if strings.HasPrefix(model, key) && len(key) >= len(best) {
+			best, price, found = key, p, true
+		}
+	}
+	if !found {
+		return 0
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6
+}
+
+// Analysis returns the database record for the result.
+func (r *AnalysisResult) Analysis(traderID string) *db.Analysis {
+	return &db.Analysis{
+		TraderID:       traderID,
+		Thesis:         r.Thesis,
+		Model:          r.Model,
+		CreatedAt:      r.CreatedAt,
+		Summary:        r.Summary,
+		InputTokens:    r.InputTokens,
+		OutputTokens:   r.OutputTokens,
+		StopReason:     r.StopReason,
+		CostUSD:        r.CostUSD,
+		PromptVersion:  r.PromptVersion,
+		PromptTemplate: r.PromptTemplate,
+		PromptHash:     r.PromptHash,
+		Prompt:         r.Prompt,
+	}
+}
+
+// Comparison returns the database record for a comparison of the traders,
+// in the order they were compared.
+func (r *AnalysisResult) Comparison(traderIDs []string) *db.Comparison {
+	return &db.Comparison{
+		TraderIDs:     traderIDs,
+		Thesis:        r.Thesis,
+		Model:         r.Model,
+		InputTokens:   r.InputTokens,
+		OutputTokens:  r.OutputTokens,
+		StopReason:    r.StopReason,
+		CostUSD:       r.CostUSD,
+		PromptVersion: r.PromptVersion,
+		PromptHash:    r.PromptHash,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// MarketAnalysis returns the database record for an analysis of the market.
+func (r *AnalysisResult) MarketAnalysis(marketID string) *db.MarketAnalysis {
+	return &db.MarketAnalysis{
+		MarketID:      marketID,
+		Thesis:        r.Thesis,
+		Model:         r.Model,
+		InputTokens:   r.InputTokens,
+		OutputTokens:  r.OutputTokens,
+		StopReason:    r.StopReason,
+		CostUSD:       r.CostUSD,
+		PromptVersion: r.PromptVersion,
+		PromptHash:    r.PromptHash,
+		CreatedAt:     r.CreatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCost(t *testing.T) {
+	prices := map[string]Price{
+		"claude-opus-4":   {Input: 15, Output: 75},
+		"claude-opus-4-5": {Input: 5, Output: 25},
+		"claude-sonnet-4": {Input: 3, Output: 15},
+	}
+
+	assert.InDelta(t, 0.003+0.015, cost(prices, "claude-sonnet-4-20250514", 1000, 1000), 1e-9)
+	assert.InDelta(t, 0.015+0.075, cost(prices, "claude-opus-4-1-20250805", 1000, 1000), 1e-9)
+	// The longest matching prefix wins.
+	assert.InDelta(t, 0.005+0.025, cost(prices, "claude-opus-4-5-20251101", 1000, 1000), 1e-9)
+	assert.Zero(t, cost(prices, "claude-unknown", 1000, 1000))
+	assert.Zero(t, cost(nil, "claude-sonnet-4", 1000, 1000))
+}
+
+func TestAnalysisResultRecord(t *testing.T) {
+	result := &AnalysisResult{Thesis: "t", Model: "claude-test", InputTokens: 1_000_000, OutputTokens: 500_000, StopReason: "end_turn"}
+	result.CostUSD = cost(map[string]Price{"claude-test": {Input: 1, Output: 2}}, result.Model, result.InputTokens, result.OutputTokens)
+	result.PromptVersion = PromptVersion
+
+	a := result.Analysis("0xabc")
+	assert.Equal(t, "0xabc", a.TraderID)
+	assert.Equal(t, int64(1_000_000), a.InputTokens)
+	assert.Equal(t, int64(500_000), a.OutputTokens)
+	assert.Equal(t, "end_turn", a.StopReason)
+	assert.InDelta(t, 2.0, a.CostUSD, 1e-9)
+	assert.Equal(t, PromptVersion, a.PromptVersion)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,6 +9,16 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Claude defaults. The model, token limit and context budget match the claude
+// package defaults.
+const (
+	DefaultClaudeModel     = "claude-sonnet-4-20250514"
+	DefaultClaudeMaxTokens = 4096
+	DefaultContextBudget   = 12000
+	MinContextBudget       = 1000
+	MaxClaudeMaxTokens     = 128000
+)
+
 type Config struct {
 	Polymarket struct {
 		APIKey     string `mapstructure:"api_key"`
@@ -15,8 +26,20 @@ type Config struct {
 		Passphrase string `mapstructure:"passphrase"`
 	} `mapstructure:"polymarket"`
 	Claude struct {
-		APIKey   string `mapstructure:"api_key"`
-		Endpoint string `mapstructure:"endpoint"`
+		APIKey      string  `mapstructure:"api_key"`
+		Endpoint    string  `mapstructure:"endpoint"`
+		Model       string  `mapstructure:"model"`
+		MaxTokens   int64   `mapstructure:"max_tokens"`
+		Temperature float64 `mapstructure:"temperature"`
+		// Prices maps model IDs, or prefixes of them, to their USD price per
+		// million tokens; the longest matching prefix wins.
+		Prices map[string]ModelPrice `mapstructure:"prices"`
+		// PromptDir holds custom prompt templates (<name>.tmpl) selectable
+		// with analyze --template; empty uses only the built-in prompt.
+		PromptDir string `mapstructure:"prompt_dir"`
+		// ContextBudget caps the estimated size of the trader context sent with
+		// each analysis, in tokens.
+		ContextBudget int `mapstructure:"context_budget"`
 	} `mapstructure:"claude"`
 	Database struct {
 		Path string `mapstructure:"path"`
@@ -24,6 +47,99 @@ type Config struct {
 	UI struct {
 		Theme string `mapstructure:"theme"`
 	} `mapstructure:"ui"`
+	Trading struct {
+		PrivateKey        string  `mapstructure:"private_key"`
+		ChainID           int64   `mapstructure:"chain_id"`
+		Exchange          string  `mapstructure:"exchange"`
+		MaxMarketNotional float64 `mapstructure:"max_market_notional"`
+		MaxDailyNotional  float64 `mapstructure:"max_daily_notional"`
+	} `mapstructure:"trading"`
+	Paper struct {
+		StartingCash float64 `mapstructure:"starting_cash"`
+		Amount       float64 `mapstructure:"amount"`
+	} `mapstructure:"paper"`
+	Alerts struct {
+		Rules []AlertRule `mapstructure:"rules"`
+	} `mapstructure:"alerts"`
+	Notifications struct {
+		Sinks []NotificationSink `mapstructure:"sinks"`
+	} `mapstructure:"notifications"`
+	Email struct {
+		SMTPHost string   `mapstructure:"smtp_host"`
+		SMTPPort int      `mapstructure:"smtp_port"`
+		Username string   `mapstructure:"username"`
+		Password string   `mapstructure:"password"`
+		From     string   `mapstructure:"from"`
+		To       []string `mapstructure:"to"`
+	} `mapstructure:"email"`
+	Daemon struct {
+		// Jobs maps job names (scan, watchlist, snapshot, resolution, digest) to
+		// cron expressions. An empty expression disables the job.
+		Jobs map[string]string `mapstructure:"jobs"`
+		// MetricsAddr is where /metrics is served while the daemon runs; empty disables it.
+		MetricsAddr string `mapstructure:"metrics_addr"`
+	} `mapstructure:"daemon"`
+	Server struct {
+		Addr  string `mapstructure:"addr"`
+		Token string `mapstructure:"token"`
+	} `mapstructure:"server"`
+}
+
+// ModelPrice is the USD price of a Claude model per million tokens.
+type ModelPrice struct {
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
+}
+
+// AlertRule configures one alert condition; see the alerts package for the rule types.
+type AlertRule struct {
+	Type      string  `mapstructure:"type"`
+	Threshold float64 `mapstructure:"threshold"`
+}
+
+// NotificationSink configures one webhook that receives fired alerts.
+type NotificationSink struct {
+	Name          string `mapstructure:"name"`
+	Format        string `mapstructure:"format"`
+	URL           string `mapstructure:"url"`
+	ChatID        string `mapstructure:"chat_id"`
+	Template      string `mapstructure:"template"`
+	RatePerMinute int    `mapstructure:"rate_per_minute"`
+	Retries       int    `mapstructure:"retries"`
+}
+
+func defaultDaemonJobs() map[string]string {
+	return map[string]string{
+		"scan":       "*/15 * * * *",
+		"watchlist":  "*/30 * * * *",
+		"snapshot":   "0 * * * *",
+		"resolution": "0 */6 * * *",
+		"digest":     "0 8 * * *",
+	}
+}
+
+func defaultClaudePrices() map[string]interface{} {
+	price := func(input, output float64) map[string]interface{} {
+		return map[string]interface{}{"input": input, "output": output}
+	}
+	return map[string]interface{}{
+		"claude-opus-4":     price(15, 75),
+		"claude-opus-4-5":   price(5, 25),
+		"claude-sonnet-4":   price(3, 15),
+		"claude-3-7-sonnet": price(3, 15),
+		"claude-haiku-4-5":  price(1, 5),
+		"claude-3-5-haiku":  price(0.8, 4),
+		"claude-3-haiku":    price(0.25, 1.25),
+	}
+}
+
+func defaultAlertRules() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"type": "large_position", "threshold": 1000.0},
+		{"type": "new_market", "threshold": 0.0},
+		{"type": "pnl_below", "threshold": 0.0},
+		{"type": "rank_change", "threshold": 5.0},
+	}
 }
 
 func LoadConfig(configPath string) (*Config, error) {
@@ -33,6 +149,22 @@ func LoadConfig(configPath string) (*Config, error) {
 	v.SetDefault("database.path", "polytracker.db")
 	v.SetDefault("ui.theme", "dracula")
 	v.SetDefault("claude.endpoint", "https://api.anthropic.com/v1/messages")
+	v.SetDefault("claude.model", DefaultClaudeModel)
+	v.SetDefault("claude.max_tokens", DefaultClaudeMaxTokens)
+	v.SetDefault("claude.temperature", 1.0)
+	v.SetDefault("claude.prices", defaultClaudePrices())
+	v.SetDefault("claude.context_budget", DefaultContextBudget)
+	v.SetDefault("trading.private_key", "")
+	v.SetDefault("trading.chain_id", 137)
+	v.SetDefault("trading.max_market_notional", 100.0)
+	v.SetDefault("trading.max_daily_notional", 500.0)
+	v.SetDefault("paper.starting_cash", 10000.0)
+	v.SetDefault("paper.amount", 100.0)
+	v.SetDefault("alerts.rules", defaultAlertRules())
+	v.SetDefault("email.smtp_port", 587)
+	v.SetDefault("daemon.jobs", defaultDaemonJobs())
+	v.SetDefault("daemon.metrics_addr", "127.0.0.1:9464")
+	v.SetDefault("server.addr", "127.0.0.1:8080")
 
 	// Environment variables
 	v.SetEnvPrefix("POLYTRACKER")
@@ -66,10 +198,35 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
 
+// Validate reports settings that would only fail later, at request time.
+func (c *Config) Validate() error {
+	if strings.TrimSpace(c.Claude.Model) == "" {
+		return fmt.Errorf("invalid config: claude.model must not be empty")
+	}
+	if c.Claude.MaxTokens < 1 || c.Claude.MaxTokens > MaxClaudeMaxTokens {
+		return fmt.Errorf("invalid config: claude.max_tokens must be between 1 and %d, got %d", MaxClaudeMaxTokens, c.Claude.MaxTokens)
+	}
+	if c.Claude.Temperature < 0 || c.Claude.Temperature > 1 {
+		return fmt.Errorf("invalid config: claude.temperature must be between 0 and 1, got %g", c.Claude.Temperature)
+	}
+	if c.Claude.ContextBudget < MinContextBudget {
+		return fmt.Errorf("invalid config: claude.context_budget must be at least %d, got %d", MinContextBudget, c.Claude.ContextBudget)
+	}
+	for model, price := range c.Claude.Prices {
+		if price.Input < 0 || price.Output < 0 {
+			return fmt.Errorf("invalid config: claude.prices.%s must not be negative", model)
+		}
+	}
+	return nil
+}
+
 func CreateDefaultConfig(path string) error {
 	v := viper.New()
 	v.Set("polymarket.api_key", "")
@@ -77,8 +234,32 @@ func CreateDefaultConfig(path string) error {
 	v.Set("polymarket.passphrase", "")
 	v.Set("claude.api_key", "")
 	v.Set("claude.endpoint", "https://api.anthropic.com/v1/messages")
+	v.Set("claude.model", DefaultClaudeModel)
+	v.Set("claude.max_tokens", DefaultClaudeMaxTokens)
+	v.Set("claude.temperature", 1.0)
+	v.Set("claude.prices", defaultClaudePrices())
+	v.Set("claude.prompt_dir", "")
+	v.Set("claude.context_budget", DefaultContextBudget)
 	v.Set("database.path", "polytracker.db")
 	v.Set("ui.theme", "dracula")
+	v.Set("trading.private_key", "")
+	v.Set("trading.chain_id", 137)
+	v.Set("trading.max_market_notional", 100.0)
+	v.Set("trading.max_daily_notional", 500.0)
+	v.Set("paper.starting_cash", 10000.0)
+	v.Set("paper.amount", 100.0)
+	v.Set("alerts.rules", defaultAlertRules())
+	v.Set("notifications.sinks", []map[string]interface{}{})
+	v.Set("email.smtp_host", "")
+	v.Set("email.smtp_port", 587)
+	v.Set("email.username", "")
+	v.Set("email.password", "")
+	v.Set("email.from", "")
+	v.Set("email.to", []string{})
+	v.Set("daemon.jobs", defaultDaemonJobs())
+	v.Set("daemon.metrics_addr", "127.0.0.1:9464")
+	v.Set("server.addr", "127.0.0.1:8080")
+	v.Set("server.token", "")
 
 	dir := filepath.Dir(path)
 	if dir != "." {
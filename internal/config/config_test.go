@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +21,14 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.UI.Theme != "dracula" {
 		t.Errorf("Expected default theme 'dracula', got '%s'", cfg.UI.Theme)
 	}
+
+	if len(cfg.Alerts.Rules) != 4 || cfg.Alerts.Rules[0].Type != "large_position" || cfg.Alerts.Rules[0].Threshold != 1000 {
+		t.Errorf("Expected default alert rules, got %+v", cfg.Alerts.Rules)
+	}
+
+	if cfg.Daemon.Jobs["scan"] != "*/15 * * * *" || len(cfg.Daemon.Jobs) != 5 {
+		t.Errorf("Expected default daemon jobs, got %+v", cfg.Daemon.Jobs)
+	}
 }
 
 func TestEnvironmentOverrides(t *testing.T) {
@@ -62,3 +71,82 @@ func TestCreateDefaultConfig(t *testing.T) {
 		t.Errorf("Expected theme 'dracula' in created config, got '%s'", cfg.UI.Theme)
 	}
 }
+
+func TestClaudeSettings(t *testing.T) {
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Claude.Model != DefaultClaudeModel || cfg.Claude.MaxTokens != DefaultClaudeMaxTokens || cfg.Claude.Temperature != 1.0 ||
+		cfg.Claude.ContextBudget != DefaultContextBudget {
+		t.Errorf("Expected default Claude settings, got %+v", cfg.Claude)
+	}
+
+	tmpDir := t.TempDir()
+	cases := map[string]string{
+		"empty model":      "claude:\n  model: \"\"\n",
+		"zero max tokens":  "claude:\n  max_tokens: 0\n",
+		"huge max tokens":  "claude:\n  max_tokens: 1000000\n",
+		"high temperature": "claude:\n  temperature: 1.5\n",
+		"negative temp":    "claude:\n  temperature: -0.1\n",
+		"tiny budget":      "claude:\n  context_budget: 10\n",
+	}
+	for name, body := range cases {
+		path := filepath.Join(tmpDir, strings.ReplaceAll(name, " ", "_")+".yaml")
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	path := filepath.Join(tmpDir, "valid.yaml")
+	if err := os.WriteFile(path, []byte("claude:\n  model: claude-opus-4-1\n  max_tokens: 8000\n  temperature: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load valid config: %v", err)
+	}
+	if cfg.Claude.Model != "claude-opus-4-1" || cfg.Claude.MaxTokens != 8000 || cfg.Claude.Temperature != 0 {
+		t.Errorf("Expected configured Claude settings, got %+v", cfg.Claude)
+	}
+}
+
+func TestClaudePrices(t *testing.T) {
+	cfg, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if p := cfg.Claude.Prices["claude-sonnet-4"]; p.Input != 3 || p.Output != 15 {
+		t.Errorf("Expected default sonnet price 3/15, got %+v", p)
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "prices.yaml")
+	if err := os.WriteFile(path, []byte("claude:\n  prices:\n    claude-sonnet-4:\n      input: 2.5\n      output: 12\n    claude-custom:\n      input: 1\n      output: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if p := cfg.Claude.Prices["claude-sonnet-4"]; p.Input != 2.5 || p.Output != 12 {
+		t.Errorf("Expected overridden sonnet price, got %+v", p)
+	}
+	if _, ok := cfg.Claude.Prices["claude-custom"]; !ok {
+		t.Error("Expected custom model price")
+	}
+	if _, ok := cfg.Claude.Prices["claude-opus-4"]; !ok {
+		t.Error("Expected default prices to be kept alongside overrides")
+	}
+
+	path = filepath.Join(tmpDir, "negative.yaml")
+	if err := os.WriteFile(path, []byte("claude:\n  prices:\n    claude-x:\n      input: -1\n      output: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected an error for a negative price")
+	}
+}
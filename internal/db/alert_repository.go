@@ -0,0 +1,169 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SaveAlert stores a fired alert. It returns false without error when an alert
+// with the same dedup key already exists.
+func (db *DB) SaveAlert(a *Alert) (bool, error) {
+	query := `INSERT OR IGNORE INTO alerts (rule, trader_id, market_id, message, dedup_key, read, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	res, err := db.exec(query, a.Rule, a.TraderID, a.MarketID, a.Message, a.DedupKey, a.Read, a.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save alert: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		a.ID = id
+	}
+	return true, nil
+}
+
+// ListAlerts returns the most recent alerts, newest first.
+func (db *DB) ListAlerts(limit int, unreadOnly bool) ([]Alert, error) {
+	query := `SELECT id, rule, trader_id, market_id, message, dedup_key, read, created_at FROM alerts`
+	if unreadOnly {
+		query += ` WHERE read = 0`
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []Alert
+	for rows.Next() {
+		var a Alert
+		if err := rows.Scan(&a.ID, &a.Rule, &a.TraderID, &a.MarketID, &a.Message, &a.DedupKey, &a.Read, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, nil
+}
+
+func (db *DB) CountUnreadAlerts() (int, error) {
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM alerts WHERE read = 0`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread alerts: %w", err)
+	}
+	return count, nil
+}
+
+func (db *DB) MarkAlertsRead() error {
+	if _, err := db.exec(`UPDATE alerts SET read = 1 WHERE read = 0`); err != nil {
+		return fmt.Errorf("failed to mark alerts read: %w", err)
+	}
+	return nil
+}
+
+// GetTraderRanks returns the last stored leaderboard rank of every trader.
+func (db *DB) GetTraderRanks() (map[string]TraderRank, error) {
+	rows, err := db.conn.Query(`SELECT trader_id, rank, updated_at FROM trader_ranks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trader ranks: %w", err)
+	}
+	defer rows.Close()
+
+	ranks := make(map[string]TraderRank)
+	for rows.Next() {
+		var r TraderRank
+		if err := rows.Scan(&r.TraderID, &r.Rank, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trader rank: %w", err)
+		}
+		ranks[r.TraderID] = r
+	}
+	return ranks, nil
+}
+
+// ReplaceTraderRanks stores the current leaderboard ranks, replacing the previous ones.
+func (db *DB) ReplaceTraderRanks(ranks []TraderRank) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM trader_ranks`); err != nil {
+		return fmt.Errorf("failed to clear trader ranks: %w", err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO trader_ranks (trader_id, rank, updated_at) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare trader rank insert: %w", err)
+	}
+	defer stmt.Close()
+	for _, r := range ranks {
+		if _, err := stmt.Exec(r.TraderID, r.Rank, r.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to save trader rank: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveRankSnapshot appends the given leaderboard positions to the rank history.
+func (db *DB) SaveRankSnapshot(snapshot []RankSnapshot) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO rank_history (trader_id, rank, profit_loss, recorded_at) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare rank history insert: %w", err)
+	}
+	defer stmt.Close()
+	for _, r := range snapshot {
+		if _, err := stmt.Exec(r.TraderID, r.Rank, r.ProfitLoss, r.RecordedAt); err != nil {
+			return fmt.Errorf("failed to save rank snapshot: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRankSnapshot returns the latest leaderboard recorded at or before at. If
+// nothing was recorded that early it falls back to the oldest snapshot after
+// it. The map is empty when no history exists.
+func (db *DB) GetRankSnapshot(at time.Time) (map[string]RankSnapshot, error) {
+	var recordedAt sql.NullString
+	err := db.conn.QueryRow(`SELECT MAX(recorded_at) FROM rank_history WHERE recorded_at <= ?`, at).Scan(&recordedAt)
+	if err == nil && !recordedAt.Valid {
+		err = db.conn.QueryRow(`SELECT MIN(recorded_at) FROM rank_history`).Scan(&recordedAt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rank snapshot: %w", err)
+	}
+
+	snapshot := make(map[string]RankSnapshot)
+	if !recordedAt.Valid {
+		return snapshot, nil
+	}
+
+	rows, err := db.conn.Query(`SELECT trader_id, rank, profit_loss, recorded_at FROM rank_history WHERE recorded_at = ?`, recordedAt.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rank snapshot: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r RankSnapshot
+		if err := rows.Scan(&r.TraderID, &r.Rank, &r.ProfitLoss, &r.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rank snapshot: %w", err)
+		}
+		snapshot[r.TraderID] = r
+	}
+	return snapshot, nil
+}
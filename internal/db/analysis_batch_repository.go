@@ -0,0 +1,163 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SaveAnalysisBatch records a submitted batch and its pending items.
+func (db *DB) SaveAnalysisBatch(b *AnalysisBatch) error {
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now()
+	}
+	if b.Status == "" {
+		b.Status = BatchStatusSubmitted
+	}
+
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO analysis_batches (id, source, model, prompt_template, prompt_hash, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		b.ID, b.Source, b.Model, b.PromptTemplate, b.PromptHash, b.Status, b.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis batch: %w", err)
+	}
+
+	for i := range b.Items {
+		item := &b.Items[i]
+		item.BatchID = b.ID
+		if item.Status == "" {
+			item.Status = BatchItemPending
+		}
+		_, err := tx.Exec(`INSERT INTO analysis_batch_items (batch_id, trader_id, prompt, status) VALUES (?, ?, ?, ?)`,
+			item.BatchID, item.TraderID, item.Prompt, item.Status)
+		if err != nil {
+			return fmt.Errorf("failed to save batch item for trader %s: %w", item.TraderID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analysis batch: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysisBatch returns the batch with the given ID and its items, or nil
+// if there is none.
+func (db *DB) GetAnalysisBatch(id string) (*AnalysisBatch, error) {
+	batches, err := db.queryAnalysisBatches(`WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+	return &batches[0], nil
+}
+
+// ListOpenAnalysisBatches returns the batches whose results have not been
+// collected yet, oldest first.
+func (db *DB) ListOpenAnalysisBatches() ([]AnalysisBatch, error) {
+	return db.queryAnalysisBatches(`WHERE status = ?`, BatchStatusSubmitted)
+}
+
+func (db *DB) queryAnalysisBatches(where string, args ...interface{}) ([]AnalysisBatch, error) {
+	query := `SELECT id, source, model, prompt_template, prompt_hash, status, created_at, ended_at
+			  FROM analysis_batches ` + where + ` ORDER BY created_at, id`
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []AnalysisBatch
+	for rows.Next() {
+		var b AnalysisBatch
+		var ended sql.NullTime
+		if err := rows.Scan(&b.ID, &b.Source, &b.Model, &b.PromptTemplate, &b.PromptHash, &b.Status, &b.CreatedAt, &ended); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis batch: %w", err)
+		}
+		b.EndedAt = ended.Time
+		batches = append(batches, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get analysis batches: %w", err)
+	}
+	rows.Close()
+
+	for i := range batches {
+		if batches[i].Items, err = db.analysisBatchItems(batches[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return batches, nil
+}
+
+func (db *DB) analysisBatchItems(batchID string) ([]AnalysisBatchItem, error) {
+	rows, err := db.conn.Query(`SELECT batch_id, trader_id, prompt, status, analysis_id, error
+		FROM analysis_batch_items WHERE batch_id = ? ORDER BY rowid`, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []AnalysisBatchItem
+	for rows.Next() {
+		var item AnalysisBatchItem
+		if err := rows.Scan(&item.BatchID, &item.TraderID, &item.Prompt, &item.Status, &item.AnalysisID, &item.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan batch item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SaveBatchAnalysis stores a batch item's analysis and marks the item saved
+// in one transaction, so a collection interrupted part way can be rerun
+// without saving any analysis twice.
+func (db *DB) SaveBatchAnalysis(batchID string, a *Analysis) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertAnalysis(tx.Exec, a); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE analysis_batch_items SET status = ?, analysis_id = ?, error = ''
+		WHERE batch_id = ? AND trader_id = ?`, BatchItemSaved, a.ID, batchID, a.TraderID)
+	if err != nil {
+		return fmt.Errorf("failed to update batch item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit batch analysis: %w", err)
+	}
+	return nil
+}
+
+// FailAnalysisBatchItem marks a batch item failed with the reason given.
+func (db *DB) FailAnalysisBatchItem(batchID, traderID, reason string) error {
+	_, err := db.exec(`UPDATE analysis_batch_items SET status = ?, error = ? WHERE batch_id = ? AND trader_id = ?`,
+		BatchItemFailed, reason, batchID, traderID)
+	if err != nil {
+		return fmt.Errorf("failed to update batch item: %w", err)
+	}
+	return nil
+}
+
+// FinishAnalysisBatch marks a batch collected.
+func (db *DB) FinishAnalysisBatch(id string) error {
+	_, err := db.exec(`UPDATE analysis_batches SET status = ?, ended_at = ? WHERE id = ?`,
+		BatchStatusCollected, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to finish analysis batch: %w", err)
+	}
+	return nil
+}
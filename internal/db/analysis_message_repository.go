@@ -0,0 +1,58 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// SaveAnalysisMessages appends turns to an analysis's follow-up conversation.
+// A question and its reply are saved together so a stored conversation
+// always alternates between user and assistant.
+func (db *DB) SaveAnalysisMessages(messages ...*AnalysisMessage) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, m := range messages {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = time.Now()
+		}
+		result, err := tx.Exec(`INSERT INTO analysis_messages (analysis_id, role, content, model, input_tokens, output_tokens, cost_usd, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.AnalysisID, m.Role, m.Content, m.Model, m.InputTokens, m.OutputTokens, m.CostUSD, m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save analysis message: %w", err)
+		}
+		if m.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analysis messages: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysisMessages returns an analysis's follow-up conversation, oldest first.
+func (db *DB) GetAnalysisMessages(analysisID int64) ([]AnalysisMessage, error) {
+	query := `SELECT id, analysis_id, role, content, model, input_tokens, output_tokens, cost_usd, created_at
+			  FROM analysis_messages WHERE analysis_id = ? ORDER BY id`
+	rows, err := db.conn.Query(query, analysisID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []AnalysisMessage
+	for rows.Next() {
+		var m AnalysisMessage
+		if err := rows.Scan(&m.ID, &m.AnalysisID, &m.Role, &m.Content, &m.Model, &m.InputTokens, &m.OutputTokens, &m.CostUSD, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
@@ -2,14 +2,90 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 )
 
+// analysisColumns lists the analyses columns in the order scanAnalysis reads them.
+const analysisColumns = `id, trader_id, thesis, model, created_at,
+	archetype, market_focus, risk_score, timing_style, edge_confidence, copyability, key_risks,
+	input_tokens, output_tokens, stop_reason, cost_usd, prompt_version, prompt_template, prompt_hash, prompt`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAnalysis(row rowScanner) (Analysis, error) {
+	var a Analysis
+	var s ThesisSummary
+	var marketFocus, keyRisks string
+	err := row.Scan(&a.ID, &a.TraderID, &a.Thesis, &a.Model, &a.CreatedAt,
+		&s.Archetype, &marketFocus, &s.RiskScore, &s.TimingStyle, &s.EdgeConfidence, &s.Copyability, &keyRisks,
+		&a.InputTokens, &a.OutputTokens, &a.StopReason, &a.CostUSD, &a.PromptVersion, &a.PromptTemplate, &a.PromptHash, &a.Prompt)
+	if err != nil {
+		return a, err
+	}
+	// Analyses without a summary leave every summary column empty.
+	if s.Archetype != "" {
+		if err := decodeList(marketFocus, &s.MarketFocus); err != nil {
+			return a, fmt.Errorf("failed to decode market focus: %w", err)
+		}
+		if err := decodeList(keyRisks, &s.KeyRisks); err != nil {
+			return a, fmt.Errorf("failed to decode key risks: %w", err)
+		}
+		a.Summary = &s
+	}
+	return a, nil
+}
+
+// encodeList stores a string list as a JSON array; nil and empty lists are
+// stored as an empty string.
+func encodeList(list []string) (string, error) {
+	if len(list) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(list)
+	return string(b), err
+}
+
+func decodeList(s string, list *[]string) error {
+	if s == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), list)
+}
+
 func (db *DB) SaveAnalysis(a *Analysis) error {
-	query := `INSERT INTO analyses (trader_id, thesis, created_at)
-			  VALUES (?, ?, ?)`
+	return insertAnalysis(db.exec, a)
+}
+
+// insertAnalysis stores a new analysis with exec, which is either the
+// database's or a transaction's, and sets its ID.
+func insertAnalysis(exec func(string, ...interface{}) (sql.Result, error), a *Analysis) error {
+	query := `INSERT INTO analyses (trader_id, thesis, model, created_at,
+				archetype, market_focus, risk_score, timing_style, edge_confidence, copyability, key_risks,
+				input_tokens, output_tokens, stop_reason, cost_usd, prompt_version, prompt_template, prompt_hash, prompt)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	result, err := db.conn.Exec(query, a.TraderID, a.Thesis, a.CreatedAt)
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	var s ThesisSummary
+	if a.Summary != nil {
+		s = *a.Summary
+	}
+	marketFocus, err := encodeList(s.MarketFocus)
+	if err != nil {
+		return fmt.Errorf("failed to encode market focus: %w", err)
+	}
+	keyRisks, err := encodeList(s.KeyRisks)
+	if err != nil {
+		return fmt.Errorf("failed to encode key risks: %w", err)
+	}
+	result, err := exec(query, a.TraderID, a.Thesis, a.Model, a.CreatedAt,
+		s.Archetype, marketFocus, s.RiskScore, s.TimingStyle, s.EdgeConfidence, s.Copyability, keyRisks,
+		a.InputTokens, a.OutputTokens, a.StopReason, a.CostUSD, a.PromptVersion, a.PromptTemplate, a.PromptHash, a.Prompt)
 	if err != nil {
 		return fmt.Errorf("failed to save analysis: %w", err)
 	}
@@ -23,13 +99,26 @@ func (db *DB) SaveAnalysis(a *Analysis) error {
 	return nil
 }
 
+// GetAnalysis returns the analysis with the given ID, or nil if there is none.
+func (db *DB) GetAnalysis(id int64) (*Analysis, error) {
+	row := db.conn.QueryRow(`SELECT `+analysisColumns+` FROM analyses WHERE id = ?`, id)
+
+	a, err := scanAnalysis(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	return &a, nil
+}
+
 func (db *DB) GetAnalysisByTrader(traderID string) (*Analysis, error) {
-	query := `SELECT id, trader_id, thesis, created_at FROM analyses
+	query := `SELECT ` + analysisColumns + ` FROM analyses
 			  WHERE trader_id = ? ORDER BY created_at DESC LIMIT 1`
 	row := db.conn.QueryRow(query, traderID)
 
-	var a Analysis
-	err := row.Scan(&a.ID, &a.TraderID, &a.Thesis, &a.CreatedAt)
+	a, err := scanAnalysis(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -40,7 +129,7 @@ func (db *DB) GetAnalysisByTrader(traderID string) (*Analysis, error) {
 }
 
 func (db *DB) GetAllAnalysesByTrader(traderID string) ([]Analysis, error) {
-	query := `SELECT id, trader_id, thesis, created_at FROM analyses
+	query := `SELECT ` + analysisColumns + ` FROM analyses
 			  WHERE trader_id = ? ORDER BY created_at DESC`
 	rows, err := db.conn.Query(query, traderID)
 	if err != nil {
@@ -50,8 +139,8 @@ func (db *DB) GetAllAnalysesByTrader(traderID string) ([]Analysis, error) {
 
 	var analyses []Analysis
 	for rows.Next() {
-		var a Analysis
-		if err := rows.Scan(&a.ID, &a.TraderID, &a.Thesis, &a.CreatedAt); err != nil {
+		a, err := scanAnalysis(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan analysis: %w", err)
 		}
 		analyses = append(analyses, a)
@@ -60,10 +149,131 @@ func (db *DB) GetAllAnalysesByTrader(traderID string) ([]Analysis, error) {
 }
 
 func (db *DB) DeleteAnalysis(id int64) error {
+	if _, err := db.exec(`DELETE FROM analysis_messages WHERE analysis_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete analysis messages: %w", err)
+	}
 	query := `DELETE FROM analyses WHERE id = ?`
-	_, err := db.conn.Exec(query, id)
+	_, err := db.exec(query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete analysis: %w", err)
 	}
 	return nil
 }
+
+// ListAnalysesSince returns analyses created after since, newest first.
+func (db *DB) ListAnalysesSince(since time.Time) ([]Analysis, error) {
+	query := `SELECT ` + analysisColumns + ` FROM analyses
+			  WHERE created_at > ? ORDER BY created_at DESC`
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var analyses []Analysis
+	for rows.Next() {
+		a, err := scanAnalysis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan analysis: %w", err)
+		}
+		analyses = append(analyses, a)
+	}
+	return analyses, nil
+}
+
+// ListAnalyses returns analyses of all traders, newest first.
+func (db *DB) ListAnalyses(limit, offset int) ([]Analysis, error) {
+	query := `SELECT ` + analysisColumns + ` FROM analyses
+			  ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	rows, err := db.conn.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var analyses []Analysis
+	for rows.Next() {
+		a, err := scanAnalysis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan analysis: %w", err)
+		}
+		analyses = append(analyses, a)
+	}
+	return analyses, nil
+}
+
+func (db *DB) CountAnalyses() (int, error) {
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM analyses`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count analyses: %w", err)
+	}
+	return count, nil
+}
+
+// Groupings accepted by AnalysisUsage.
+const (
+	UsageByDay    = "day"
+	UsageByModel  = "model"
+	UsageByTrader = "trader"
+)
+
+// UsageRow totals the Claude usage of one group of requests.
+type UsageRow struct {
+	Key          string
+	Requests     int
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
+
+// usageSources lists every stored Claude request with its usage: trader
+// analyses, follow-up chat replies, comparisons and market analyses. Chat
+// replies saved before their model was recorded count under their analysis's
+// model; trader_id is empty for requests not about a single trader.
+const usageSources = `
+	SELECT created_at, model, trader_id, input_tokens, output_tokens, cost_usd FROM analyses
+	UNION ALL
+	SELECT m.created_at, COALESCE(NULLIF(m.model, ''), a.model), a.trader_id, m.input_tokens, m.output_tokens, m.cost_usd
+	FROM analysis_messages m JOIN analyses a ON a.id = m.analysis_id
+	WHERE m.role = '` + MessageRoleAssistant + `'
+	UNION ALL
+	SELECT created_at, model, '', input_tokens, output_tokens, cost_usd FROM comparisons
+	UNION ALL
+	SELECT created_at, model, '', input_tokens, output_tokens, cost_usd FROM market_analyses`
+
+// AnalysisUsage totals token usage and cost of Claude requests made since the
+// given time, grouped by day, model or trader. Grouping by trader counts only
+// analyses and their chat replies. Days are listed newest first; models and
+// traders by cost, highest first.
+func (db *DB) AnalysisUsage(groupBy string, since time.Time) ([]UsageRow, error) {
+	var key, order, filter string
+	switch groupBy {
+	case UsageByDay:
+		key, order = "substr(created_at, 1, 10)", "key DESC"
+	case UsageByModel:
+		key, order = "model", "cost DESC, key"
+	case UsageByTrader:
+		key, order, filter = "trader_id", "cost DESC, key", " AND trader_id != ''"
+	default:
+		return nil, fmt.Errorf("unknown usage grouping: %s", groupBy)
+	}
+
+	query := `SELECT ` + key + ` AS key, COUNT(*), SUM(input_tokens), SUM(output_tokens), SUM(cost_usd) AS cost
+			  FROM (` + usageSources + `) WHERE created_at >= ?` + filter + `
+			  GROUP BY key ORDER BY ` + order
+	rows, err := db.conn.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []UsageRow
+	for rows.Next() {
+		var u UsageRow
+		if err := rows.Scan(&u.Key, &u.Requests, &u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
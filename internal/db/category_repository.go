@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+)
+
+// UncategorizedLabel is the category reported for markets without one.
+const UncategorizedLabel = "uncategorized"
+
+// categoryPositionsQuery yields one row per (trader, market) with the market's
+// category, traded volume and P&L. Each side held is netted on its own: sells
+// add cash and buys spend it, and any position left open is valued at the
+// latest snapshot price for that side, or at its average entry price when no
+// snapshot exists. %s is a WHERE clause.
+const categoryPositionsQuery = `
+	SELECT p.trader_id, p.market_id, p.category,
+		SUM(p.volume) AS volume, SUM(p.trade_count) AS trade_count, SUM(p.cost) AS cost,
+		SUM(p.cash_flow + p.net_size * COALESCE(
+			(SELECT CASE WHEN p.side = 'NO' THEN s.no_price ELSE s.yes_price END
+			 FROM market_snapshots s WHERE s.market_id = p.market_id
+			 ORDER BY s.timestamp DESC LIMIT 1),
+			CASE WHEN p.buy_size > 0 THEN p.cost / p.buy_size ELSE 0 END
+		)) AS pnl
+	FROM (
+		SELECT t.trader_id, t.market_id,
+			COALESCE(NULLIF(m.category, ''), '` + UncategorizedLabel + `') AS category,
+			UPPER(t.side) AS side,
+			SUM(CASE WHEN UPPER(t.type) = 'SELL' THEN -t.size ELSE t.size END) AS net_size,
+			SUM(CASE WHEN UPPER(t.type) = 'SELL' THEN t.price * t.size ELSE -t.price * t.size END) AS cash_flow,
+			SUM(CASE WHEN UPPER(t.type) = 'SELL' THEN 0 ELSE t.price * t.size END) AS cost,
+			SUM(CASE WHEN UPPER(t.type) = 'SELL' THEN 0 ELSE t.size END) AS buy_size,
+			SUM(t.price * t.size) AS volume,
+			COUNT(*) AS trade_count
+		FROM trades t LEFT JOIN markets m ON m.id = t.market_id
+		%s
+		GROUP BY t.trader_id, t.market_id, UPPER(t.side)
+	) p
+	GROUP BY p.trader_id, p.market_id`
+
+// categoryStatsColumns aggregates categoryPositionsQuery rows into CategoryStats fields.
+const categoryStatsColumns = `SUM(pnl) AS profit_loss,
+	AVG(CASE WHEN pnl > 0 THEN 1.0 ELSE 0.0 END) AS win_rate,
+	CASE WHEN SUM(cost) > 0 THEN SUM(pnl) / SUM(cost) ELSE 0 END AS roi,
+	SUM(volume) AS volume,
+	SUM(trade_count) AS trade_count,
+	COUNT(*) AS market_count`
+
+// GetCategoryBreakdown returns a trader's performance split by market category,
+// most profitable category first.
+func (db *DB) GetCategoryBreakdown(traderID string) ([]CategoryStats, error) {
+	positions := fmt.Sprintf(categoryPositionsQuery, "WHERE t.trader_id = ?")
+	query := `SELECT category, ` + categoryStatsColumns + ` FROM (` + positions + `)
+			  GROUP BY category ORDER BY profit_loss DESC`
+
+	rows, err := db.conn.Query(query, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []CategoryStats
+	for rows.Next() {
+		var c CategoryStats
+		if err := rows.Scan(&c.Category, &c.ProfitLoss, &c.WinRate, &c.ROI, &c.Volume, &c.TradeCount, &c.MarketCount); err != nil {
+			return nil, fmt.Errorf("failed to scan category stats: %w", err)
+		}
+		stats = append(stats, c)
+	}
+	return stats, nil
+}
+
+// ListCategories returns every category that has at least one stored trade.
+func (db *DB) ListCategories() ([]string, error) {
+	query := `SELECT DISTINCT COALESCE(NULLIF(m.category, ''), '` + UncategorizedLabel + `') AS category
+			  FROM trades t LEFT JOIN markets m ON m.id = t.market_id ORDER BY category`
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+// categoryTradersQuery aggregates per-trader stats within the category bound to its
+// single parameter, exposing the same metric columns as the traders table.
+func categoryTradersQuery() string {
+	positions := fmt.Sprintf(categoryPositionsQuery,
+		`WHERE COALESCE(NULLIF(m.category, ''), '`+UncategorizedLabel+`') = ?`)
+	return `SELECT trader_id, ` + categoryStatsColumns + ` FROM (` + positions + `) GROUP BY trader_id`
+}
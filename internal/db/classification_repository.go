@@ -0,0 +1,65 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func (db *DB) SaveTraderClassification(c *TraderClassification) error {
+	query := `INSERT INTO trader_classifications (trader_id, label, confidence, maker_ratio, two_sided_ratio,
+			  trades_per_day, round_size_ratio, sub_second_ratio, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(trader_id) DO UPDATE SET
+			  label=excluded.label,
+			  confidence=excluded.confidence,
+			  maker_ratio=excluded.maker_ratio,
+			  two_sided_ratio=excluded.two_sided_ratio,
+			  trades_per_day=excluded.trades_per_day,
+			  round_size_ratio=excluded.round_size_ratio,
+			  sub_second_ratio=excluded.sub_second_ratio,
+			  updated_at=excluded.updated_at`
+
+	_, err := db.exec(query, c.TraderID, c.Label, c.Confidence, c.MakerRatio, c.TwoSidedRatio,
+		c.TradesPerDay, c.RoundSizeRatio, c.SubSecondRatio, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save trader classification: %w", err)
+	}
+	return nil
+}
+
+func (db *DB) GetTraderClassification(traderID string) (*TraderClassification, error) {
+	query := `SELECT trader_id, label, confidence, maker_ratio, two_sided_ratio,
+			  trades_per_day, round_size_ratio, sub_second_ratio, updated_at
+			  FROM trader_classifications WHERE trader_id = ?`
+	row := db.conn.QueryRow(query, traderID)
+
+	var c TraderClassification
+	err := row.Scan(&c.TraderID, &c.Label, &c.Confidence, &c.MakerRatio, &c.TwoSidedRatio,
+		&c.TradesPerDay, &c.RoundSizeRatio, &c.SubSecondRatio, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trader classification: %w", err)
+	}
+	return &c, nil
+}
+
+// GetTraderLabels returns the classifier label for every classified trader, keyed by address.
+func (db *DB) GetTraderLabels() (map[string]string, error) {
+	rows, err := db.conn.Query(`SELECT trader_id, label FROM trader_classifications`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trader labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]string)
+	for rows.Next() {
+		var traderID, label string
+		if err := rows.Scan(&traderID, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan trader label: %w", err)
+		}
+		labels[traderID] = label
+	}
+	return labels, nil
+}
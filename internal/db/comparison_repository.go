@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const comparisonColumns = `c.id, c.thesis, c.model, c.input_tokens, c.output_tokens, c.stop_reason,
+	c.cost_usd, c.prompt_version, c.prompt_hash, c.created_at`
+
+func scanComparison(row rowScanner) (Comparison, error) {
+	var c Comparison
+	err := row.Scan(&c.ID, &c.Thesis, &c.Model, &c.InputTokens, &c.OutputTokens, &c.StopReason,
+		&c.CostUSD, &c.PromptVersion, &c.PromptHash, &c.CreatedAt)
+	return c, err
+}
+
+// SaveComparison stores a comparison and links it to each of its traders.
+func (db *DB) SaveComparison(c *Comparison) error {
+	if len(c.TraderIDs) < 2 {
+		return fmt.Errorf("a comparison needs at least two traders, got %d", len(c.TraderIDs))
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO comparisons (thesis, model, input_tokens, output_tokens, stop_reason,
+			cost_usd, prompt_version, prompt_hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.Thesis, c.Model, c.InputTokens, c.OutputTokens, c.StopReason,
+		c.CostUSD, c.PromptVersion, c.PromptHash, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save comparison: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	for i, traderID := range c.TraderIDs {
+		_, err := tx.Exec(`INSERT INTO comparison_traders (comparison_id, trader_id, position) VALUES (?, ?, ?)`,
+			id, traderID, i)
+		if err != nil {
+			return fmt.Errorf("failed to link comparison to trader %s: %w", traderID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comparison: %w", err)
+	}
+	c.ID = id
+	return nil
+}
+
+// GetComparison returns the comparison with the given ID, or nil if there is none.
+func (db *DB) GetComparison(id int64) (*Comparison, error) {
+	row := db.conn.QueryRow(`SELECT `+comparisonColumns+` FROM comparisons c WHERE c.id = ?`, id)
+	c, err := scanComparison(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comparison: %w", err)
+	}
+	if c.TraderIDs, err = db.comparisonTraders(c.ID); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListComparisonsByTrader returns the comparisons a trader took part in,
+// newest first.
+func (db *DB) ListComparisonsByTrader(traderID string) ([]Comparison, error) {
+	query := `SELECT ` + comparisonColumns + ` FROM comparisons c
+			  JOIN comparison_traders ct ON ct.comparison_id = c.id
+			  WHERE ct.trader_id = ? ORDER BY c.created_at DESC, c.id DESC`
+	rows, err := db.conn.Query(query, traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comparisons: %w", err)
+	}
+	defer rows.Close()
+
+	var comparisons []Comparison
+	for rows.Next() {
+		c, err := scanComparison(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comparison: %w", err)
+		}
+		comparisons = append(comparisons, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list comparisons: %w", err)
+	}
+	rows.Close()
+
+	for i := range comparisons {
+		if comparisons[i].TraderIDs, err = db.comparisonTraders(comparisons[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return comparisons, nil
+}
+
+// comparisonTraders returns a comparison's traders in prompt order.
+func (db *DB) comparisonTraders(comparisonID int64) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT trader_id FROM comparison_traders
+		WHERE comparison_id = ? ORDER BY position`, comparisonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comparison traders: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan comparison trader: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
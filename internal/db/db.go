@@ -3,6 +3,9 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"time"
+
+	"polytracker/internal/metrics"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -33,6 +36,60 @@ func (db *DB) Close() error {
 	return db.conn.Close()
 }
 
+// exec runs a write statement outside a transaction, recording its latency.
+func (db *DB) exec(query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := db.conn.Exec(query, args...)
+	metrics.ObserveDBWrite(query, time.Since(start))
+	return res, err
+}
+
+// tx is a transaction whose writes record their latency like exec.
+type tx struct {
+	*sql.Tx
+}
+
+// begin starts a transaction whose writes are instrumented.
+func (db *DB) begin() (*tx, error) {
+	t, err := db.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &tx{t}, nil
+}
+
+// Exec runs a write statement in the transaction, recording its latency.
+func (t *tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := t.Tx.Exec(query, args...)
+	metrics.ObserveDBWrite(query, time.Since(start))
+	return res, err
+}
+
+// Prepare returns a statement in the transaction whose executions record their
+// latency.
+func (t *tx) Prepare(query string) (*stmt, error) {
+	s, err := t.Tx.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: s, query: query}, nil
+}
+
+// stmt is a prepared write statement that records the latency of each execution.
+type stmt struct {
+	*sql.Stmt
+	query string
+}
+
+// Exec runs the statement with the given arguments, recording its latency.
+func (s *stmt) Exec(args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := s.Stmt.Exec(args...)
+	metrics.ObserveDBWrite(s.query, time.Since(start))
+	return res, err
+}
+
 func (db *DB) migrate() error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS traders (
@@ -84,6 +141,199 @@ func (db *DB) migrate() error {
 			created_at DATETIME,
 			FOREIGN KEY(trader_id) REFERENCES traders(address)
 		)`,
+		`CREATE TABLE IF NOT EXISTS trader_profiles (
+			trader_id TEXT PRIMARY KEY,
+			median_holding_hours REAL,
+			late_entry_ratio REAL,
+			avg_size_share REAL,
+			scale_in_ratio REAL,
+			momentum_ratio REAL,
+			contrarian_ratio REAL,
+			entries INTEGER,
+			updated_at DATETIME,
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS trader_classifications (
+			trader_id TEXT PRIMARY KEY,
+			label TEXT,
+			confidence REAL,
+			maker_ratio REAL,
+			two_sided_ratio REAL,
+			trades_per_day REAL,
+			round_size_ratio REAL,
+			sub_second_ratio REAL,
+			updated_at DATETIME,
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS trader_similarities (
+			trader_a TEXT,
+			trader_b TEXT,
+			score REAL,
+			market_overlap REAL,
+			co_timing REAL,
+			sizing REAL,
+			updated_at DATETIME,
+			PRIMARY KEY(trader_a, trader_b)
+		)`,
+		`CREATE TABLE IF NOT EXISTS wallet_clusters (
+			trader_id TEXT PRIMARY KEY,
+			cluster_id INTEGER,
+			updated_at DATETIME,
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS paper_allocations (
+			trader_id TEXT PRIMARY KEY,
+			mode TEXT,
+			amount REAL,
+			max_position REAL,
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS paper_cash (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			starting_cash REAL,
+			cash REAL,
+			updated_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS paper_positions (
+			market_id TEXT,
+			side TEXT,
+			shares REAL,
+			cost_basis REAL,
+			realized_pnl REAL,
+			updated_at DATETIME,
+			PRIMARY KEY(market_id, side),
+			FOREIGN KEY(market_id) REFERENCES markets(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS paper_fills (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source_trade_id TEXT UNIQUE,
+			trader_id TEXT,
+			market_id TEXT,
+			side TEXT,
+			type TEXT,
+			price REAL,
+			shares REAL,
+			notional REAL,
+			timestamp DATETIME,
+			FOREIGN KEY(trader_id) REFERENCES traders(address),
+			FOREIGN KEY(market_id) REFERENCES markets(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			market_id TEXT,
+			token_id TEXT,
+			side TEXT,
+			price REAL,
+			size REAL,
+			notional REAL,
+			status TEXT,
+			exchange_order_id TEXT,
+			error TEXT,
+			created_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule TEXT,
+			trader_id TEXT,
+			market_id TEXT,
+			message TEXT,
+			dedup_key TEXT UNIQUE,
+			read INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS trader_ranks (
+			trader_id TEXT PRIMARY KEY,
+			rank INTEGER,
+			updated_at DATETIME,
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS rank_history (
+			trader_id TEXT,
+			rank INTEGER,
+			profit_loss REAL,
+			recorded_at DATETIME,
+			PRIMARY KEY (trader_id, recorded_at)
+		)`,
+		`CREATE TABLE IF NOT EXISTS job_locks (
+			job TEXT PRIMARY KEY,
+			owner TEXT,
+			locked_until DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS job_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			job TEXT,
+			owner TEXT,
+			status TEXT,
+			error TEXT NOT NULL DEFAULT '',
+			started_at DATETIME,
+			finished_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS analysis_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			analysis_id INTEGER,
+			role TEXT,
+			content TEXT,
+			input_tokens INTEGER,
+			output_tokens INTEGER,
+			cost_usd REAL,
+			created_at DATETIME,
+			FOREIGN KEY(analysis_id) REFERENCES analyses(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS comparisons (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			thesis TEXT,
+			model TEXT,
+			input_tokens INTEGER,
+			output_tokens INTEGER,
+			stop_reason TEXT,
+			cost_usd REAL,
+			prompt_version TEXT,
+			prompt_hash TEXT,
+			created_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS comparison_traders (
+			comparison_id INTEGER,
+			trader_id TEXT,
+			position INTEGER,
+			PRIMARY KEY(comparison_id, trader_id),
+			FOREIGN KEY(comparison_id) REFERENCES comparisons(id),
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
+		`CREATE TABLE IF NOT EXISTS market_analyses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			market_id TEXT,
+			thesis TEXT,
+			model TEXT,
+			input_tokens INTEGER,
+			output_tokens INTEGER,
+			stop_reason TEXT,
+			cost_usd REAL,
+			prompt_version TEXT,
+			prompt_hash TEXT,
+			created_at DATETIME,
+			FOREIGN KEY(market_id) REFERENCES markets(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS analysis_batches (
+			id TEXT PRIMARY KEY,
+			source TEXT,
+			model TEXT,
+			prompt_template TEXT,
+			prompt_hash TEXT,
+			status TEXT,
+			created_at DATETIME,
+			ended_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS analysis_batch_items (
+			batch_id TEXT,
+			trader_id TEXT,
+			prompt TEXT,
+			status TEXT,
+			analysis_id INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY(batch_id, trader_id),
+			FOREIGN KEY(batch_id) REFERENCES analysis_batches(id),
+			FOREIGN KEY(trader_id) REFERENCES traders(address)
+		)`,
 		`CREATE TABLE IF NOT EXISTS settings (
 			key TEXT PRIMARY KEY,
 			value TEXT
@@ -96,5 +346,73 @@ func (db *DB) migrate() error {
 		}
 	}
 
+	// Columns added after the initial schema; existing databases are upgraded in place.
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"trades", "role", "TEXT NOT NULL DEFAULT ''"},
+		{"markets", "resolution", "TEXT NOT NULL DEFAULT ''"},
+		{"markets", "resolved_at", "DATETIME"},
+		{"analyses", "model", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "archetype", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "market_focus", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "risk_score", "INTEGER NOT NULL DEFAULT 0"},
+		{"analyses", "timing_style", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "edge_confidence", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "copyability", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "key_risks", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "input_tokens", "INTEGER NOT NULL DEFAULT 0"},
+		{"analyses", "output_tokens", "INTEGER NOT NULL DEFAULT 0"},
+		{"analyses", "stop_reason", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "cost_usd", "REAL NOT NULL DEFAULT 0"},
+		{"analyses", "prompt_version", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "prompt_template", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "prompt_hash", "TEXT NOT NULL DEFAULT ''"},
+		{"analyses", "prompt", "TEXT NOT NULL DEFAULT ''"},
+		{"analysis_messages", "model", "TEXT NOT NULL DEFAULT ''"},
+	}
+
+	for _, c := range columns {
+		if err := db.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan column info for %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
 	return nil
 }
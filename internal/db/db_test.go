@@ -1,11 +1,16 @@
 package db
 
 import (
+	"database/sql"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
+	"polytracker/internal/metrics"
+
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestDB(t *testing.T) {
@@ -157,3 +162,638 @@ func TestWatchlistCRUD(t *testing.T) {
 		t.Errorf("expected empty watchlist, got %d items", len(items))
 	}
 }
+
+func TestCategoryBreakdown(t *testing.T) {
+	dbPath := "test_categories.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now().Truncate(time.Second)
+	for _, m := range []Market{
+		{ID: "m1", Question: "Election A?", Category: "Politics"},
+		{ID: "m2", Question: "Election B?", Category: "Politics"},
+		{ID: "m3", Question: "Final?", Category: "Sports"},
+	} {
+		if err := database.SaveMarket(&m); err != nil {
+			t.Fatalf("failed to save market: %v", err)
+		}
+	}
+	for _, s := range []MarketSnapshot{
+		{MarketID: "m2", YesPrice: 0.3, NoPrice: 0.7, Timestamp: now},
+		{MarketID: "m3", YesPrice: 0.4, NoPrice: 0.6, Timestamp: now},
+	} {
+		if err := database.SaveMarketSnapshot(&s); err != nil {
+			t.Fatalf("failed to save snapshot: %v", err)
+		}
+	}
+	for _, addr := range []string{"0xa", "0xb", "0xc"} {
+		if err := database.SaveTrader(&Trader{Address: addr, LastScanned: now}); err != nil {
+			t.Fatalf("failed to save trader: %v", err)
+		}
+	}
+	for _, tr := range []Trade{
+		// Closed Politics win: +20 on 40 cost.
+		{ID: "a1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 100, Timestamp: now},
+		{ID: "a2", TraderID: "0xa", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.6, Size: 100, Timestamp: now},
+		// Open Politics loss marked at 0.3: -20 on 50 cost.
+		{ID: "a3", TraderID: "0xa", MarketID: "m2", Type: "BUY", Side: "YES", Price: 0.5, Size: 100, Timestamp: now},
+		// Open Sports NO position marked at 0.6: +20 on 10 cost.
+		{ID: "a4", TraderID: "0xa", MarketID: "m3", Type: "BUY", Side: "NO", Price: 0.2, Size: 50, Timestamp: now},
+		{ID: "b1", TraderID: "0xb", MarketID: "m3", Type: "BUY", Side: "NO", Price: 0.2, Size: 100, Timestamp: now},
+		// Market without stored metadata.
+		{ID: "c1", TraderID: "0xc", MarketID: "m9", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: now},
+	} {
+		if err := database.SaveTrade(&tr); err != nil {
+			t.Fatalf("failed to save trade: %v", err)
+		}
+	}
+
+	stats, err := database.GetCategoryBreakdown("0xa")
+	if err != nil {
+		t.Fatalf("failed to get category breakdown: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 categories, got %d", len(stats))
+	}
+	sports, politics := stats[0], stats[1]
+	if sports.Category != "Sports" || politics.Category != "Politics" {
+		t.Fatalf("expected Sports then Politics, got %s then %s", sports.Category, politics.Category)
+	}
+	if !approxEqual(sports.ProfitLoss, 20) || !approxEqual(sports.ROI, 2) || sports.WinRate != 1 {
+		t.Errorf("unexpected Sports stats: %+v", sports)
+	}
+	if !approxEqual(politics.ProfitLoss, 0) || politics.WinRate != 0.5 || politics.MarketCount != 2 || politics.TradeCount != 3 {
+		t.Errorf("unexpected Politics stats: %+v", politics)
+	}
+	if !approxEqual(politics.Volume, 150) {
+		t.Errorf("expected Politics volume 150, got %f", politics.Volume)
+	}
+
+	categories, err := database.ListCategories()
+	if err != nil {
+		t.Fatalf("failed to list categories: %v", err)
+	}
+	if len(categories) != 3 || categories[2] != UncategorizedLabel {
+		t.Errorf("expected Politics, Sports and %s, got %v", UncategorizedLabel, categories)
+	}
+
+	opts := ListTradersOptions{SortBy: SortByProfitLoss, Order: SortDesc, Limit: 10, Category: "Sports"}
+	traders, err := database.ListTradersWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to list traders by category: %v", err)
+	}
+	if len(traders) != 2 || traders[0].Address != "0xb" || traders[1].Address != "0xa" {
+		t.Fatalf("expected 0xb then 0xa, got %+v", traders)
+	}
+	if !approxEqual(traders[0].ProfitLoss, 40) {
+		t.Errorf("expected category P&L 40, got %f", traders[0].ProfitLoss)
+	}
+	count, err := database.CountTradersWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to count traders by category: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 traders in Sports, got %d", count)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
+
+func TestJobLocksAndRuns(t *testing.T) {
+	dbPath := "test_jobs.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	ok, err := database.AcquireJobLock("scan", "a", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected owner a to take the lock, got %v, %v", ok, err)
+	}
+	if ok, _ := database.AcquireJobLock("scan", "b", time.Minute); ok {
+		t.Error("expected owner b to be refused while a holds the lock")
+	}
+	if ok, _ := database.AcquireJobLock("scan", "a", time.Minute); !ok {
+		t.Error("expected owner a to renew its own lock")
+	}
+	if err := database.ReleaseJobLock("scan", "a"); err != nil {
+		t.Fatalf("failed to release lock: %v", err)
+	}
+	if ok, _ := database.AcquireJobLock("scan", "b", -time.Second); !ok {
+		t.Error("expected owner b to take the released lock")
+	}
+	// b's lock has already expired, so a can take it over.
+	if ok, _ := database.AcquireJobLock("scan", "a", time.Minute); !ok {
+		t.Error("expected an expired lock to be taken over")
+	}
+
+	run, err := database.StartJobRun("scan", "a")
+	if err != nil {
+		t.Fatalf("failed to start run: %v", err)
+	}
+	runs, err := database.ListJobRuns("", 10)
+	if err != nil || len(runs) != 1 || runs[0].Status != JobStatusRunning {
+		t.Fatalf("expected one running run, got %+v, %v", runs, err)
+	}
+	if err := database.FinishJobRun(run, os.ErrDeadlineExceeded); err != nil {
+		t.Fatalf("failed to finish run: %v", err)
+	}
+	runs, err = database.ListJobRuns("scan", 10)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected one run, got %+v, %v", runs, err)
+	}
+	if runs[0].Status != JobStatusFailed || runs[0].Error == "" || runs[0].FinishedAt.IsZero() {
+		t.Errorf("expected a failed run with an error, got %+v", runs[0])
+	}
+}
+
+func TestAnalysisModelMigration(t *testing.T) {
+	dbPath := "test_analysis_model.db"
+	defer os.Remove(dbPath)
+
+	// A database created before analyses recorded their model.
+	conn, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE analyses (id INTEGER PRIMARY KEY AUTOINCREMENT, trader_id TEXT, thesis TEXT, created_at DATETIME)`); err != nil {
+		t.Fatalf("failed to create legacy table: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO analyses (trader_id, thesis, created_at) VALUES ('0xold', 'old thesis', ?)`, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("failed to insert legacy analysis: %v", err)
+	}
+	conn.Close()
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	defer database.Close()
+
+	old, err := database.GetAnalysisByTrader("0xold")
+	if err != nil || old == nil {
+		t.Fatalf("failed to read legacy analysis: %v", err)
+	}
+	if old.Model != "" {
+		t.Errorf("expected empty model for legacy analysis, got %q", old.Model)
+	}
+
+	if err := database.SaveAnalysis(&Analysis{TraderID: "0xnew", Thesis: "new thesis", Model: "claude-test", PromptTemplate: "sports", PromptHash: "0123456789ab"}); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+	got, err := database.GetAnalysisByTrader("0xnew")
+	if err != nil || got == nil {
+		t.Fatalf("failed to get analysis: %v", err)
+	}
+	if got.Model != "claude-test" {
+		t.Errorf("expected model claude-test, got %q", got.Model)
+	}
+	if got.PromptTemplate != "sports" || got.PromptHash != "0123456789ab" {
+		t.Errorf("expected prompt sports@0123456789ab, got %s@%s", got.PromptTemplate, got.PromptHash)
+	}
+}
+
+func TestAnalysisSummary(t *testing.T) {
+	dbPath := "test_analysis_summary.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	summary := &ThesisSummary{
+		Archetype:      "momentum",
+		MarketFocus:    []string{"politics", "crypto"},
+		RiskScore:      7,
+		TimingStyle:    "late",
+		EdgeConfidence: "medium",
+		Copyability:    "high",
+		KeyRisks:       []string{"Thin books near resolution"},
+	}
+	if err := database.SaveAnalysis(&Analysis{TraderID: "0xa", Thesis: "with summary", Summary: summary, CreatedAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+	if err := database.SaveAnalysis(&Analysis{TraderID: "0xa", Thesis: "without summary"}); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+
+	analyses, err := database.GetAllAnalysesByTrader("0xa")
+	if err != nil {
+		t.Fatalf("failed to get analyses: %v", err)
+	}
+	if len(analyses) != 2 {
+		t.Fatalf("expected 2 analyses, got %d", len(analyses))
+	}
+	if analyses[0].Summary != nil {
+		t.Errorf("expected no summary, got %+v", analyses[0].Summary)
+	}
+	if !reflect.DeepEqual(analyses[1].Summary, summary) {
+		t.Errorf("expected summary %+v, got %+v", summary, analyses[1].Summary)
+	}
+}
+
+func TestAnalysisUsage(t *testing.T) {
+	dbPath := "test_analysis_usage.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	analyses := []Analysis{
+		{TraderID: "0xa", Thesis: "1", Model: "claude-sonnet", InputTokens: 100, OutputTokens: 10, CostUSD: 0.5, CreatedAt: day1},
+		{TraderID: "0xb", Thesis: "2", Model: "claude-opus", InputTokens: 200, OutputTokens: 20, CostUSD: 2, CreatedAt: day1},
+		{TraderID: "0xa", Thesis: "3", Model: "claude-sonnet", InputTokens: 300, OutputTokens: 30, CostUSD: 1, CreatedAt: day2},
+	}
+	for i := range analyses {
+		if err := database.SaveAnalysis(&analyses[i]); err != nil {
+			t.Fatalf("failed to save analysis: %v", err)
+		}
+	}
+
+	byDay, err := database.AnalysisUsage(UsageByDay, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(byDay) != 2 || byDay[0].Key != "2026-03-02" || byDay[1].Key != "2026-03-01" {
+		t.Fatalf("expected two days newest first, got %+v", byDay)
+	}
+	if byDay[1].Requests != 2 || byDay[1].InputTokens != 300 || byDay[1].OutputTokens != 30 || byDay[1].CostUSD != 2.5 {
+		t.Errorf("unexpected totals for 2026-03-01: %+v", byDay[1])
+	}
+
+	byModel, err := database.AnalysisUsage(UsageByModel, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(byModel) != 2 || byModel[0].Key != "claude-opus" || byModel[1].CostUSD != 1.5 {
+		t.Errorf("expected models by cost, got %+v", byModel)
+	}
+
+	byTrader, err := database.AnalysisUsage(UsageByTrader, day2)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(byTrader) != 1 || byTrader[0].Key != "0xa" || byTrader[0].Requests != 1 {
+		t.Errorf("expected only 0xa since day 2, got %+v", byTrader)
+	}
+
+	if _, err := database.AnalysisUsage("week", time.Time{}); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
+
+func TestAnalysisUsage_AllRequests(t *testing.T) {
+	dbPath := "test_analysis_usage_all.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	analysis := &Analysis{TraderID: "0xa", Thesis: "thesis", Model: "claude-sonnet", InputTokens: 100, OutputTokens: 10, CostUSD: 1, CreatedAt: day}
+	if err := database.SaveAnalysis(analysis); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+	err = database.SaveAnalysisMessages(
+		&AnalysisMessage{AnalysisID: analysis.ID, Role: MessageRoleUser, Content: "why?", CreatedAt: day},
+		&AnalysisMessage{AnalysisID: analysis.ID, Role: MessageRoleAssistant, Content: "because", Model: "claude-haiku",
+			InputTokens: 200, OutputTokens: 20, CostUSD: 0.25, CreatedAt: day},
+	)
+	if err != nil {
+		t.Fatalf("failed to save messages: %v", err)
+	}
+	comparison := &Comparison{TraderIDs: []string{"0xa", "0xb"}, Thesis: "a vs b", Model: "claude-opus",
+		InputTokens: 300, OutputTokens: 30, CostUSD: 4, CreatedAt: day}
+	if err := database.SaveComparison(comparison); err != nil {
+		t.Fatalf("failed to save comparison: %v", err)
+	}
+	marketAnalysis := &MarketAnalysis{MarketID: "m1", Thesis: "crowd", Model: "claude-sonnet",
+		InputTokens: 400, OutputTokens: 40, CostUSD: 2, CreatedAt: day}
+	if err := database.SaveMarketAnalysis(marketAnalysis); err != nil {
+		t.Fatalf("failed to save market analysis: %v", err)
+	}
+
+	byDay, err := database.AnalysisUsage(UsageByDay, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	want := UsageRow{Key: "2026-03-01", Requests: 4, InputTokens: 1000, OutputTokens: 100, CostUSD: 7.25}
+	if len(byDay) != 1 || byDay[0] != want {
+		t.Errorf("expected %+v, got %+v", want, byDay)
+	}
+
+	byModel, err := database.AnalysisUsage(UsageByModel, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	wantModels := []UsageRow{
+		{Key: "claude-opus", Requests: 1, InputTokens: 300, OutputTokens: 30, CostUSD: 4},
+		{Key: "claude-sonnet", Requests: 2, InputTokens: 500, OutputTokens: 50, CostUSD: 3},
+		{Key: "claude-haiku", Requests: 1, InputTokens: 200, OutputTokens: 20, CostUSD: 0.25},
+	}
+	if !reflect.DeepEqual(byModel, wantModels) {
+		t.Errorf("expected %+v, got %+v", wantModels, byModel)
+	}
+
+	byTrader, err := database.AnalysisUsage(UsageByTrader, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(byTrader) != 1 || byTrader[0].Key != "0xa" || byTrader[0].Requests != 2 || byTrader[0].CostUSD != 1.25 {
+		t.Errorf("expected the analysis and its chat reply under 0xa, got %+v", byTrader)
+	}
+}
+
+func TestComparisons(t *testing.T) {
+	dbPath := "test_comparisons.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.SaveComparison(&Comparison{TraderIDs: []string{"0xa"}, Thesis: "alone"}); err == nil {
+		t.Error("expected an error for a single-trader comparison")
+	}
+
+	older := &Comparison{TraderIDs: []string{"0xb", "0xa"}, Thesis: "b vs a", Model: "claude-sonnet", CostUSD: 0.25, CreatedAt: time.Now().Add(-time.Hour)}
+	newer := &Comparison{TraderIDs: []string{"0xa", "0xc", "0xd"}, Thesis: "a vs c vs d"}
+	for _, c := range []*Comparison{older, newer} {
+		if err := database.SaveComparison(c); err != nil {
+			t.Fatalf("failed to save comparison: %v", err)
+		}
+	}
+	if older.ID == 0 || newer.ID == older.ID {
+		t.Fatalf("expected distinct IDs, got %d and %d", older.ID, newer.ID)
+	}
+
+	got, err := database.GetComparison(older.ID)
+	if err != nil {
+		t.Fatalf("failed to get comparison: %v", err)
+	}
+	if got == nil || got.Thesis != "b vs a" || got.CostUSD != 0.25 || !reflect.DeepEqual(got.TraderIDs, []string{"0xb", "0xa"}) {
+		t.Errorf("unexpected comparison: %+v", got)
+	}
+	if missing, err := database.GetComparison(999); err != nil || missing != nil {
+		t.Errorf("expected no comparison, got %+v, %v", missing, err)
+	}
+
+	forA, err := database.ListComparisonsByTrader("0xa")
+	if err != nil {
+		t.Fatalf("failed to list comparisons: %v", err)
+	}
+	if len(forA) != 2 || forA[0].ID != newer.ID || !reflect.DeepEqual(forA[0].TraderIDs, []string{"0xa", "0xc", "0xd"}) {
+		t.Errorf("expected both comparisons newest first, got %+v", forA)
+	}
+	forC, err := database.ListComparisonsByTrader("0xc")
+	if err != nil {
+		t.Fatalf("failed to list comparisons: %v", err)
+	}
+	if len(forC) != 1 || forC[0].ID != newer.ID {
+		t.Errorf("expected one comparison for 0xc, got %+v", forC)
+	}
+}
+
+func TestAnalysisMessages(t *testing.T) {
+	dbPath := "test_analysis_messages.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	analysis := &Analysis{TraderID: "0xa", Thesis: "thesis", Prompt: "the prompt"}
+	if err := database.SaveAnalysis(analysis); err != nil {
+		t.Fatalf("failed to save analysis: %v", err)
+	}
+	got, err := database.GetAnalysis(analysis.ID)
+	if err != nil {
+		t.Fatalf("failed to get analysis: %v", err)
+	}
+	if got == nil || got.Prompt != "the prompt" {
+		t.Fatalf("expected the stored prompt, got %+v", got)
+	}
+
+	question := &AnalysisMessage{AnalysisID: analysis.ID, Role: MessageRoleUser, Content: "Why sports?"}
+	reply := &AnalysisMessage{AnalysisID: analysis.ID, Role: MessageRoleAssistant, Content: "Most volume.", InputTokens: 50, OutputTokens: 5, CostUSD: 0.01}
+	if err := database.SaveAnalysisMessages(question, reply); err != nil {
+		t.Fatalf("failed to save messages: %v", err)
+	}
+	if question.ID == 0 || reply.ID <= question.ID {
+		t.Errorf("expected increasing IDs, got %d and %d", question.ID, reply.ID)
+	}
+
+	messages, err := database.GetAnalysisMessages(analysis.ID)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Role != MessageRoleUser || messages[1].Content != "Most volume." || messages[1].OutputTokens != 5 {
+		t.Errorf("unexpected conversation: %+v", messages)
+	}
+
+	if err := database.DeleteAnalysis(analysis.ID); err != nil {
+		t.Fatalf("failed to delete analysis: %v", err)
+	}
+	messages, err = database.GetAnalysisMessages(analysis.ID)
+	if err != nil {
+		t.Fatalf("failed to get messages: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("expected messages to be deleted with the analysis, got %d", len(messages))
+	}
+}
+
+func TestCategoryBreakdown_BothSides(t *testing.T) {
+	dbPath := "test_categories_both_sides.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	now := time.Now().Truncate(time.Second)
+	if err := database.SaveMarket(&Market{ID: "m1", Question: "Final?", Category: "Sports"}); err != nil {
+		t.Fatalf("failed to save market: %v", err)
+	}
+	if err := database.SaveMarketSnapshot(&MarketSnapshot{MarketID: "m1", YesPrice: 0.3, NoPrice: 0.7, Timestamp: now}); err != nil {
+		t.Fatalf("failed to save snapshot: %v", err)
+	}
+	// YES marked at 0.3: -10 on 40 cost. NO marked at 0.7: +20 on 50 cost.
+	for _, tr := range []Trade{
+		{ID: "y1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 100, Timestamp: now},
+		{ID: "n1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "no", Price: 0.5, Size: 100, Timestamp: now},
+	} {
+		if err := database.SaveTrade(&tr); err != nil {
+			t.Fatalf("failed to save trade: %v", err)
+		}
+	}
+
+	stats, err := database.GetCategoryBreakdown("0xa")
+	if err != nil {
+		t.Fatalf("failed to get category breakdown: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 category, got %d", len(stats))
+	}
+	sports := stats[0]
+	if !approxEqual(sports.ProfitLoss, 10) || !approxEqual(sports.ROI, 10.0/90) {
+		t.Errorf("expected each side valued at its own price, got %+v", sports)
+	}
+	if sports.MarketCount != 1 || sports.TradeCount != 2 || sports.WinRate != 1 {
+		t.Errorf("expected both sides counted as one winning market, got %+v", sports)
+	}
+}
+
+func TestMarketAnalyses(t *testing.T) {
+	dbPath := "test_market_analyses.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	if missing, err := database.GetMarketAnalysisByMarket("m1"); err != nil || missing != nil {
+		t.Errorf("expected no market analysis, got %+v, %v", missing, err)
+	}
+
+	older := &MarketAnalysis{MarketID: "m1", Thesis: "whales on yes", Model: "claude-sonnet", CostUSD: 0.05, CreatedAt: time.Now().Add(-time.Hour)}
+	newer := &MarketAnalysis{MarketID: "m1", Thesis: "flow turned to no", InputTokens: 900, OutputTokens: 300}
+	other := &MarketAnalysis{MarketID: "m2", Thesis: "quiet"}
+	for _, a := range []*MarketAnalysis{older, newer, other} {
+		if err := database.SaveMarketAnalysis(a); err != nil {
+			t.Fatalf("failed to save market analysis: %v", err)
+		}
+	}
+	if older.ID == 0 || newer.ID == older.ID {
+		t.Fatalf("expected distinct IDs, got %d and %d", older.ID, newer.ID)
+	}
+
+	latest, err := database.GetMarketAnalysisByMarket("m1")
+	if err != nil {
+		t.Fatalf("failed to get market analysis: %v", err)
+	}
+	if latest == nil || latest.ID != newer.ID || latest.InputTokens != 900 {
+		t.Errorf("expected the newer analysis, got %+v", latest)
+	}
+
+	all, err := database.GetAllMarketAnalysesByMarket("m1")
+	if err != nil {
+		t.Fatalf("failed to get market analyses: %v", err)
+	}
+	if len(all) != 2 || all[1].Thesis != "whales on yes" || all[1].CostUSD != 0.05 {
+		t.Errorf("expected both analyses newest first, got %+v", all)
+	}
+}
+
+func TestAnalysisBatches(t *testing.T) {
+	dbPath := "test_analysis_batches.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	if missing, err := database.GetAnalysisBatch("msgbatch_none"); err != nil || missing != nil {
+		t.Errorf("expected no batch, got %+v, %v", missing, err)
+	}
+
+	batch := &AnalysisBatch{ID: "msgbatch_1", Source: "top", Model: "claude-sonnet", PromptTemplate: "default", PromptHash: "abc123",
+		Items: []AnalysisBatchItem{{TraderID: "0xb", Prompt: "prompt b"}, {TraderID: "0xa", Prompt: "prompt a"}}}
+	if err := database.SaveAnalysisBatch(batch); err != nil {
+		t.Fatalf("failed to save batch: %v", err)
+	}
+
+	open, err := database.ListOpenAnalysisBatches()
+	if err != nil {
+		t.Fatalf("failed to list open batches: %v", err)
+	}
+	if len(open) != 1 || len(open[0].Items) != 2 || open[0].Items[0].TraderID != "0xb" || open[0].Items[0].Status != BatchItemPending {
+		t.Fatalf("expected the batch with its pending items in order, got %+v", open)
+	}
+
+	analysis := &Analysis{TraderID: "0xb", Thesis: "patient whale", Prompt: "prompt b"}
+	if err := database.SaveBatchAnalysis("msgbatch_1", analysis); err != nil {
+		t.Fatalf("failed to save batch analysis: %v", err)
+	}
+	if err := database.FailAnalysisBatchItem("msgbatch_1", "0xa", "expired"); err != nil {
+		t.Fatalf("failed to fail batch item: %v", err)
+	}
+	if err := database.FinishAnalysisBatch("msgbatch_1"); err != nil {
+		t.Fatalf("failed to finish batch: %v", err)
+	}
+
+	got, err := database.GetAnalysisBatch("msgbatch_1")
+	if err != nil {
+		t.Fatalf("failed to get batch: %v", err)
+	}
+	if got.Status != BatchStatusCollected || got.EndedAt.IsZero() || got.PromptHash != "abc123" {
+		t.Errorf("expected a collected batch, got %+v", got)
+	}
+	if got.Items[0].Status != BatchItemSaved || got.Items[0].AnalysisID != analysis.ID {
+		t.Errorf("expected the first item saved as analysis %d, got %+v", analysis.ID, got.Items[0])
+	}
+	if got.Items[1].Status != BatchItemFailed || got.Items[1].Error != "expired" {
+		t.Errorf("expected the second item failed, got %+v", got.Items[1])
+	}
+
+	saved, err := database.GetAnalysisByTrader("0xb")
+	if err != nil || saved == nil || saved.Thesis != "patient whale" {
+		t.Errorf("expected the batch analysis saved, got %+v, %v", saved, err)
+	}
+
+	if open, err := database.ListOpenAnalysisBatches(); err != nil || len(open) != 0 {
+		t.Errorf("expected no open batches, got %+v, %v", open, err)
+	}
+}
+
+func TestTransactionWritesObserved(t *testing.T) {
+	dbPath := "test_tx_metrics.db"
+	defer os.Remove(dbPath)
+
+	database, err := NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer database.Close()
+
+	// ReplaceTraderRanks deletes and inserts trader_ranks inside a transaction;
+	// each statement gets its own series.
+	before := testutil.CollectAndCount(metrics.DBWriteDuration)
+	if err := database.ReplaceTraderRanks([]TraderRank{{TraderID: "0xa", Rank: 1, UpdatedAt: time.Now()}}); err != nil {
+		t.Fatalf("failed to replace ranks: %v", err)
+	}
+	if got := testutil.CollectAndCount(metrics.DBWriteDuration) - before; got != 2 {
+		t.Errorf("expected delete and insert on trader_ranks observed, got %d new series", got)
+	}
+}
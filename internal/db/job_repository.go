@@ -0,0 +1,97 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AcquireJobLock takes the named lock for owner until ttl from now. It returns
+// false when another owner holds an unexpired lock, so only one daemon runs a
+// job at a time against the same database.
+func (db *DB) AcquireJobLock(job, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	query := `INSERT INTO job_locks (job, owner, locked_until) VALUES (?, ?, ?)
+			  ON CONFLICT(job) DO UPDATE SET
+			  owner=excluded.owner,
+			  locked_until=excluded.locked_until
+			  WHERE job_locks.owner = excluded.owner OR job_locks.locked_until < ?`
+
+	res, err := db.exec(query, job, owner, now.Add(ttl), now)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ReleaseJobLock frees the named lock if owner still holds it.
+func (db *DB) ReleaseJobLock(job, owner string) error {
+	if _, err := db.exec(`DELETE FROM job_locks WHERE job = ? AND owner = ?`, job, owner); err != nil {
+		return fmt.Errorf("failed to release job lock: %w", err)
+	}
+	return nil
+}
+
+// StartJobRun records that a job has started and returns the run.
+func (db *DB) StartJobRun(job, owner string) (*JobRun, error) {
+	run := &JobRun{Job: job, Owner: owner, Status: JobStatusRunning, StartedAt: time.Now()}
+	res, err := db.exec(`INSERT INTO job_runs (job, owner, status, started_at) VALUES (?, ?, ?, ?)`,
+		run.Job, run.Owner, run.Status, run.StartedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record job run: %w", err)
+	}
+	if run.ID, err = res.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return run, nil
+}
+
+// FinishJobRun marks a run succeeded, or failed with runErr.
+func (db *DB) FinishJobRun(run *JobRun, runErr error) error {
+	run.Status = JobStatusSucceeded
+	run.FinishedAt = time.Now()
+	if runErr != nil {
+		run.Status = JobStatusFailed
+		run.Error = runErr.Error()
+	}
+	_, err := db.exec(`UPDATE job_runs SET status = ?, error = ?, finished_at = ? WHERE id = ?`,
+		run.Status, run.Error, run.FinishedAt, run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+	return nil
+}
+
+// ListJobRuns returns the most recent runs, newest first. An empty job lists every job.
+func (db *DB) ListJobRuns(job string, limit int) ([]JobRun, error) {
+	query := `SELECT id, job, owner, status, error, started_at, finished_at FROM job_runs`
+	var args []interface{}
+	if job != "" {
+		query += ` WHERE job = ?`
+		args = append(args, job)
+	}
+	query += ` ORDER BY started_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []JobRun
+	for rows.Next() {
+		var r JobRun
+		var finished sql.NullTime
+		if err := rows.Scan(&r.ID, &r.Job, &r.Owner, &r.Status, &r.Error, &r.StartedAt, &finished); err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		r.FinishedAt = finished.Time
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const marketAnalysisColumns = `id, market_id, thesis, model, input_tokens, output_tokens, stop_reason,
+	cost_usd, prompt_version, prompt_hash, created_at`
+
+func scanMarketAnalysis(row rowScanner) (MarketAnalysis, error) {
+	var a MarketAnalysis
+	err := row.Scan(&a.ID, &a.MarketID, &a.Thesis, &a.Model, &a.InputTokens, &a.OutputTokens, &a.StopReason,
+		&a.CostUSD, &a.PromptVersion, &a.PromptHash, &a.CreatedAt)
+	return a, err
+}
+
+func (db *DB) SaveMarketAnalysis(a *MarketAnalysis) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	result, err := db.exec(`INSERT INTO market_analyses (market_id, thesis, model, input_tokens, output_tokens,
+			stop_reason, cost_usd, prompt_version, prompt_hash, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.MarketID, a.Thesis, a.Model, a.InputTokens, a.OutputTokens,
+		a.StopReason, a.CostUSD, a.PromptVersion, a.PromptHash, a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save market analysis: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	a.ID = id
+	return nil
+}
+
+// GetMarketAnalysisByMarket returns the market's latest analysis, or nil if
+// it has none.
+func (db *DB) GetMarketAnalysisByMarket(marketID string) (*MarketAnalysis, error) {
+	row := db.conn.QueryRow(`SELECT `+marketAnalysisColumns+` FROM market_analyses
+		WHERE market_id = ? ORDER BY created_at DESC, id DESC LIMIT 1`, marketID)
+	a, err := scanMarketAnalysis(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market analysis: %w", err)
+	}
+	return &a, nil
+}
+
+// GetAllMarketAnalysesByMarket returns the market's analyses, newest first.
+func (db *DB) GetAllMarketAnalysesByMarket(marketID string) ([]MarketAnalysis, error) {
+	rows, err := db.conn.Query(`SELECT `+marketAnalysisColumns+` FROM market_analyses
+		WHERE market_id = ? ORDER BY created_at DESC, id DESC`, marketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market analyses: %w", err)
+	}
+	defer rows.Close()
+
+	var analyses []MarketAnalysis
+	for rows.Next() {
+		a, err := scanMarketAnalysis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market analysis: %w", err)
+		}
+		analyses = append(analyses, a)
+	}
+	return analyses, rows.Err()
+}
@@ -3,19 +3,37 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 )
 
+// marketColumns lists the markets columns in the order scanMarket reads them.
+const marketColumns = `id, question, description, category, ends_at, status, resolution, resolved_at`
+
+func scanMarket(row rowScanner) (Market, error) {
+	var m Market
+	var resolvedAt sql.NullTime
+	err := row.Scan(&m.ID, &m.Question, &m.Description, &m.Category, &m.EndsAt, &m.Status, &m.Resolution, &resolvedAt)
+	m.ResolvedAt = resolvedAt.Time
+	return m, err
+}
+
 func (db *DB) SaveMarket(m *Market) error {
-	query := `INSERT INTO markets (id, question, description, category, ends_at, status)
-			  VALUES (?, ?, ?, ?, ?, ?)
+	query := `INSERT INTO markets (id, question, description, category, ends_at, status, resolution, resolved_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 			  ON CONFLICT(id) DO UPDATE SET
 			  question=excluded.question,
 			  description=excluded.description,
 			  category=excluded.category,
 			  ends_at=excluded.ends_at,
-			  status=excluded.status`
-	
-	_, err := db.conn.Exec(query, m.ID, m.Question, m.Description, m.Category, m.EndsAt, m.Status)
+			  status=excluded.status,
+			  resolution=excluded.resolution,
+			  resolved_at=excluded.resolved_at`
+
+	var resolvedAt interface{}
+	if !m.ResolvedAt.IsZero() {
+		resolvedAt = m.ResolvedAt
+	}
+	_, err := db.exec(query, m.ID, m.Question, m.Description, m.Category, m.EndsAt, m.Status, m.Resolution, resolvedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save market: %w", err)
 	}
@@ -23,11 +41,10 @@ func (db *DB) SaveMarket(m *Market) error {
 }
 
 func (db *DB) GetMarket(id string) (*Market, error) {
-	query := `SELECT id, question, description, category, ends_at, status FROM markets WHERE id = ?`
+	query := `SELECT ` + marketColumns + ` FROM markets WHERE id = ?`
 	row := db.conn.QueryRow(query, id)
 
-	var m Market
-	err := row.Scan(&m.ID, &m.Question, &m.Description, &m.Category, &m.EndsAt, &m.Status)
+	m, err := scanMarket(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -41,7 +58,7 @@ func (db *DB) SaveMarketSnapshot(s *MarketSnapshot) error {
 	query := `INSERT INTO market_snapshots (market_id, yes_price, no_price, timestamp)
 			  VALUES (?, ?, ?, ?)`
 	
-	_, err := db.conn.Exec(query, s.MarketID, s.YesPrice, s.NoPrice, s.Timestamp)
+	_, err := db.exec(query, s.MarketID, s.YesPrice, s.NoPrice, s.Timestamp)
 	if err != nil {
 		return fmt.Errorf("failed to save market snapshot: %w", err)
 	}
@@ -63,3 +80,116 @@ func (db *DB) GetLatestMarketSnapshot(marketID string) (*MarketSnapshot, error)
 	}
 	return &s, nil
 }
+
+func (db *DB) GetMarketSnapshots(marketID string) ([]MarketSnapshot, error) {
+	query := `SELECT id, market_id, yes_price, no_price, timestamp FROM market_snapshots
+			  WHERE market_id = ? ORDER BY timestamp ASC`
+	rows, err := db.conn.Query(query, marketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []MarketSnapshot
+	for rows.Next() {
+		var s MarketSnapshot
+		if err := rows.Scan(&s.ID, &s.MarketID, &s.YesPrice, &s.NoPrice, &s.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan market snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+// ListMarketsExcludingStatus returns stored markets whose status is not one of statuses.
+func (db *DB) ListMarketsExcludingStatus(statuses ...string) ([]Market, error) {
+	query := `SELECT ` + marketColumns + ` FROM markets`
+	args := make([]interface{}, len(statuses))
+	if len(statuses) > 0 {
+		query += ` WHERE status NOT IN (?` + strings.Repeat(", ?", len(statuses)-1) + `)`
+		for i, s := range statuses {
+			args[i] = s
+		}
+	}
+	query += ` ORDER BY id`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list markets: %w", err)
+	}
+	defer rows.Close()
+
+	var markets []Market
+	for rows.Next() {
+		m, err := scanMarket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market: %w", err)
+		}
+		markets = append(markets, m)
+	}
+	return markets, nil
+}
+
+// ListMarketsOptions filters and pages ListMarkets.
+type ListMarketsOptions struct {
+	// Query matches markets whose question contains it, case-insensitively.
+	Query  string
+	Status string
+	Limit  int
+	Offset int
+}
+
+func (opts ListMarketsOptions) filterClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if opts.Query != "" {
+		conditions = append(conditions, "question LIKE ?")
+		args = append(args, "%"+opts.Query+"%")
+	}
+	if opts.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, opts.Status)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListMarkets returns stored markets ordered by end date, latest first.
+func (db *DB) ListMarkets(opts ListMarketsOptions) ([]Market, error) {
+	filter, args := opts.filterClause()
+	query := `SELECT ` + marketColumns + ` FROM markets` + filter + ` ORDER BY ends_at DESC, id`
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
+		if opts.Offset > 0 {
+			query += fmt.Sprintf(" OFFSET %d", opts.Offset)
+		}
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list markets: %w", err)
+	}
+	defer rows.Close()
+
+	var markets []Market
+	for rows.Next() {
+		m, err := scanMarket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan market: %w", err)
+		}
+		markets = append(markets, m)
+	}
+	return markets, nil
+}
+
+// CountMarkets counts markets matching the filters in opts, ignoring paging.
+func (db *DB) CountMarkets(opts ListMarketsOptions) (int, error) {
+	filter, args := opts.filterClause()
+	var count int
+	if err := db.conn.QueryRow(`SELECT COUNT(*) FROM markets`+filter, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count markets: %w", err)
+	}
+	return count, nil
+}
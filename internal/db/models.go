@@ -23,6 +23,7 @@ type Trade struct {
 	Price     float64   `json:"price"`
 	Size      float64   `json:"size"`
 	Timestamp time.Time `json:"timestamp"`
+	Role      string    `json:"role"` // maker/taker, empty if unknown
 }
 
 type Market struct {
@@ -32,6 +33,10 @@ type Market struct {
 	Category    string    `json:"category"`
 	EndsAt      time.Time `json:"ends_at"`
 	Status      string    `json:"status"` // open/closed/resolved
+	// Resolution is the winning outcome ("Yes" or "No") of a resolved market,
+	// and ResolvedAt when it resolved; both are empty until then.
+	Resolution string    `json:"resolution"`
+	ResolvedAt time.Time `json:"resolved_at"`
 }
 
 type MarketSnapshot struct {
@@ -46,7 +51,128 @@ type Analysis struct {
 	ID        int64     `json:"id"`
 	TraderID  string    `json:"trader_id"`
 	Thesis    string    `json:"thesis"`
+	Model     string    `json:"model"` // Claude model that wrote the thesis; empty for older analyses
 	CreatedAt time.Time `json:"created_at"`
+	// Summary holds Claude's structured conclusions; nil for older analyses
+	// and for theses where Claude did not return a valid summary.
+	Summary *ThesisSummary `json:"summary,omitempty"`
+	// Usage and provenance of the request that wrote the thesis; zero for
+	// older analyses.
+	InputTokens   int64   `json:"input_tokens"`
+	OutputTokens  int64   `json:"output_tokens"`
+	StopReason    string  `json:"stop_reason"`
+	CostUSD       float64 `json:"cost_usd"`
+	PromptVersion string  `json:"prompt_version"`
+	// PromptTemplate and PromptHash identify the prompt template used.
+	PromptTemplate string `json:"prompt_template"`
+	PromptHash     string `json:"prompt_hash"`
+	// Prompt is the rendered prompt the thesis answered, kept so follow-up
+	// chats can replay it; empty for older analyses. Left out of JSON for size.
+	Prompt string `json:"-"`
+}
+
+// Roles of the messages in a follow-up conversation.
+const (
+	MessageRoleUser      = "user"
+	MessageRoleAssistant = "assistant"
+)
+
+// AnalysisMessage is one turn of a follow-up conversation about an analysis.
+// Usage is recorded on assistant messages only.
+type AnalysisMessage struct {
+	ID           int64     `json:"id"`
+	AnalysisID   int64     `json:"analysis_id"`
+	Role         string    `json:"role"`
+	Content      string    `json:"content"`
+	Model        string    `json:"model"`
+	InputTokens  int64     `json:"input_tokens"`
+	OutputTokens int64     `json:"output_tokens"`
+	CostUSD      float64   `json:"cost_usd"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ThesisSummary is the structured part of an analysis, kept alongside the
+// narrative so traders can be filtered and sorted by Claude's conclusions.
+type ThesisSummary struct {
+	Archetype      string   `json:"archetype"`
+	MarketFocus    []string `json:"market_focus"`
+	RiskScore      int      `json:"risk_score"` // 1 (conservative) to 10 (reckless)
+	TimingStyle    string   `json:"timing_style"`
+	EdgeConfidence string   `json:"edge_confidence"`
+	Copyability    string   `json:"copyability"`
+	KeyRisks       []string `json:"key_risks"`
+}
+
+// Comparison is a Claude write-up contrasting several traders. TraderIDs keeps
+// the order the traders were presented in the prompt.
+type Comparison struct {
+	ID            int64     `json:"id"`
+	TraderIDs     []string  `json:"trader_ids"`
+	Thesis        string    `json:"thesis"`
+	Model         string    `json:"model"`
+	InputTokens   int64     `json:"input_tokens"`
+	OutputTokens  int64     `json:"output_tokens"`
+	StopReason    string    `json:"stop_reason"`
+	CostUSD       float64   `json:"cost_usd"`
+	PromptVersion string    `json:"prompt_version"`
+	PromptHash    string    `json:"prompt_hash"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// MarketAnalysis is a Claude read on who is trading a market and how the
+// better-scoring traders are positioned.
+type MarketAnalysis struct {
+	ID            int64     `json:"id"`
+	MarketID      string    `json:"market_id"`
+	Thesis        string    `json:"thesis"`
+	Model         string    `json:"model"`
+	InputTokens   int64     `json:"input_tokens"`
+	OutputTokens  int64     `json:"output_tokens"`
+	StopReason    string    `json:"stop_reason"`
+	CostUSD       float64   `json:"cost_usd"`
+	PromptVersion string    `json:"prompt_version"`
+	PromptHash    string    `json:"prompt_hash"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Analysis batch statuses. A batch stays submitted until its results have
+// been collected into analyses.
+const (
+	BatchStatusSubmitted = "submitted"
+	BatchStatusCollected = "collected"
+)
+
+// Analysis batch item statuses.
+const (
+	BatchItemPending = "pending"
+	BatchItemSaved   = "saved"
+	BatchItemFailed  = "failed"
+)
+
+// AnalysisBatch is a set of trader analyses submitted together through the
+// Message Batches API. ID is the API's batch ID.
+type AnalysisBatch struct {
+	ID string `json:"id"`
+	// Source records what selected the traders: "top" or "watchlist".
+	Source         string              `json:"source"`
+	Model          string              `json:"model"`
+	PromptTemplate string              `json:"prompt_template"`
+	PromptHash     string              `json:"prompt_hash"`
+	Status         string              `json:"status"`
+	CreatedAt      time.Time           `json:"created_at"`
+	EndedAt        time.Time           `json:"ended_at"`
+	Items          []AnalysisBatchItem `json:"items"`
+}
+
+// AnalysisBatchItem is one trader's request in a batch. Prompt is kept so the
+// saved analysis records what it answers; AnalysisID is set once it is saved.
+type AnalysisBatchItem struct {
+	BatchID    string `json:"batch_id"`
+	TraderID   string `json:"trader_id"`
+	Prompt     string `json:"prompt"`
+	Status     string `json:"status"`
+	AnalysisID int64  `json:"analysis_id"`
+	Error      string `json:"error"`
 }
 
 type WatchlistItem struct {
@@ -55,6 +181,193 @@ type WatchlistItem struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// TraderProfile holds behavioral statistics derived from a trader's history.
+type TraderProfile struct {
+	TraderID           string    `json:"trader_id"`
+	MedianHoldingHours float64   `json:"median_holding_hours"`
+	LateEntryRatio     float64   `json:"late_entry_ratio"` // share of entries in the last 24h before market end
+	AvgSizeShare       float64   `json:"avg_size_share"`   // average entry notional as share of market volume
+	ScaleInRatio       float64   `json:"scale_in_ratio"`   // share of markets entered more than once
+	MomentumRatio      float64   `json:"momentum_ratio"`   // share of entries after the price moved up
+	ContrarianRatio    float64   `json:"contrarian_ratio"` // share of entries after the price moved down
+	Entries            int       `json:"entries"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Trader type labels assigned by the classifier.
+const (
+	TraderTypeUnknown     = "unknown"
+	TraderTypeDirectional = "directional"
+	TraderTypeMarketMaker = "market_maker"
+	TraderTypeBot         = "bot"
+)
+
+// TraderClassification labels a trader as directional, market maker or bot, along
+// with the features the heuristic classifier used.
+type TraderClassification struct {
+	TraderID       string    `json:"trader_id"`
+	Label          string    `json:"label"`
+	Confidence     float64   `json:"confidence"`
+	MakerRatio     float64   `json:"maker_ratio"`
+	TwoSidedRatio  float64   `json:"two_sided_ratio"`
+	TradesPerDay   float64   `json:"trades_per_day"`
+	RoundSizeRatio float64   `json:"round_size_ratio"`
+	SubSecondRatio float64   `json:"sub_second_ratio"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TraderSimilarity scores how alike two traders are. TraderA sorts before TraderB.
+type TraderSimilarity struct {
+	TraderA       string    `json:"trader_a"`
+	TraderB       string    `json:"trader_b"`
+	Score         float64   `json:"score"`
+	MarketOverlap float64   `json:"market_overlap"` // Jaccard overlap of markets traded
+	CoTiming      float64   `json:"co_timing"`      // share of entries made alongside the other trader
+	Sizing        float64   `json:"sizing"`         // similarity of typical entry size
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Other returns the trader in the pair that is not traderID.
+func (s TraderSimilarity) Other(traderID string) string {
+	if s.TraderA == traderID {
+		return s.TraderB
+	}
+	return s.TraderA
+}
+
+// WalletCluster assigns a trader to a group of wallets likely controlled by one entity.
+type WalletCluster struct {
+	TraderID  string    `json:"trader_id"`
+	ClusterID int       `json:"cluster_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoryStats is a trader's performance within one market category. P&L and
+// win rate are per market, marking open positions to the latest snapshot.
+type CategoryStats struct {
+	Category    string  `json:"category"`
+	ProfitLoss  float64 `json:"profit_loss"`
+	WinRate     float64 `json:"win_rate"`
+	ROI         float64 `json:"roi"`
+	Volume      float64 `json:"volume"`
+	TradeCount  int     `json:"trade_count"`
+	MarketCount int     `json:"market_count"`
+}
+
+// Paper allocation modes decide how much of a watched trader's buy is mirrored.
+const (
+	AllocationFixed        = "fixed"        // Amount dollars per mirrored buy
+	AllocationProportional = "proportional" // Amount times the source trade's notional
+)
+
+// PaperAllocation is the per-trader rule for mirroring trades into the paper portfolio.
+type PaperAllocation struct {
+	TraderID    string  `json:"trader_id"`
+	Mode        string  `json:"mode"`
+	Amount      float64 `json:"amount"`
+	MaxPosition float64 `json:"max_position"` // cap on cost basis per market outcome; 0 means no cap
+}
+
+// PaperAccount is the paper portfolio's cash balance.
+type PaperAccount struct {
+	StartingCash float64   `json:"starting_cash"`
+	Cash         float64   `json:"cash"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// PaperPosition is the paper portfolio's holding in one market outcome.
+type PaperPosition struct {
+	MarketID    string    `json:"market_id"`
+	Side        string    `json:"side"`
+	Shares      float64   `json:"shares"`
+	CostBasis   float64   `json:"cost_basis"`
+	RealizedPnL float64   `json:"realized_pnl"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PaperFill is a mirrored copy of a watched trader's trade.
+type PaperFill struct {
+	ID            int64     `json:"id"`
+	SourceTradeID string    `json:"source_trade_id"`
+	TraderID      string    `json:"trader_id"`
+	MarketID      string    `json:"market_id"`
+	Side          string    `json:"side"`
+	Type          string    `json:"type"` // BUY/SELL
+	Price         float64   `json:"price"`
+	Shares        float64   `json:"shares"`
+	Notional      float64   `json:"notional"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Order statuses recorded for placed orders.
+const (
+	OrderStatusDryRun    = "dry_run"
+	OrderStatusSubmitted = "submitted"
+	OrderStatusRejected  = "rejected"
+)
+
+// Order is a CLOB limit order built by polytracker, whether or not it was sent.
+type Order struct {
+	ID              int64     `json:"id"`
+	MarketID        string    `json:"market_id"`
+	TokenID         string    `json:"token_id"`
+	Side            string    `json:"side"` // BUY/SELL
+	Price           float64   `json:"price"`
+	Size            float64   `json:"size"`
+	Notional        float64   `json:"notional"`
+	Status          string    `json:"status"`
+	ExchangeOrderID string    `json:"exchange_order_id"`
+	Error           string    `json:"error"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Alert is a fired alert rule. DedupKey identifies the triggering event so the
+// same event never fires twice.
+type Alert struct {
+	ID        int64     `json:"id"`
+	Rule      string    `json:"rule"`
+	TraderID  string    `json:"trader_id"`
+	MarketID  string    `json:"market_id"`
+	Message   string    `json:"message"`
+	DedupKey  string    `json:"dedup_key"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TraderRank is a trader's last observed leaderboard position, used to detect rank changes.
+type TraderRank struct {
+	TraderID  string    `json:"trader_id"`
+	Rank      int       `json:"rank"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RankSnapshot is a trader's leaderboard position at one point in time, kept so
+// digests can compare today's leaderboard with yesterday's.
+type RankSnapshot struct {
+	TraderID   string    `json:"trader_id"`
+	Rank       int       `json:"rank"`
+	ProfitLoss float64   `json:"profit_loss"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Job run statuses.
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// JobRun is one execution of a scheduled daemon job.
+type JobRun struct {
+	ID         int64     `json:"id"`
+	Job        string    `json:"job"`
+	Owner      string    `json:"owner"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
 type Setting struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
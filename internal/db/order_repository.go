@@ -0,0 +1,63 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+func (db *DB) SaveOrder(o *Order) error {
+	query := `INSERT INTO orders (market_id, token_id, side, price, size, notional, status, exchange_order_id, error, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = time.Now()
+	}
+	res, err := db.exec(query, o.MarketID, o.TokenID, o.Side, o.Price, o.Size, o.Notional,
+		o.Status, o.ExchangeOrderID, o.Error, o.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save order: %w", err)
+	}
+	if id, err := res.LastInsertId(); err == nil {
+		o.ID = id
+	}
+	return nil
+}
+
+// SumSubmittedNotional totals the notional of orders sent to the exchange since
+// the given time, for one market or all markets when marketID is empty.
+func (db *DB) SumSubmittedNotional(marketID string, since time.Time) (float64, error) {
+	query := `SELECT COALESCE(SUM(notional), 0) FROM orders WHERE status = ? AND created_at >= ?`
+	args := []interface{}{OrderStatusSubmitted, since}
+	if marketID != "" {
+		query += ` AND market_id = ?`
+		args = append(args, marketID)
+	}
+
+	var total float64
+	if err := db.conn.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum order notional: %w", err)
+	}
+	return total, nil
+}
+
+// ListOrders returns the most recent orders, newest first.
+func (db *DB) ListOrders(limit int) ([]Order, error) {
+	query := `SELECT id, market_id, token_id, side, price, size, notional, status, exchange_order_id, error, created_at
+			  FROM orders ORDER BY created_at DESC, id DESC LIMIT ?`
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []Order
+	for rows.Next() {
+		var o Order
+		if err := rows.Scan(&o.ID, &o.MarketID, &o.TokenID, &o.Side, &o.Price, &o.Size, &o.Notional,
+			&o.Status, &o.ExchangeOrderID, &o.Error, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+func (db *DB) SavePaperAllocation(a *PaperAllocation) error {
+	query := `INSERT INTO paper_allocations (trader_id, mode, amount, max_position)
+			  VALUES (?, ?, ?, ?)
+			  ON CONFLICT(trader_id) DO UPDATE SET
+			  mode=excluded.mode,
+			  amount=excluded.amount,
+			  max_position=excluded.max_position`
+
+	_, err := db.exec(query, a.TraderID, a.Mode, a.Amount, a.MaxPosition)
+	if err != nil {
+		return fmt.Errorf("failed to save paper allocation: %w", err)
+	}
+	return nil
+}
+
+// GetPaperAllocation returns the trader's allocation rule, or nil if none is set.
+func (db *DB) GetPaperAllocation(traderID string) (*PaperAllocation, error) {
+	query := `SELECT trader_id, mode, amount, max_position FROM paper_allocations WHERE trader_id = ?`
+	row := db.conn.QueryRow(query, traderID)
+
+	var a PaperAllocation
+	err := row.Scan(&a.TraderID, &a.Mode, &a.Amount, &a.MaxPosition)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper allocation: %w", err)
+	}
+	return &a, nil
+}
+
+// GetPaperAccount returns the paper cash balance, or nil if the portfolio has not
+// been funded yet.
+func (db *DB) GetPaperAccount() (*PaperAccount, error) {
+	query := `SELECT starting_cash, cash, updated_at FROM paper_cash WHERE id = 1`
+	row := db.conn.QueryRow(query)
+
+	var a PaperAccount
+	err := row.Scan(&a.StartingCash, &a.Cash, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper account: %w", err)
+	}
+	return &a, nil
+}
+
+// ResetPaperPortfolio clears all paper positions and fills and funds the account
+// with startingCash.
+func (db *DB) ResetPaperPortfolio(startingCash float64) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, q := range []string{`DELETE FROM paper_positions`, `DELETE FROM paper_fills`} {
+		if _, err := tx.Exec(q); err != nil {
+			return fmt.Errorf("failed to reset paper portfolio: %w", err)
+		}
+	}
+	query := `INSERT INTO paper_cash (id, starting_cash, cash, updated_at)
+			  VALUES (1, ?, ?, ?)
+			  ON CONFLICT(id) DO UPDATE SET
+			  starting_cash=excluded.starting_cash,
+			  cash=excluded.cash,
+			  updated_at=excluded.updated_at`
+	if _, err := tx.Exec(query, startingCash, startingCash, time.Now()); err != nil {
+		return fmt.Errorf("failed to fund paper account: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RecordPaperFill stores a mirrored fill together with the resulting position and
+// cash balance. A nil position records the source trade as seen without changing
+// holdings. A fill whose source trade was already mirrored is ignored and
+// reported as not recorded.
+func (db *DB) RecordPaperFill(f *PaperFill, pos *PaperPosition, cash float64) (bool, error) {
+	tx, err := db.begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT OR IGNORE INTO paper_fills
+			(source_trade_id, trader_id, market_id, side, type, price, shares, notional, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		f.SourceTradeID, f.TraderID, f.MarketID, f.Side, f.Type, f.Price, f.Shares, f.Notional, f.Timestamp)
+	if err != nil {
+		return false, fmt.Errorf("failed to save paper fill: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	if pos == nil {
+		return true, tx.Commit()
+	}
+
+	_, err = tx.Exec(`INSERT INTO paper_positions (market_id, side, shares, cost_basis, realized_pnl, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(market_id, side) DO UPDATE SET
+			shares=excluded.shares,
+			cost_basis=excluded.cost_basis,
+			realized_pnl=excluded.realized_pnl,
+			updated_at=excluded.updated_at`,
+		pos.MarketID, pos.Side, pos.Shares, pos.CostBasis, pos.RealizedPnL, pos.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to save paper position: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE paper_cash SET cash = ?, updated_at = ? WHERE id = 1`, cash, time.Now()); err != nil {
+		return false, fmt.Errorf("failed to update paper cash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit paper fill: %w", err)
+	}
+	return true, nil
+}
+
+// HasPaperFill reports whether the source trade has already been mirrored.
+func (db *DB) HasPaperFill(sourceTradeID string) (bool, error) {
+	var n int
+	err := db.conn.QueryRow(`SELECT COUNT(*) FROM paper_fills WHERE source_trade_id = ?`, sourceTradeID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to check paper fill: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetPaperPosition returns the holding in a market outcome, or nil if there is none.
+func (db *DB) GetPaperPosition(marketID, side string) (*PaperPosition, error) {
+	query := `SELECT market_id, side, shares, cost_basis, realized_pnl, updated_at
+			  FROM paper_positions WHERE market_id = ? AND side = ?`
+	row := db.conn.QueryRow(query, marketID, side)
+
+	var p PaperPosition
+	err := row.Scan(&p.MarketID, &p.Side, &p.Shares, &p.CostBasis, &p.RealizedPnL, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper position: %w", err)
+	}
+	return &p, nil
+}
+
+// ListPaperPositions returns every position, including closed ones with realized P&L.
+func (db *DB) ListPaperPositions() ([]PaperPosition, error) {
+	query := `SELECT market_id, side, shares, cost_basis, realized_pnl, updated_at
+			  FROM paper_positions ORDER BY updated_at DESC`
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paper positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []PaperPosition
+	for rows.Next() {
+		var p PaperPosition
+		if err := rows.Scan(&p.MarketID, &p.Side, &p.Shares, &p.CostBasis, &p.RealizedPnL, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan paper position: %w", err)
+		}
+		positions = append(positions, p)
+	}
+	return positions, nil
+}
+
+// ListPaperFills returns the most recent mirrored fills, newest first. Source
+// trades that were seen but not copied are omitted.
+func (db *DB) ListPaperFills(limit int) ([]PaperFill, error) {
+	query := `SELECT id, source_trade_id, trader_id, market_id, side, type, price, shares, notional, timestamp
+			  FROM paper_fills WHERE shares > 0 ORDER BY timestamp DESC, id DESC LIMIT ?`
+	rows, err := db.conn.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paper fills: %w", err)
+	}
+	defer rows.Close()
+
+	var fills []PaperFill
+	for rows.Next() {
+		var f PaperFill
+		if err := rows.Scan(&f.ID, &f.SourceTradeID, &f.TraderID, &f.MarketID, &f.Side, &f.Type, &f.Price, &f.Shares, &f.Notional, &f.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan paper fill: %w", err)
+		}
+		fills = append(fills, f)
+	}
+	return fills, nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func (db *DB) SaveTraderProfile(p *TraderProfile) error {
+	query := `INSERT INTO trader_profiles (trader_id, median_holding_hours, late_entry_ratio, avg_size_share,
+			  scale_in_ratio, momentum_ratio, contrarian_ratio, entries, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(trader_id) DO UPDATE SET
+			  median_holding_hours=excluded.median_holding_hours,
+			  late_entry_ratio=excluded.late_entry_ratio,
+			  avg_size_share=excluded.avg_size_share,
+			  scale_in_ratio=excluded.scale_in_ratio,
+			  momentum_ratio=excluded.momentum_ratio,
+			  contrarian_ratio=excluded.contrarian_ratio,
+			  entries=excluded.entries,
+			  updated_at=excluded.updated_at`
+
+	_, err := db.exec(query, p.TraderID, p.MedianHoldingHours, p.LateEntryRatio, p.AvgSizeShare,
+		p.ScaleInRatio, p.MomentumRatio, p.ContrarianRatio, p.Entries, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save trader profile: %w", err)
+	}
+	return nil
+}
+
+func (db *DB) GetTraderProfile(traderID string) (*TraderProfile, error) {
+	query := `SELECT trader_id, median_holding_hours, late_entry_ratio, avg_size_share,
+			  scale_in_ratio, momentum_ratio, contrarian_ratio, entries, updated_at
+			  FROM trader_profiles WHERE trader_id = ?`
+	row := db.conn.QueryRow(query, traderID)
+
+	var p TraderProfile
+	err := row.Scan(&p.TraderID, &p.MedianHoldingHours, &p.LateEntryRatio, &p.AvgSizeShare,
+		&p.ScaleInRatio, &p.MomentumRatio, &p.ContrarianRatio, &p.Entries, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trader profile: %w", err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ReplaceSimilarities replaces all stored trader similarities and wallet clusters
+// with the given results of a fresh clustering run.
+func (db *DB) ReplaceSimilarities(sims []TraderSimilarity, clusters []WalletCluster) error {
+	tx, err := db.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM trader_similarities`); err != nil {
+		return fmt.Errorf("failed to clear similarities: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM wallet_clusters`); err != nil {
+		return fmt.Errorf("failed to clear clusters: %w", err)
+	}
+
+	for _, s := range sims {
+		_, err := tx.Exec(`INSERT INTO trader_similarities (trader_a, trader_b, score, market_overlap, co_timing, sizing, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			s.TraderA, s.TraderB, s.Score, s.MarketOverlap, s.CoTiming, s.Sizing, s.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save similarity: %w", err)
+		}
+	}
+
+	for _, c := range clusters {
+		_, err := tx.Exec(`INSERT INTO wallet_clusters (trader_id, cluster_id, updated_at) VALUES (?, ?, ?)`,
+			c.TraderID, c.ClusterID, c.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save cluster membership: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit similarities: %w", err)
+	}
+	return nil
+}
+
+// GetSimilarTraders returns the most similar traders to traderID, best first.
+func (db *DB) GetSimilarTraders(traderID string, limit int) ([]TraderSimilarity, error) {
+	query := `SELECT trader_a, trader_b, score, market_overlap, co_timing, sizing, updated_at
+			  FROM trader_similarities WHERE trader_a = ? OR trader_b = ?
+			  ORDER BY score DESC LIMIT ?`
+	rows, err := db.conn.Query(query, traderID, traderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar traders: %w", err)
+	}
+	defer rows.Close()
+
+	var sims []TraderSimilarity
+	for rows.Next() {
+		var s TraderSimilarity
+		if err := rows.Scan(&s.TraderA, &s.TraderB, &s.Score, &s.MarketOverlap, &s.CoTiming, &s.Sizing, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan similarity: %w", err)
+		}
+		sims = append(sims, s)
+	}
+	return sims, nil
+}
+
+func (db *DB) GetWalletCluster(traderID string) (*WalletCluster, error) {
+	row := db.conn.QueryRow(`SELECT trader_id, cluster_id, updated_at FROM wallet_clusters WHERE trader_id = ?`, traderID)
+
+	var c WalletCluster
+	err := row.Scan(&c.TraderID, &c.ClusterID, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet cluster: %w", err)
+	}
+	return &c, nil
+}
+
+// ListWalletClusters returns cluster membership as trader addresses keyed by cluster ID.
+func (db *DB) ListWalletClusters() (map[int][]string, error) {
+	rows, err := db.conn.Query(`SELECT trader_id, cluster_id FROM wallet_clusters ORDER BY cluster_id, trader_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet clusters: %w", err)
+	}
+	defer rows.Close()
+
+	clusters := make(map[int][]string)
+	for rows.Next() {
+		var traderID string
+		var clusterID int
+		if err := rows.Scan(&traderID, &clusterID); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet cluster: %w", err)
+		}
+		clusters[clusterID] = append(clusters[clusterID], traderID)
+	}
+	return clusters, nil
+}
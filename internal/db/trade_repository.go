@@ -5,8 +5,8 @@ import (
 )
 
 func (db *DB) SaveTrade(t *Trade) error {
-	query := `INSERT INTO trades (id, trader_id, market_id, type, side, price, size, timestamp)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	query := `INSERT INTO trades (id, trader_id, market_id, type, side, price, size, timestamp, role)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 			  ON CONFLICT(id) DO UPDATE SET
 			  trader_id=excluded.trader_id,
 			  market_id=excluded.market_id,
@@ -14,9 +14,10 @@ func (db *DB) SaveTrade(t *Trade) error {
 			  side=excluded.side,
 			  price=excluded.price,
 			  size=excluded.size,
-			  timestamp=excluded.timestamp`
+			  timestamp=excluded.timestamp,
+			  role=excluded.role`
 	
-	_, err := db.conn.Exec(query, t.ID, t.TraderID, t.MarketID, t.Type, t.Side, t.Price, t.Size, t.Timestamp)
+	_, err := db.exec(query, t.ID, t.TraderID, t.MarketID, t.Type, t.Side, t.Price, t.Size, t.Timestamp, t.Role)
 	if err != nil {
 		return fmt.Errorf("failed to save trade: %w", err)
 	}
@@ -24,7 +25,7 @@ func (db *DB) SaveTrade(t *Trade) error {
 }
 
 func (db *DB) GetTradesByTrader(traderID string) ([]Trade, error) {
-	query := `SELECT id, trader_id, market_id, type, side, price, size, timestamp FROM trades WHERE trader_id = ? ORDER BY timestamp DESC`
+	query := `SELECT id, trader_id, market_id, type, side, price, size, timestamp, role FROM trades WHERE trader_id = ? ORDER BY timestamp DESC`
 	rows, err := db.conn.Query(query, traderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trades by trader: %w", err)
@@ -34,10 +35,48 @@ func (db *DB) GetTradesByTrader(traderID string) ([]Trade, error) {
 	var trades []Trade
 	for rows.Next() {
 		var t Trade
-		if err := rows.Scan(&t.ID, &t.TraderID, &t.MarketID, &t.Type, &t.Side, &t.Price, &t.Size, &t.Timestamp); err != nil {
+		if err := rows.Scan(&t.ID, &t.TraderID, &t.MarketID, &t.Type, &t.Side, &t.Price, &t.Size, &t.Timestamp, &t.Role); err != nil {
 			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 		trades = append(trades, t)
 	}
 	return trades, nil
 }
+
+func (db *DB) GetTradesByMarket(marketID string) ([]Trade, error) {
+	query := `SELECT id, trader_id, market_id, type, side, price, size, timestamp, role FROM trades WHERE market_id = ? ORDER BY timestamp ASC`
+	rows, err := db.conn.Query(query, marketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades by market: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []Trade
+	for rows.Next() {
+		var t Trade
+		if err := rows.Scan(&t.ID, &t.TraderID, &t.MarketID, &t.Type, &t.Side, &t.Price, &t.Size, &t.Timestamp, &t.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
+		}
+		trades = append(trades, t)
+	}
+	return trades, nil
+}
+
+// GetTradedMarketIDs returns the IDs of all markets with at least one stored trade.
+func (db *DB) GetTradedMarketIDs() ([]string, error) {
+	rows, err := db.conn.Query(`SELECT DISTINCT market_id FROM trades ORDER BY market_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get traded markets: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan market id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 )
 
 func (db *DB) SaveTrader(t *Trader) error {
@@ -16,7 +17,7 @@ func (db *DB) SaveTrader(t *Trader) error {
 			  volume=excluded.volume,
 			  last_scanned=excluded.last_scanned`
 	
-	_, err := db.conn.Exec(query, t.Address, t.Username, t.WinRate, t.ProfitLoss, t.ROI, t.Volume, t.LastScanned)
+	_, err := db.exec(query, t.Address, t.Username, t.WinRate, t.ProfitLoss, t.ROI, t.Volume, t.LastScanned)
 	if err != nil {
 		return fmt.Errorf("failed to save trader: %w", err)
 	}
@@ -59,6 +60,61 @@ type ListTradersOptions struct {
 	Order     SortOrder
 	Limit     int
 	Offset    int
+
+	// Behavioral filters backed by trader_profiles; zero values disable them.
+	MinHoldingHours   float64
+	MaxHoldingHours   float64
+	MinLateEntryRatio float64
+
+	// TraderType restricts results to a classifier label; empty includes all traders.
+	TraderType string
+
+	// Category ranks traders within a single market category: only traders with
+	// trades in it are returned, and win rate, P&L, ROI and volume are replaced by
+	// their values within that category.
+	Category string
+}
+
+// hasProfileFilter reports whether any filter requires the trader_profiles join.
+func (opts ListTradersOptions) hasProfileFilter() bool {
+	return opts.MinHoldingHours > 0 || opts.MaxHoldingHours > 0 || opts.MinLateEntryRatio > 0
+}
+
+// filterClause builds the JOIN and WHERE clauses (and their arguments) for the
+// filters set on opts, to be appended after "FROM traders t".
+func (opts ListTradersOptions) filterClause() (string, []interface{}) {
+	var clause string
+	var conditions []string
+	var args []interface{}
+
+	if opts.Category != "" {
+		clause += ` JOIN (` + categoryTradersQuery() + `) cs ON cs.trader_id = t.address`
+		args = append(args, opts.Category)
+	}
+	if opts.hasProfileFilter() {
+		clause += ` JOIN trader_profiles p ON p.trader_id = t.address`
+		if opts.MinHoldingHours > 0 {
+			conditions = append(conditions, "p.median_holding_hours >= ?")
+			args = append(args, opts.MinHoldingHours)
+		}
+		if opts.MaxHoldingHours > 0 {
+			conditions = append(conditions, "p.median_holding_hours <= ?")
+			args = append(args, opts.MaxHoldingHours)
+		}
+		if opts.MinLateEntryRatio > 0 {
+			conditions = append(conditions, "p.late_entry_ratio >= ?")
+			args = append(args, opts.MinLateEntryRatio)
+		}
+	}
+	if opts.TraderType != "" {
+		clause += ` JOIN trader_classifications c ON c.trader_id = t.address`
+		conditions = append(conditions, "c.label = ?")
+		args = append(args, opts.TraderType)
+	}
+	if len(conditions) > 0 {
+		clause += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return clause, args
 }
 
 func (db *DB) ListTraders() ([]Trader, error) {
@@ -76,7 +132,13 @@ func (db *DB) ListTradersWithOptions(opts ListTradersOptions) ([]Trader, error)
 		opts.Order = SortDesc
 	}
 
-	query := fmt.Sprintf(`SELECT address, username, win_rate, profit_loss, roi, volume, last_scanned FROM traders ORDER BY %s %s`, opts.SortBy, opts.Order)
+	filter, args := opts.filterClause()
+	query := `SELECT t.address, t.username, t.win_rate, t.profit_loss, t.roi, t.volume, t.last_scanned FROM traders t` + filter
+	query += fmt.Sprintf(" ORDER BY t.%s %s", opts.SortBy, opts.Order)
+	if opts.Category != "" {
+		query = `SELECT t.address, t.username, cs.win_rate, cs.profit_loss, cs.roi, cs.volume, t.last_scanned FROM traders t` + filter
+		query += fmt.Sprintf(" ORDER BY cs.%s %s", opts.SortBy, opts.Order)
+	}
 
 	if opts.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
@@ -85,7 +147,7 @@ func (db *DB) ListTradersWithOptions(opts ListTradersOptions) ([]Trader, error)
 		}
 	}
 
-	rows, err := db.conn.Query(query)
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list traders: %w", err)
 	}
@@ -103,8 +165,14 @@ func (db *DB) ListTradersWithOptions(opts ListTradersOptions) ([]Trader, error)
 }
 
 func (db *DB) CountTraders() (int, error) {
+	return db.CountTradersWithOptions(ListTradersOptions{})
+}
+
+// CountTradersWithOptions counts traders matching the filters in opts, ignoring sort and paging.
+func (db *DB) CountTradersWithOptions(opts ListTradersOptions) (int, error) {
+	filter, args := opts.filterClause()
 	var count int
-	err := db.conn.QueryRow("SELECT COUNT(*) FROM traders").Scan(&count)
+	err := db.conn.QueryRow("SELECT COUNT(*) FROM traders t"+filter, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count traders: %w", err)
 	}
@@ -27,7 +27,7 @@ func (db *DB) AddToWatchlist(traderID string, notes string) error {
 			  ON CONFLICT(trader_id) DO UPDATE SET
 			  notes=excluded.notes`
 
-	_, err := db.conn.Exec(query, traderID, notes, time.Now())
+	_, err := db.exec(query, traderID, notes, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to add to watchlist: %w", err)
 	}
@@ -37,7 +37,7 @@ func (db *DB) AddToWatchlist(traderID string, notes string) error {
 func (db *DB) RemoveFromWatchlist(traderID string) error {
 	query := `DELETE FROM watchlist WHERE trader_id = ?`
 
-	_, err := db.conn.Exec(query, traderID)
+	_, err := db.exec(query, traderID)
 	if err != nil {
 		return fmt.Errorf("failed to remove from watchlist: %w", err)
 	}
@@ -0,0 +1,179 @@
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+const (
+	DefaultPeriod = 24 * time.Hour
+	DefaultTopN   = 10
+	// DefaultMovers is how many of the largest rank changes are listed.
+	DefaultMovers = 5
+)
+
+// RankedTrader is a leaderboard entry with its position at the start of the period.
+type RankedTrader struct {
+	Trader       db.Trader
+	Rank         int
+	PreviousRank int // 0 when the trader was not ranked
+}
+
+// Change is how many places the trader climbed; negative when they fell.
+func (r RankedTrader) Change() int {
+	if r.PreviousRank == 0 {
+		return 0
+	}
+	return r.PreviousRank - r.Rank
+}
+
+// TraderActivity summarizes a watchlisted trader's trades in the period.
+type TraderActivity struct {
+	TraderID string
+	Trades   int
+	Notional float64
+	Markets  int
+	Last     time.Time
+}
+
+// Digest summarizes what changed over a period.
+type Digest struct {
+	Since         time.Time
+	Until         time.Time
+	NewTopTraders []RankedTrader
+	Movers        []RankedTrader
+	Watchlist     []TraderActivity
+	Analyses      []db.Analysis
+
+	// ranks is the leaderboard at Until, saved by Generator.Record.
+	ranks []db.RankSnapshot
+}
+
+// Empty reports whether nothing happened in the period.
+func (d *Digest) Empty() bool {
+	return len(d.NewTopTraders) == 0 && len(d.Movers) == 0 && len(d.Watchlist) == 0 && len(d.Analyses) == 0
+}
+
+// Generator builds digests from the database.
+type Generator struct {
+	db     *db.DB
+	Period time.Duration
+	TopN   int
+	Movers int
+}
+
+func NewGenerator(database *db.DB) *Generator {
+	return &Generator{
+		db:     database,
+		Period: DefaultPeriod,
+		TopN:   DefaultTopN,
+		Movers: DefaultMovers,
+	}
+}
+
+// Generate summarizes the period ending at now without writing anything;
+// call Record once the digest has been delivered.
+func (g *Generator) Generate(now time.Time) (*Digest, error) {
+	d := &Digest{
+		Since: now.Add(-g.Period),
+		Until: now,
+	}
+
+	traders, err := g.db.ListTradersWithOptions(db.ListTradersOptions{SortBy: db.SortByProfitLoss, Order: db.SortDesc})
+	if err != nil {
+		return nil, err
+	}
+	previous, err := g.db.GetRankSnapshot(d.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]RankedTrader, len(traders))
+	d.ranks = make([]db.RankSnapshot, len(traders))
+	for i, t := range traders {
+		ranked[i] = RankedTrader{Trader: t, Rank: i + 1, PreviousRank: previous[t.Address].Rank}
+		d.ranks[i] = db.RankSnapshot{TraderID: t.Address, Rank: i + 1, ProfitLoss: t.ProfitLoss, RecordedAt: now}
+	}
+
+	// Without an earlier leaderboard there is nothing to compare against.
+	if len(previous) > 0 {
+		for _, r := range ranked {
+			if r.Rank > g.TopN {
+				break
+			}
+			if r.PreviousRank == 0 || r.PreviousRank > g.TopN {
+				d.NewTopTraders = append(d.NewTopTraders, r)
+			}
+		}
+
+		for _, r := range ranked {
+			if r.Change() != 0 {
+				d.Movers = append(d.Movers, r)
+			}
+		}
+		sort.SliceStable(d.Movers, func(i, j int) bool {
+			return abs(d.Movers[i].Change()) > abs(d.Movers[j].Change())
+		})
+		if len(d.Movers) > g.Movers {
+			d.Movers = d.Movers[:g.Movers]
+		}
+	}
+
+	if d.Watchlist, err = g.watchlistActivity(d.Since); err != nil {
+		return nil, err
+	}
+	if d.Analyses, err = g.db.ListAnalysesSince(d.Since); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Record saves the leaderboard the digest was generated from, so the next
+// digest measures rank changes against it.
+func (g *Generator) Record(d *Digest) error {
+	return g.db.SaveRankSnapshot(d.ranks)
+}
+
+func (g *Generator) watchlistActivity(since time.Time) ([]TraderActivity, error) {
+	items, err := g.db.ListWatchlist()
+	if err != nil {
+		return nil, err
+	}
+
+	var activity []TraderActivity
+	for _, item := range items {
+		trades, err := g.db.GetTradesByTrader(item.TraderID)
+		if err != nil {
+			return nil, err
+		}
+		a := TraderActivity{TraderID: item.TraderID}
+		markets := make(map[string]bool)
+		for _, t := range trades {
+			if !t.Timestamp.After(since) {
+				continue
+			}
+			a.Trades++
+			a.Notional += t.Price * t.Size
+			markets[t.MarketID] = true
+			if t.Timestamp.After(a.Last) {
+				a.Last = t.Timestamp
+			}
+		}
+		if a.Trades > 0 {
+			a.Markets = len(markets)
+			activity = append(activity, a)
+		}
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Notional > activity[j].Notional })
+	return activity, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,116 @@
+package digest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	dbPath := "test_digest.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, database.SaveTrader(&db.Trader{
+			Address: fmt.Sprintf("0x%d", i), ProfitLoss: float64(100 - i*10), LastScanned: time.Now(),
+		}))
+	}
+
+	gen := NewGenerator(database)
+	gen.TopN = 2
+
+	yesterday := time.Now().Add(-25 * time.Hour)
+	first, err := gen.Generate(yesterday)
+	require.NoError(t, err)
+	// The first digest has no earlier leaderboard to compare with.
+	assert.Empty(t, first.NewTopTraders)
+	assert.Empty(t, first.Movers)
+
+	// Generating alone records nothing, so a dry run does not move the baseline.
+	ranks, err := database.GetRankSnapshot(yesterday)
+	require.NoError(t, err)
+	assert.Empty(t, ranks)
+	require.NoError(t, gen.Record(first))
+	ranks, err = database.GetRankSnapshot(yesterday)
+	require.NoError(t, err)
+	assert.Len(t, ranks, 5)
+
+	// 0x4 climbs from #5 to #1; everyone else drops one place.
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0x4", ProfitLoss: 500, LastScanned: time.Now()}))
+
+	require.NoError(t, database.AddToWatchlist("0x1", ""))
+	now := time.Now()
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t1", TraderID: "0x1", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 100, Timestamp: now.Add(-time.Hour),
+	}))
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t2", TraderID: "0x1", MarketID: "m2", Type: "BUY", Side: "NO", Price: 0.2, Size: 100, Timestamp: now.Add(-2 * time.Hour),
+	}))
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "old", TraderID: "0x1", MarketID: "m3", Type: "BUY", Side: "NO", Price: 0.2, Size: 100, Timestamp: now.Add(-48 * time.Hour),
+	}))
+	require.NoError(t, database.SaveAnalysis(&db.Analysis{TraderID: "0x4", Thesis: "Momentum trader.", CreatedAt: now.Add(-time.Hour)}))
+
+	d, err := gen.Generate(now)
+	require.NoError(t, err)
+
+	require.Len(t, d.NewTopTraders, 1)
+	assert.Equal(t, "0x4", d.NewTopTraders[0].Trader.Address)
+	assert.Equal(t, 5, d.NewTopTraders[0].PreviousRank)
+
+	require.NotEmpty(t, d.Movers)
+	assert.Equal(t, "0x4", d.Movers[0].Trader.Address)
+	assert.Equal(t, 4, d.Movers[0].Change())
+
+	require.Len(t, d.Watchlist, 1)
+	assert.Equal(t, 2, d.Watchlist[0].Trades)
+	assert.Equal(t, 2, d.Watchlist[0].Markets)
+	assert.InDelta(t, 70.0, d.Watchlist[0].Notional, 0.001)
+
+	require.Len(t, d.Analyses, 1)
+	assert.False(t, d.Empty())
+}
+
+func TestDigest_Render(t *testing.T) {
+	d := &Digest{
+		Since: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		Until: time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC),
+		NewTopTraders: []RankedTrader{
+			{Trader: db.Trader{Address: "0x1234567890abcdef", Username: "whale", ProfitLoss: 1234.5}, Rank: 1, PreviousRank: 14},
+		},
+		Movers: []RankedTrader{
+			{Trader: db.Trader{Address: "0xaaa"}, Rank: 3, PreviousRank: 9},
+		},
+		Watchlist: []TraderActivity{{TraderID: "0xbbb", Trades: 2, Markets: 1, Notional: 80}},
+		Analyses:  []db.Analysis{{TraderID: "0xccc", Thesis: "Fades <overreactions> & news."}},
+	}
+
+	text, err := d.Text()
+	require.NoError(t, err)
+	assert.Contains(t, text, "NEW TOP TRADERS")
+	assert.Contains(t, text, "#1 0x1234...cdef (whale)  P&L $1234.50  was #14")
+	assert.Contains(t, text, "+6  0xaaa  #9 -> #3")
+	assert.Contains(t, text, "0xbbb  2 trade(s) in 1 market(s), $80.00")
+	assert.Contains(t, text, "Fades <overreactions> & news.")
+
+	html, err := d.HTML()
+	require.NoError(t, err)
+	assert.Contains(t, html, "<h3>New top traders</h3>")
+	assert.Contains(t, html, "Fades &lt;overreactions&gt; &amp; news.")
+
+	assert.Equal(t, "Polytracker digest for Mar 2, 2024", d.Subject())
+
+	empty := &Digest{Since: d.Since, Until: d.Until}
+	text, err = empty.Text()
+	require.NoError(t, err)
+	assert.Contains(t, text, "Nothing new in this period.")
+}
@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var funcs = map[string]interface{}{
+	"money": func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"signed": func(n int) string {
+		if n > 0 {
+			return fmt.Sprintf("+%d", n)
+		}
+		return fmt.Sprintf("%d", n)
+	},
+	"short": func(s string) string {
+		if len(s) > 12 {
+			return s[:6] + "..." + s[len(s)-4:]
+		}
+		return s
+	},
+	"excerpt": func(s string) string {
+		if len(s) > 280 {
+			return s[:277] + "..."
+		}
+		return s
+	},
+}
+
+const textTemplate = `Polytracker digest: {{.Since.Format "Jan 2 15:04"}} - {{.Until.Format "Jan 2 15:04"}}
+{{if .Empty}}
+Nothing new in this period.
+{{end}}{{with .NewTopTraders}}
+NEW TOP TRADERS
+{{range .}}  #{{.Rank}} {{short .Trader.Address}}{{with .Trader.Username}} ({{.}}){{end}}  P&L {{money .Trader.ProfitLoss}}{{if .PreviousRank}}  was #{{.PreviousRank}}{{else}}  new{{end}}
+{{end}}{{end}}{{with .Movers}}
+RANK MOVERS
+{{range .}}  {{signed .Change}}  {{short .Trader.Address}}  #{{.PreviousRank}} -> #{{.Rank}}
+{{end}}{{end}}{{with .Watchlist}}
+WATCHLIST ACTIVITY
+{{range .}}  {{short .TraderID}}  {{.Trades}} trade(s) in {{.Markets}} market(s), {{money .Notional}}
+{{end}}{{end}}{{with .Analyses}}
+NEW ANALYSES
+{{range .}}  {{short .TraderID}} ({{.CreatedAt.Format "Jan 2 15:04"}})
+    {{excerpt .Thesis}}
+{{end}}{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; color: #282a36;">
+<h2>Polytracker digest</h2>
+<p style="color: #6272a4;">{{.Since.Format "Jan 2 15:04"}} &ndash; {{.Until.Format "Jan 2 15:04"}}</p>
+{{if .Empty}}<p>Nothing new in this period.</p>{{end}}
+{{with .NewTopTraders}}
+<h3>New top traders</h3>
+<table cellpadding="4">
+<tr><th align="left">Rank</th><th align="left">Trader</th><th align="right">P&amp;L</th><th align="left">Previously</th></tr>
+{{range .}}<tr><td>#{{.Rank}}</td><td><code>{{short .Trader.Address}}</code>{{with .Trader.Username}} {{.}}{{end}}</td><td align="right">{{money .Trader.ProfitLoss}}</td><td>{{if .PreviousRank}}#{{.PreviousRank}}{{else}}new{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Movers}}
+<h3>Rank movers</h3>
+<table cellpadding="4">
+{{range .}}<tr><td style="color: {{if gt .Change 0}}#50fa7b{{else}}#ff5555{{end}};">{{signed .Change}}</td><td><code>{{short .Trader.Address}}</code></td><td>#{{.PreviousRank}} &rarr; #{{.Rank}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Watchlist}}
+<h3>Watchlist activity</h3>
+<table cellpadding="4">
+<tr><th align="left">Trader</th><th align="right">Trades</th><th align="right">Markets</th><th align="right">Notional</th></tr>
+{{range .}}<tr><td><code>{{short .TraderID}}</code></td><td align="right">{{.Trades}}</td><td align="right">{{.Markets}}</td><td align="right">{{money .Notional}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Analyses}}
+<h3>New analyses</h3>
+{{range .}}<p><code>{{short .TraderID}}</code> <span style="color: #6272a4;">{{.CreatedAt.Format "Jan 2 15:04"}}</span><br>{{excerpt .Thesis}}</p>
+{{end}}
+{{end}}
+</body>
+</html>
+`
+
+var (
+	textTmpl = template.Must(template.New("digest.txt").Funcs(funcs).Parse(textTemplate))
+	htmlTmpl = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(funcs).Parse(htmlTemplate))
+)
+
+// Subject is the email subject line for the digest.
+func (d *Digest) Subject() string {
+	return "Polytracker digest for " + d.Until.Format("Jan 2, 2006")
+}
+
+// Text renders the digest as plain text.
+func (d *Digest) Text() (string, error) {
+	var buf bytes.Buffer
+	if err := textTmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// HTML renders the digest as an HTML email body.
+func (d *Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,109 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig describes the mail server digests are delivered through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // optional; PLAIN auth is used when set
+	Password string
+	From     string
+	To       []string
+}
+
+// Sender delivers multipart text/HTML email over SMTP, upgrading to TLS when
+// the server offers STARTTLS.
+type Sender struct {
+	cfg SMTPConfig
+}
+
+func NewSender(cfg SMTPConfig) (*Sender, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("email.smtp_host is not configured")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("email.from and email.to are required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &Sender{cfg: cfg}, nil
+}
+
+// Send delivers one message with plain text and HTML alternatives.
+func (s *Sender) Send(subject, text, html string) error {
+	msg, err := s.buildMessage(subject, text, html)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	if err := smtp.SendMail(addr, auth, s.cfg.From, s.cfg.To, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// SendDigest renders the digest and sends it.
+func (s *Sender) SendDigest(d *Digest) error {
+	text, err := d.Text()
+	if err != nil {
+		return err
+	}
+	html, err := d.HTML()
+	if err != nil {
+		return err
+	}
+	return s.Send(d.Subject(), text, html)
+}
+
+func (s *Sender) buildMessage(subject, text, html string) ([]byte, error) {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate boundary: %w", err)
+	}
+	boundary := "polytracker-" + hex.EncodeToString(b[:])
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", text},
+		{"text/html", html},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=UTF-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, fmt.Errorf("failed to encode email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode email: %w", err)
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes(), nil
+}
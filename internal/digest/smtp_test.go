@@ -0,0 +1,140 @@
+package digest
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStandIn is a minimal SMTP server that accepts one message and records it.
+type smtpStandIn struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       chan string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: l, data: make(chan string, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var body strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.data <- body.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	server := newSMTPStandIn(t)
+	defer server.listener.Close()
+
+	sender, err := NewSender(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "polytracker@example.com",
+		To:   []string{"desk@example.com", "ops@example.com"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send("Daily digest", "plain body", "<p>html body</p>"))
+
+	raw := <-server.data
+	assert.Equal(t, "polytracker@example.com", server.from)
+	assert.Equal(t, []string{"desk@example.com", "ops@example.com"}, server.recipients)
+
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "Daily digest", msg.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		// multipart.Reader decodes quoted-printable parts transparently.
+		body, err := io.ReadAll(p)
+		require.NoError(t, err)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(body)
+	}
+	assert.Equal(t, "plain body", parts["text/plain"])
+	assert.Equal(t, "<p>html body</p>", parts["text/html"])
+}
+
+func TestNewSender_Validation(t *testing.T) {
+	_, err := NewSender(SMTPConfig{From: "a@example.com", To: []string{"b@example.com"}})
+	assert.Error(t, err)
+	_, err = NewSender(SMTPConfig{Host: "localhost"})
+	assert.Error(t, err)
+
+	s, err := NewSender(SMTPConfig{Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, 587, s.cfg.Port)
+}
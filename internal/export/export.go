@@ -83,11 +83,17 @@ func (e *Exporter) ExportLeaderboardCSV(traders []db.Trader, filename string) (s
 
 // ExportThesisMarkdown exports a trading thesis to a markdown file
 func (e *Exporter) ExportThesisMarkdown(trader *db.Trader, thesis string, filename string) (string, error) {
+	return e.ExportAnalysisMarkdown(trader, &db.Analysis{Thesis: thesis}, filename)
+}
+
+// ExportAnalysisMarkdown exports an analysis to a markdown file: the trader
+// summary, Claude's structured conclusions when present, then the thesis.
+func (e *Exporter) ExportAnalysisMarkdown(trader *db.Trader, analysis *db.Analysis, filename string) (string, error) {
 	if err := e.EnsureExportDir(); err != nil {
 		return "", fmt.Errorf("failed to create export directory: %w", err)
 	}
 
-	if thesis == "" {
+	if analysis == nil || analysis.Thesis == "" {
 		return "", fmt.Errorf("no thesis content to export")
 	}
 
@@ -114,6 +120,12 @@ func (e *Exporter) ExportThesisMarkdown(trader *db.Trader, thesis string, filena
 		content.WriteString(fmt.Sprintf("**Trader:** %s\n\n", trader.Username))
 	}
 	content.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	if analysis.Model != "" {
+		content.WriteString(fmt.Sprintf("**Model:** %s\n\n", analysis.Model))
+	}
+	if analysis.PromptTemplate != "" {
+		content.WriteString(fmt.Sprintf("**Prompt:** %s@%s\n\n", analysis.PromptTemplate, analysis.PromptHash))
+	}
 
 	// Write trader stats summary
 	content.WriteString("## Trader Summary\n\n")
@@ -125,9 +137,14 @@ func (e *Exporter) ExportThesisMarkdown(trader *db.Trader, thesis string, filena
 	content.WriteString(fmt.Sprintf("| Volume | $%.2f |\n", trader.Volume))
 	content.WriteString("\n---\n\n")
 
+	if analysis.Summary != nil {
+		content.WriteString(SummaryMarkdown(analysis.Summary))
+		content.WriteString("\n---\n\n")
+	}
+
 	// Write thesis content
 	content.WriteString("## Analysis\n\n")
-	content.WriteString(thesis)
+	content.WriteString(analysis.Thesis)
 
 	// Write footer
 	content.WriteString("\n\n---\n")
@@ -140,6 +157,83 @@ func (e *Exporter) ExportThesisMarkdown(trader *db.Trader, thesis string, filena
 	return path, nil
 }
 
+// SummaryMarkdown renders a structured thesis summary as a markdown section.
+func SummaryMarkdown(s *db.ThesisSummary) string {
+	var content strings.Builder
+	content.WriteString("## Summary\n\n")
+	content.WriteString("| Field | Value |\n")
+	content.WriteString("|-------|-------|\n")
+	content.WriteString(fmt.Sprintf("| Archetype | %s |\n", s.Archetype))
+	content.WriteString(fmt.Sprintf("| Market Focus | %s |\n", strings.Join(s.MarketFocus, ", ")))
+	content.WriteString(fmt.Sprintf("| Risk Score | %d/10 |\n", s.RiskScore))
+	content.WriteString(fmt.Sprintf("| Timing Style | %s |\n", s.TimingStyle))
+	content.WriteString(fmt.Sprintf("| Edge Confidence | %s |\n", s.EdgeConfidence))
+	content.WriteString(fmt.Sprintf("| Copyability | %s |\n", s.Copyability))
+	if len(s.KeyRisks) > 0 {
+		content.WriteString("\n**Key Risks:**\n\n")
+		for _, risk := range s.KeyRisks {
+			content.WriteString(fmt.Sprintf("- %s\n", risk))
+		}
+	}
+	return content.String()
+}
+
+// ExportComparisonMarkdown exports a comparison to a markdown file: a stats
+// table with one row per trader, in comparison order, then Claude's write-up.
+func (e *Exporter) ExportComparisonMarkdown(traders []*db.Trader, comparison *db.Comparison, filename string) (string, error) {
+	if err := e.EnsureExportDir(); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	if comparison == nil || comparison.Thesis == "" {
+		return "", fmt.Errorf("no comparison content to export")
+	}
+
+	if filename == "" {
+		filename = fmt.Sprintf("comparison_%d_%s.md", comparison.ID, time.Now().Format("20060102_150405"))
+	}
+
+	// Ensure .md extension
+	if !strings.HasSuffix(strings.ToLower(filename), ".md") {
+		filename += ".md"
+	}
+
+	path := filepath.Join(e.exportDir, filename)
+
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("# Trader Comparison (%d traders)\n\n", len(traders)))
+	content.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	if comparison.Model != "" {
+		content.WriteString(fmt.Sprintf("**Model:** %s\n\n", comparison.Model))
+	}
+
+	content.WriteString("## Traders\n\n")
+	content.WriteString("| # | Trader | Win Rate | P&L | ROI | Volume |\n")
+	content.WriteString("|---|--------|----------|-----|-----|--------|\n")
+	for i, t := range traders {
+		name := t.Address
+		if t.Username != "" {
+			name = fmt.Sprintf("%s (%s)", t.Username, t.Address)
+		}
+		content.WriteString(fmt.Sprintf("| %d | %s | %.2f%% | $%.2f | %.2f%% | $%.2f |\n",
+			i+1, name, t.WinRate*100, t.ProfitLoss, t.ROI*100, t.Volume))
+	}
+	content.WriteString("\n---\n\n")
+
+	content.WriteString("## Comparison\n\n")
+	content.WriteString(comparison.Thesis)
+
+	content.WriteString("\n\n---\n")
+	content.WriteString(fmt.Sprintf("*Generated by Polytracker on %s*\n", time.Now().Format("2006-01-02 15:04:05")))
+
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return path, nil
+}
+
 // ExportAnalysisFromDB exports an analysis from the database
 func (e *Exporter) ExportAnalysisFromDB(database *db.DB, traderAddress string, filename string) (string, error) {
 	trader, err := database.GetTrader(traderAddress)
@@ -158,5 +252,5 @@ func (e *Exporter) ExportAnalysisFromDB(database *db.DB, traderAddress string, f
 		return "", fmt.Errorf("no analysis found for trader: %s", traderAddress)
 	}
 
-	return e.ExportThesisMarkdown(trader, analysis.Thesis, filename)
+	return e.ExportAnalysisMarkdown(trader, analysis, filename)
 }
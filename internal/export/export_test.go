@@ -361,3 +361,65 @@ func TestEnsureExportDir(t *testing.T) {
 	err = exporter.EnsureExportDir()
 	require.NoError(t, err)
 }
+
+func TestExportAnalysisMarkdownSummary(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "export_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	exporter := NewExporter(tmpDir)
+	trader := &db.Trader{Address: "0xtest_summary"}
+	analysis := &db.Analysis{
+		Thesis: "Narrative thesis.",
+		Model:  "claude-test",
+		Summary: &db.ThesisSummary{
+			Archetype:      "contrarian",
+			MarketFocus:    []string{"sports"},
+			RiskScore:      3,
+			TimingStyle:    "early",
+			EdgeConfidence: "high",
+			Copyability:    "low",
+			KeyRisks:       []string{"Small sample", "Illiquid markets"},
+		},
+	}
+
+	path, err := exporter.ExportAnalysisMarkdown(trader, analysis, "summary")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	text := string(content)
+	assert.Contains(t, text, "**Model:** claude-test")
+	assert.Contains(t, text, "| Archetype | contrarian |")
+	assert.Contains(t, text, "| Risk Score | 3/10 |")
+	assert.Contains(t, text, "- Illiquid markets")
+	assert.Less(t, strings.Index(text, "## Summary"), strings.Index(text, "Narrative thesis."))
+}
+
+func TestExportComparisonMarkdown(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "export_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	exporter := NewExporter(tmpDir)
+	traders := []*db.Trader{
+		{Address: "0xaaa", Username: "alice", WinRate: 0.6, ProfitLoss: 1200},
+		{Address: "0xbbb", ProfitLoss: -300},
+	}
+	comparison := &db.Comparison{ID: 7, TraderIDs: []string{"0xaaa", "0xbbb"}, Thesis: "Alice has the edge.", Model: "claude-test"}
+
+	path, err := exporter.ExportComparisonMarkdown(traders, comparison, "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "comparison_7_"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	text := string(content)
+	assert.Contains(t, text, "# Trader Comparison (2 traders)")
+	assert.Contains(t, text, "| 1 | alice (0xaaa) | 60.00% | $1200.00 |")
+	assert.Contains(t, text, "| 2 | 0xbbb | 0.00% | $-300.00 |")
+	assert.Less(t, strings.Index(text, "## Traders"), strings.Index(text, "Alice has the edge."))
+
+	_, err = exporter.ExportComparisonMarkdown(traders, &db.Comparison{}, "empty")
+	assert.Error(t, err)
+}
@@ -0,0 +1,213 @@
+// Package mcp serves the database to MCP clients (such as Claude Desktop) as a
+// set of tools, speaking JSON-RPC 2.0 over newline-delimited stdio.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"polytracker/internal/db"
+)
+
+// ProtocolVersion is the MCP revision this server implements.
+const ProtocolVersion = "2025-06-18"
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+type initializeResult struct {
+	ProtocolVersion string       `json:"protocolVersion"`
+	Capabilities    capabilities `json:"capabilities"`
+	ServerInfo      serverInfo   `json:"serverInfo"`
+	Instructions    string       `json:"instructions,omitempty"`
+}
+
+type capabilities struct {
+	Tools struct{} `json:"tools"`
+}
+
+type serverInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type listToolsResult struct {
+	Tools []Tool `json:"tools"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// CallToolResult is the result of a tools/call request.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+// Content is a block of tool output. Only text content is produced.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Server answers MCP requests from the tools registered in tools.go.
+type Server struct {
+	db    *db.DB
+	tools map[string]Tool
+	order []string
+}
+
+func NewServer(database *db.DB) *Server {
+	s := &Server{db: database, tools: make(map[string]Tool)}
+	for _, t := range s.defaultTools() {
+		s.tools[t.Name] = t
+		s.order = append(s.order, t.Name)
+	}
+	return s
+}
+
+// Serve reads one JSON-RPC message per line from r and writes responses to w
+// until r is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	enc := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// handle processes a single message, returning nil for notifications.
+func (s *Server) handle(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error"}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if len(req.ID) == 0 {
+			return nil
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}}
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if len(req.ID) == 0 {
+		// Notifications never get a response, even on failure.
+		if err != nil {
+			log.Printf("Warning: notification %s failed: %v", req.Method, err)
+		}
+		return nil
+	}
+
+	resp := &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		rerr, ok := err.(*rpcError)
+		if !ok {
+			rerr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		resp.Result = nil
+		resp.Error = rerr
+	}
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			ServerInfo:      serverInfo{Name: "polytracker", Version: "1.0.0"},
+			Instructions:    "Query Polymarket traders, trades and markets stored by polytracker, and manage its watchlist.",
+		}, nil
+	case "notifications/initialized", "notifications/cancelled", "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		tools := make([]Tool, len(s.order))
+		for i, name := range s.order {
+			tools[i] = s.tools[name]
+		}
+		return listToolsResult{Tools: tools}, nil
+	case "tools/call":
+		var params callToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+		}
+		tool, ok := s.tools[params.Name]
+		if !ok {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", params.Name)}
+		}
+		return s.callTool(ctx, tool, params.Arguments), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// callTool runs a tool and wraps its output. Tool failures are reported in the
+// result with isError set, so the model can see them and correct its arguments.
+func (s *Server) callTool(ctx context.Context, tool Tool, args json.RawMessage) CallToolResult {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	out, err := tool.handler(ctx, args)
+	if err != nil {
+		return CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+	text, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return CallToolResult{Content: []Content{{Type: "text", Text: fmt.Sprintf("failed to encode result: %v", err)}}, IsError: true}
+	}
+	return CallToolResult{Content: []Content{{Type: "text", Text: string(text)}}}
+}
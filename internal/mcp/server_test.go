@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+func setupServer(t *testing.T) (*Server, *db.DB) {
+	dbPath := "test_mcp.db"
+	t.Cleanup(func() { os.Remove(dbPath) })
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, database.SaveTrader(&db.Trader{
+			Address:    fmt.Sprintf("0x%d", i),
+			ProfitLoss: float64(i * 100),
+			WinRate:    float64(3-i) / 10,
+		}))
+	}
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain in Paris?", Status: "active"}))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m2", Question: "Will BTC hit 100k?", Status: "active"}))
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{MarketID: "m1", YesPrice: 0.35, NoPrice: 0.65, Timestamp: time.Now()}))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, database.SaveTrade(&db.Trade{
+			ID: fmt.Sprintf("t%d", i), TraderID: "0x2", MarketID: "m1", Type: "BUY", Side: "YES",
+			Price: 0.3, Size: 10, Timestamp: time.Now().Add(-time.Duration(i) * time.Hour),
+		}))
+	}
+	return NewServer(database), database
+}
+
+// run feeds the scripted messages to the server and returns its responses.
+func run(t *testing.T, s *Server, messages ...string) []rpcResponse {
+	var out bytes.Buffer
+	require.NoError(t, s.Serve(context.Background(), strings.NewReader(strings.Join(messages, "\n")+"\n"), &out))
+
+	var responses []rpcResponse
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp rpcResponse
+		require.NoError(t, dec.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func callTool(id int, name, args string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":%q,"arguments":%s}}`, id, name, args)
+}
+
+// toolText decodes a tools/call response, returning its text and error flag.
+func toolText(t *testing.T, resp rpcResponse) (string, bool) {
+	require.Nil(t, resp.Error)
+	var result CallToolResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "text", result.Content[0].Type)
+	return result.Content[0].Text, result.IsError
+}
+
+func TestHandshakeAndToolList(t *testing.T) {
+	s, _ := setupServer(t)
+
+	responses := run(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":"p","method":"ping"}`,
+	)
+	// The notification gets no response.
+	require.Len(t, responses, 3)
+
+	var init initializeResult
+	require.NoError(t, json.Unmarshal(responses[0].Result, &init))
+	assert.Equal(t, ProtocolVersion, init.ProtocolVersion)
+	assert.Equal(t, "polytracker", init.ServerInfo.Name)
+	assert.Contains(t, string(responses[0].Result), `"tools":{}`)
+
+	var list struct {
+		Tools []struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(responses[1].Result, &list))
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		assert.Equal(t, "object", tool.InputSchema["type"], tool.Name)
+		assert.NotEmpty(t, tool.InputSchema["properties"], tool.Name)
+	}
+	assert.Equal(t, []string{"list_top_traders", "get_trader_trades", "get_market", "search_markets", "add_to_watchlist"}, names)
+
+	assert.JSONEq(t, `"p"`, string(responses[2].ID))
+	assert.JSONEq(t, `{}`, string(responses[2].Result))
+}
+
+func TestTools(t *testing.T) {
+	s, database := setupServer(t)
+
+	responses := run(t, s,
+		callTool(1, "list_top_traders", `{"limit":2}`),
+		callTool(2, "list_top_traders", `{"sort_by":"win_rate","limit":1}`),
+		callTool(3, "get_trader_trades", `{"address":"0x2","limit":2}`),
+		callTool(4, "get_market", `{"market_id":"m1"}`),
+		callTool(5, "search_markets", `{"query":"btc"}`),
+		callTool(6, "add_to_watchlist", `{"address":"0x2","notes":"weather specialist"}`),
+	)
+	require.Len(t, responses, 6)
+
+	text, isErr := toolText(t, responses[0])
+	require.False(t, isErr, text)
+	var traders []db.Trader
+	require.NoError(t, json.Unmarshal([]byte(text), &traders))
+	require.Len(t, traders, 2)
+	assert.Equal(t, "0x2", traders[0].Address)
+	assert.Equal(t, "0x1", traders[1].Address)
+
+	text, _ = toolText(t, responses[1])
+	require.NoError(t, json.Unmarshal([]byte(text), &traders))
+	require.Len(t, traders, 1)
+	assert.Equal(t, "0x0", traders[0].Address)
+
+	text, isErr = toolText(t, responses[2])
+	require.False(t, isErr, text)
+	var trades struct {
+		Trader      db.Trader  `json:"trader"`
+		TotalTrades int        `json:"total_trades"`
+		Trades      []db.Trade `json:"trades"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &trades))
+	assert.Equal(t, "0x2", trades.Trader.Address)
+	assert.Equal(t, 4, trades.TotalTrades)
+	require.Len(t, trades.Trades, 2)
+	assert.Equal(t, "t0", trades.Trades[0].ID)
+
+	text, isErr = toolText(t, responses[3])
+	require.False(t, isErr, text)
+	var market struct {
+		db.Market
+		LatestSnapshot *db.MarketSnapshot `json:"latest_snapshot"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(text), &market))
+	assert.Equal(t, "Will it rain in Paris?", market.Question)
+	require.NotNil(t, market.LatestSnapshot)
+	assert.InDelta(t, 0.35, market.LatestSnapshot.YesPrice, 1e-9)
+
+	text, _ = toolText(t, responses[4])
+	var markets []db.Market
+	require.NoError(t, json.Unmarshal([]byte(text), &markets))
+	require.Len(t, markets, 1)
+	assert.Equal(t, "m2", markets[0].ID)
+
+	_, isErr = toolText(t, responses[5])
+	assert.False(t, isErr)
+	item, err := database.GetWatchlistItem("0x2")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, "weather specialist", item.Notes)
+}
+
+func TestToolErrors(t *testing.T) {
+	s, _ := setupServer(t)
+
+	responses := run(t, s,
+		callTool(1, "get_trader_trades", `{"address":"0xnope"}`),
+		callTool(2, "get_market", `{}`),
+		callTool(3, "list_top_traders", `{"limit":1000}`),
+		callTool(4, "search_markets", `{"query":"rain","colour":"red"}`),
+		callTool(5, "delete_everything", `{}`),
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`{not json`,
+	)
+	require.Len(t, responses, 7)
+
+	for i, want := range []string{"not found", "market_id is required", "limit must be between", "unknown field"} {
+		text, isErr := toolText(t, responses[i])
+		assert.True(t, isErr, text)
+		assert.Contains(t, text, want)
+	}
+
+	require.NotNil(t, responses[4].Error)
+	assert.Equal(t, codeInvalidParams, responses[4].Error.Code)
+	require.NotNil(t, responses[5].Error)
+	assert.Equal(t, codeMethodNotFound, responses[5].Error.Code)
+	require.NotNil(t, responses[6].Error)
+	assert.Equal(t, codeParseError, responses[6].Error.Code)
+	assert.JSONEq(t, `null`, string(responses[6].ID))
+}
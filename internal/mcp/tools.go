@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+)
+
+const maxResults = 200
+
+// Tool is an MCP tool: a name, a description for the model, a JSON Schema for
+// its arguments and the handler that runs it.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"inputSchema"`
+
+	handler func(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// Schema is the subset of JSON Schema used to describe tool arguments.
+type Schema struct {
+	Type                 string              `json:"type"`
+	Properties           map[string]Property `json:"properties"`
+	Required             []string            `json:"required,omitempty"`
+	AdditionalProperties bool                `json:"additionalProperties"`
+}
+
+// Property describes one tool argument.
+type Property struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Enum        []string    `json:"enum,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Minimum     *int        `json:"minimum,omitempty"`
+	Maximum     *int        `json:"maximum,omitempty"`
+}
+
+func intRange(lo, hi int) (*int, *int) {
+	return &lo, &hi
+}
+
+func limitProperty(def int) Property {
+	lo, hi := intRange(1, maxResults)
+	return Property{Type: "integer", Description: "Maximum number of results to return", Default: def, Minimum: lo, Maximum: hi}
+}
+
+// decodeArgs unmarshals tool arguments into v, rejecting unknown fields.
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// clampLimit applies a default to an unset limit and rejects out-of-range values.
+func clampLimit(limit *int, def int) (int, error) {
+	if limit == nil {
+		return def, nil
+	}
+	if *limit < 1 || *limit > maxResults {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxResults)
+	}
+	return *limit, nil
+}
+
+var sortFields = map[string]db.SortField{
+	"profit_loss": db.SortByProfitLoss,
+	"win_rate":    db.SortByWinRate,
+	"roi":         db.SortByROI,
+	"volume":      db.SortByVolume,
+}
+
+func (s *Server) defaultTools() []Tool {
+	return []Tool{
+		{
+			Name:        "list_top_traders",
+			Description: "List the best-performing Polymarket traders in the local database, ranked by a metric. Optionally restrict to a trader type or market category.",
+			InputSchema: Schema{
+				Type: "object",
+				Properties: map[string]Property{
+					"sort_by":     {Type: "string", Description: "Metric to rank by", Enum: []string{"profit_loss", "win_rate", "roi", "volume"}, Default: "profit_loss"},
+					"limit":       limitProperty(10),
+					"trader_type": {Type: "string", Description: "Only include traders with this classifier label", Enum: []string{db.TraderTypeDirectional, db.TraderTypeMarketMaker, db.TraderTypeBot, db.TraderTypeUnknown}},
+					"category":    {Type: "string", Description: "Rank traders by their performance within this market category only"},
+				},
+			},
+			handler: s.listTopTraders,
+		},
+		{
+			Name:        "get_trader_trades",
+			Description: "Get a trader's stored stats and most recent trades, newest first.",
+			InputSchema: Schema{
+				Type: "object",
+				Properties: map[string]Property{
+					"address": {Type: "string", Description: "Trader wallet address (0x...)"},
+					"limit":   limitProperty(50),
+				},
+				Required: []string{"address"},
+			},
+			handler: s.getTraderTrades,
+		},
+		{
+			Name:        "get_market",
+			Description: "Get a market by ID along with its latest price snapshot.",
+			InputSchema: Schema{
+				Type: "object",
+				Properties: map[string]Property{
+					"market_id": {Type: "string", Description: "Polymarket market (condition) ID"},
+				},
+				Required: []string{"market_id"},
+			},
+			handler: s.getMarket,
+		},
+		{
+			Name:        "search_markets",
+			Description: "Search stored markets whose question contains the query text.",
+			InputSchema: Schema{
+				Type: "object",
+				Properties: map[string]Property{
+					"query":  {Type: "string", Description: "Text to search for, case-insensitive"},
+					"status": {Type: "string", Description: "Only include markets with this status", Enum: []string{"active", "closed", "resolved"}},
+					"limit":  limitProperty(20),
+				},
+				Required: []string{"query"},
+			},
+			handler: s.searchMarkets,
+		},
+		{
+			Name:        "add_to_watchlist",
+			Description: "Add a trader to the polytracker watchlist, or update the notes of one already on it.",
+			InputSchema: Schema{
+				Type: "object",
+				Properties: map[string]Property{
+					"address": {Type: "string", Description: "Trader wallet address (0x...)"},
+					"notes":   {Type: "string", Description: "Why the trader is worth watching"},
+				},
+				Required: []string{"address"},
+			},
+			handler: s.addToWatchlist,
+		},
+	}
+}
+
+func (s *Server) listTopTraders(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		SortBy     string `json:"sort_by"`
+		Limit      *int   `json:"limit"`
+		TraderType string `json:"trader_type"`
+		Category   string `json:"category"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	limit, err := clampLimit(args.Limit, 10)
+	if err != nil {
+		return nil, err
+	}
+	opts := db.ListTradersOptions{
+		SortBy:     db.SortByProfitLoss,
+		Order:      db.SortDesc,
+		Limit:      limit,
+		TraderType: args.TraderType,
+		Category:   args.Category,
+	}
+	if args.SortBy != "" {
+		field, ok := sortFields[args.SortBy]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort_by %q", args.SortBy)
+		}
+		opts.SortBy = field
+	}
+	traders, err := s.db.ListTradersWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(traders), nil
+}
+
+func (s *Server) getTraderTrades(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Address string `json:"address"`
+		Limit   *int   `json:"limit"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Address) == "" {
+		return nil, errors.New("address is required")
+	}
+	limit, err := clampLimit(args.Limit, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	trader, err := s.db.GetTrader(args.Address)
+	if err != nil {
+		return nil, err
+	}
+	if trader == nil {
+		return nil, fmt.Errorf("trader %s not found", args.Address)
+	}
+	trades, err := s.db.GetTradesByTrader(trader.Address)
+	if err != nil {
+		return nil, err
+	}
+	total := len(trades)
+	if len(trades) > limit {
+		trades = trades[:limit]
+	}
+	return struct {
+		Trader      *db.Trader `json:"trader"`
+		TotalTrades int        `json:"total_trades"`
+		Trades      []db.Trade `json:"trades"`
+	}{trader, total, nonNil(trades)}, nil
+}
+
+func (s *Server) getMarket(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		MarketID string `json:"market_id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.MarketID) == "" {
+		return nil, errors.New("market_id is required")
+	}
+
+	market, err := s.db.GetMarket(args.MarketID)
+	if err != nil {
+		return nil, err
+	}
+	if market == nil {
+		return nil, fmt.Errorf("market %s not found", args.MarketID)
+	}
+	snapshot, err := s.db.GetLatestMarketSnapshot(market.ID)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		*db.Market
+		LatestSnapshot *db.MarketSnapshot `json:"latest_snapshot,omitempty"`
+	}{market, snapshot}, nil
+}
+
+func (s *Server) searchMarkets(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Query  string `json:"query"`
+		Status string `json:"status"`
+		Limit  *int   `json:"limit"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+	limit, err := clampLimit(args.Limit, 20)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := s.db.ListMarkets(db.ListMarketsOptions{Query: args.Query, Status: args.Status, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return nonNil(markets), nil
+}
+
+func (s *Server) addToWatchlist(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Address string `json:"address"`
+		Notes   string `json:"notes"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Address) == "" {
+		return nil, errors.New("address is required")
+	}
+	if err := s.db.AddToWatchlist(args.Address, args.Notes); err != nil {
+		return nil, err
+	}
+	return s.db.GetWatchlistItem(args.Address)
+}
+
+// nonNil makes empty results encode as [] rather than null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,182 @@
+// Package metrics holds the Prometheus collectors that instrument polytracker
+// and the handler that serves them on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "polytracker"
+
+// Registry holds every polytracker collector plus the Go runtime and process
+// collectors. It is separate from the global default registry so tests and
+// embedders see only what polytracker registers.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// PolymarketRequests counts Polymarket API requests by API (gamma, clob),
+	// endpoint and HTTP status ("error" when no response was received).
+	PolymarketRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "polymarket",
+		Name:      "requests_total",
+		Help:      "Polymarket API requests by API, endpoint and status.",
+	}, []string{"api", "endpoint", "status"})
+
+	PolymarketRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "polymarket",
+		Name:      "request_duration_seconds",
+		Help:      "Polymarket API request latency by API and endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api", "endpoint"})
+
+	// RateLimitWait observes how long each request waited on the client-side
+	// rate limiter before being sent.
+	RateLimitWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "polymarket",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time requests spent waiting on the client-side rate limiter.",
+		Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5},
+	})
+
+	// MarketsProcessed counts markets handled by the scanner or fetcher.
+	MarketsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "markets_processed_total",
+		Help:      "Markets scanned, fetched or snapshotted, by component.",
+	}, []string{"component"})
+
+	// TradesProcessed counts trades read by the scanner or saved by the fetcher.
+	TradesProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "trades_processed_total",
+		Help:      "Trades processed, by component.",
+	}, []string{"component"})
+
+	// Errors counts failures the scanner or fetcher logged and skipped past.
+	Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors_total",
+		Help:      "Errors encountered, by component.",
+	}, []string{"component"})
+
+	// DBWriteDuration observes database write latency by statement and table,
+	// e.g. operation="insert" table="trades".
+	DBWriteDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "write_duration_seconds",
+		Help:      "Database write latency by operation and table.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1},
+	}, []string{"operation", "table"})
+
+	ClaudeRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "claude",
+		Name:      "requests_total",
+		Help:      "Claude API requests by outcome (ok, truncated, error).",
+	}, []string{"outcome"})
+
+	// ClaudeTokens counts tokens billed by Claude, by model and direction
+	// (input, output).
+	ClaudeTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "claude",
+		Name:      "tokens_total",
+		Help:      "Claude tokens used by model and type.",
+	}, []string{"model", "type"})
+)
+
+// Components used as the "component" label.
+const (
+	ComponentScanner = "scanner"
+	ComponentFetcher = "fetcher"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		PolymarketRequests,
+		PolymarketRequestDuration,
+		RateLimitWait,
+		MarketsProcessed,
+		TradesProcessed,
+		Errors,
+		DBWriteDuration,
+		ClaudeRequests,
+		ClaudeTokens,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObservePolymarketRequest records one Polymarket API request. A status of 0
+// means the request failed before a response arrived.
+func ObservePolymarketRequest(api, endpoint string, status int, duration time.Duration) {
+	label := "error"
+	if status > 0 {
+		label = strconv.Itoa(status)
+	}
+	PolymarketRequests.WithLabelValues(api, endpoint, label).Inc()
+	PolymarketRequestDuration.WithLabelValues(api, endpoint).Observe(duration.Seconds())
+}
+
+// ObserveDBWrite records the latency of a write statement, labelled by its
+// leading keyword and target table.
+func ObserveDBWrite(query string, duration time.Duration) {
+	op, table := statementTarget(query)
+	DBWriteDuration.WithLabelValues(op, table).Observe(duration.Seconds())
+}
+
+// statementTarget extracts the operation and table from a write statement such
+// as "INSERT INTO trades ...", "UPDATE markets SET ..." or "DELETE FROM alerts".
+func statementTarget(query string) (string, string) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown", "unknown"
+	}
+	op := strings.ToLower(fields[0])
+	rest := fields[1:]
+	// Skip the keywords between the operation and the table name.
+	for len(rest) > 0 {
+		switch strings.ToUpper(rest[0]) {
+		case "INTO", "FROM", "OR", "REPLACE", "IGNORE", "ABORT", "ROLLBACK", "FAIL":
+			rest = rest[1:]
+			continue
+		}
+		break
+	}
+	if len(rest) == 0 {
+		return op, "unknown"
+	}
+	table := strings.ToLower(strings.TrimFunc(rest[0], func(r rune) bool {
+		return r == '(' || r == '"' || r == '`'
+	}))
+	if i := strings.IndexByte(table, '('); i >= 0 {
+		table = table[:i]
+	}
+	return op, table
+}
+
+// ObserveClaudeUsage records the outcome and token usage of a Claude request.
+func ObserveClaudeUsage(outcome, model string, inputTokens, outputTokens int64) {
+	ClaudeRequests.WithLabelValues(outcome).Inc()
+	if model == "" {
+		return
+	}
+	ClaudeTokens.WithLabelValues(model, "input").Add(float64(inputTokens))
+	ClaudeTokens.WithLabelValues(model, "output").Add(float64(outputTokens))
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStatementTarget(t *testing.T) {
+	tests := []struct {
+		query, op, table string
+	}{
+		{"INSERT INTO trades (id) VALUES (?)", "insert", "trades"},
+		{"\n\t\tINSERT OR REPLACE INTO rank_history (trader_id) VALUES (?)", "insert", "rank_history"},
+		{"INSERT OR IGNORE INTO paper_fills(id) VALUES (?)", "insert", "paper_fills"},
+		{"UPDATE alerts SET read = 1 WHERE read = 0", "update", "alerts"},
+		{"DELETE FROM job_locks WHERE job = ?", "delete", "job_locks"},
+		{"", "unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		op, table := statementTarget(tt.query)
+		if op != tt.op || table != tt.table {
+			t.Errorf("statementTarget(%q) = %q, %q; want %q, %q", tt.query, op, table, tt.op, tt.table)
+		}
+	}
+}
+
+func TestObserveClaudeUsage(t *testing.T) {
+	input := ClaudeTokens.WithLabelValues("test-model", "input")
+	output := ClaudeTokens.WithLabelValues("test-model", "output")
+	before := testutil.ToFloat64(input)
+
+	ObserveClaudeUsage("ok", "test-model", 1200, 300)
+
+	if got := testutil.ToFloat64(input) - before; got != 1200 {
+		t.Errorf("Expected 1200 input tokens, got %v", got)
+	}
+	if got := testutil.ToFloat64(output); got < 300 {
+		t.Errorf("Expected at least 300 output tokens, got %v", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ObservePolymarketRequest("gamma", "/markets", 200, 50*time.Millisecond)
+	ObserveDBWrite("INSERT INTO traders (address) VALUES (?)", time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`polytracker_polymarket_requests_total{api="gamma",endpoint="/markets",status="200"}`,
+		`polytracker_polymarket_request_duration_seconds_bucket{api="gamma",endpoint="/markets"`,
+		`polytracker_db_write_duration_seconds_count{operation="insert",table="traders"}`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected /metrics output to contain %s", want)
+		}
+	}
+}
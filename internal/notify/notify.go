@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+// DefaultTemplate renders an alert as a single chat line.
+const DefaultTemplate = "[{{.Rule}}] {{.Message}}"
+
+// Notifier delivers fired alerts somewhere outside polytracker.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert db.Alert) error
+}
+
+// Dispatcher fans alerts out to every configured notifier.
+type Dispatcher struct {
+	notifiers []Notifier
+}
+
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{notifiers: notifiers}
+}
+
+// Len is the number of configured notifiers.
+func (d *Dispatcher) Len() int {
+	return len(d.notifiers)
+}
+
+// Dispatch sends each alert to every notifier. A failing notifier does not stop
+// delivery to the others; all failures are returned together.
+func (d *Dispatcher) Dispatch(ctx context.Context, alerts []db.Alert) error {
+	var errs []error
+	for _, n := range d.notifiers {
+		for _, a := range alerts {
+			if err := n.Notify(ctx, a); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// templateData is what message templates can reference.
+type templateData struct {
+	Rule      string
+	TraderID  string
+	MarketID  string
+	Message   string
+	CreatedAt time.Time
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template for sink %q: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func render(tmpl *template.Template, a db.Alert) (string, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, templateData{
+		Rule:      a.Rule,
+		TraderID:  a.TraderID,
+		MarketID:  a.MarketID,
+		Message:   a.Message,
+		CreatedAt: a.CreatedAt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render alert: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+// Webhook payload formats.
+const (
+	FormatGeneric  = "generic"
+	FormatSlack    = "slack"
+	FormatDiscord  = "discord"
+	FormatTelegram = "telegram"
+)
+
+const (
+	DefaultRetries       = 3
+	DefaultRatePerMinute = 30
+	DefaultTimeout       = 10 * time.Second
+)
+
+// SinkConfig describes one webhook destination.
+type SinkConfig struct {
+	Name   string
+	Format string // generic, slack, discord or telegram
+	// URL receives the POST. For Telegram it is the bot's sendMessage endpoint,
+	// https://api.telegram.org/bot<token>/sendMessage.
+	URL      string
+	ChatID   string // Telegram only
+	Template string // text/template over Rule, TraderID, MarketID, Message, CreatedAt
+	// RatePerMinute caps deliveries to this sink; excess alerts wait their turn.
+	RatePerMinute int
+	// Retries is how often a failed delivery is retried; zero selects
+	// DefaultRetries and a negative value disables retrying.
+	Retries int
+	Timeout time.Duration
+}
+
+// WebhookSink POSTs alerts as JSON in the payload shape its format expects.
+type WebhookSink struct {
+	name     string
+	format   string
+	url      string
+	chatID   string
+	template *template.Template
+	resty    *resty.Client
+	limiter  *rate.Limiter
+}
+
+// genericPayload is sent to FormatGeneric sinks: the alert plus the rendered text.
+type genericPayload struct {
+	Rule      string    `json:"rule"`
+	TraderID  string    `json:"trader_id"`
+	MarketID  string    `json:"market_id"`
+	Message   string    `json:"message"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhookSink(cfg SinkConfig) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("sink %q has no url", cfg.Name)
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatGeneric
+	}
+	switch cfg.Format {
+	case FormatGeneric, FormatSlack, FormatDiscord:
+	case FormatTelegram:
+		if cfg.ChatID == "" {
+			return nil, fmt.Errorf("telegram sink %q needs a chat_id", cfg.Name)
+		}
+	default:
+		return nil, fmt.Errorf("sink %q has unknown format %q", cfg.Name, cfg.Format)
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Format
+	}
+	if cfg.RatePerMinute <= 0 {
+		cfg.RatePerMinute = DefaultRatePerMinute
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	} else if cfg.Retries == 0 {
+		cfg.Retries = DefaultRetries
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	tmpl, err := parseTemplate(cfg.Name, cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	client := resty.New().
+		SetTimeout(cfg.Timeout).
+		SetRetryCount(cfg.Retries).
+		SetRetryWaitTime(500 * time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Second).
+		AddRetryCondition(func(r *resty.Response, err error) bool {
+			return err != nil || r.StatusCode() == http.StatusTooManyRequests || r.StatusCode() >= 500
+		})
+
+	return &WebhookSink{
+		name:     cfg.Name,
+		format:   cfg.Format,
+		url:      cfg.URL,
+		chatID:   cfg.ChatID,
+		template: tmpl,
+		resty:    client,
+		limiter:  rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.RatePerMinute)), 1),
+	}, nil
+}
+
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+// Notify renders the alert, waits for the sink's rate limit and POSTs it,
+// retrying on network errors, 429s and 5xx responses.
+func (s *WebhookSink) Notify(ctx context.Context, alert db.Alert) error {
+	text, err := render(s.template, alert)
+	if err != nil {
+		return err
+	}
+	if err := s.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	resp, err := s.resty.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(s.payload(alert, text)).
+		Post(s.url)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("webhook error: %s", resp.Status())
+	}
+	return nil
+}
+
+func (s *WebhookSink) payload(alert db.Alert, text string) interface{} {
+	switch s.format {
+	case FormatSlack:
+		return map[string]string{"text": text}
+	case FormatDiscord:
+		return map[string]string{"content": text}
+	case FormatTelegram:
+		return map[string]string{"chat_id": s.chatID, "text": text}
+	default:
+		return genericPayload{
+			Rule:      alert.Rule,
+			TraderID:  alert.TraderID,
+			MarketID:  alert.MarketID,
+			Message:   alert.Message,
+			Text:      text,
+			CreatedAt: alert.CreatedAt,
+		}
+	}
+}
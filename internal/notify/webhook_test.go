@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver records every JSON body posted to it.
+type receiver struct {
+	mu     sync.Mutex
+	bodies []map[string]interface{}
+}
+
+func (r *receiver) handler(w http.ResponseWriter, req *http.Request) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	r.mu.Unlock()
+}
+
+var testAlert = db.Alert{
+	Rule:     "large_position",
+	TraderID: "0xabc",
+	MarketID: "m1",
+	Message:  "0xabc bought $2000.00 of YES in m1 @ 0.500",
+}
+
+func TestWebhookSink_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		chatID string
+		check  func(t *testing.T, body map[string]interface{})
+	}{
+		{FormatGeneric, "", func(t *testing.T, body map[string]interface{}) {
+			assert.Equal(t, "large_position", body["rule"])
+			assert.Equal(t, "0xabc", body["trader_id"])
+			assert.Equal(t, "m1", body["market_id"])
+			assert.Equal(t, "[large_position] "+testAlert.Message, body["text"])
+		}},
+		{FormatSlack, "", func(t *testing.T, body map[string]interface{}) {
+			assert.Equal(t, "[large_position] "+testAlert.Message, body["text"])
+		}},
+		{FormatDiscord, "", func(t *testing.T, body map[string]interface{}) {
+			assert.Equal(t, "[large_position] "+testAlert.Message, body["content"])
+		}},
+		{FormatTelegram, "42", func(t *testing.T, body map[string]interface{}) {
+			assert.Equal(t, "42", body["chat_id"])
+			assert.Equal(t, "[large_position] "+testAlert.Message, body["text"])
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rec := &receiver{}
+			server := httptest.NewServer(http.HandlerFunc(rec.handler))
+			defer server.Close()
+
+			sink, err := NewWebhookSink(SinkConfig{Format: tt.format, URL: server.URL, ChatID: tt.chatID})
+			require.NoError(t, err)
+			require.NoError(t, sink.Notify(context.Background(), testAlert))
+
+			require.Len(t, rec.bodies, 1)
+			tt.check(t, rec.bodies[0])
+		})
+	}
+}
+
+func TestWebhookSink_Template(t *testing.T) {
+	rec := &receiver{}
+	server := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(SinkConfig{
+		Format:   FormatSlack,
+		URL:      server.URL,
+		Template: ":rotating_light: {{.TraderID}} in {{.MarketID}}: {{.Message}}",
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Notify(context.Background(), testAlert))
+
+	require.Len(t, rec.bodies, 1)
+	assert.Equal(t, ":rotating_light: 0xabc in m1: "+testAlert.Message, rec.bodies[0]["text"])
+
+	_, err = NewWebhookSink(SinkConfig{URL: server.URL, Template: "{{.Missing"})
+	assert.Error(t, err)
+}
+
+func TestWebhookSink_Retries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(SinkConfig{URL: server.URL, Retries: 2})
+	require.NoError(t, err)
+	sink.resty.SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+
+	require.NoError(t, sink.Notify(context.Background(), testAlert))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// Client errors are not retried.
+	atomic.StoreInt32(&calls, 0)
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer bad.Close()
+	sink, err = NewWebhookSink(SinkConfig{URL: bad.URL})
+	require.NoError(t, err)
+	assert.Error(t, sink.Notify(context.Background(), testAlert))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookSink_RateLimit(t *testing.T) {
+	rec := &receiver{}
+	server := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(SinkConfig{URL: server.URL, RatePerMinute: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Notify(context.Background(), testAlert))
+
+	// The second alert has to wait a minute for its turn.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, sink.Notify(ctx, testAlert))
+	assert.Len(t, rec.bodies, 1)
+}
+
+func TestDispatcher_ContinuesPastFailures(t *testing.T) {
+	rec := &receiver{}
+	good := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer good.Close()
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer bad.Close()
+
+	failing, err := NewWebhookSink(SinkConfig{Name: "broken", URL: bad.URL, RatePerMinute: 6000})
+	require.NoError(t, err)
+	working, err := NewWebhookSink(SinkConfig{Name: "ok", URL: good.URL, RatePerMinute: 6000})
+	require.NoError(t, err)
+
+	d := NewDispatcher(failing, working)
+	err = d.Dispatch(context.Background(), []db.Alert{testAlert, testAlert})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+	assert.Len(t, rec.bodies, 2)
+}
+
+func TestNewWebhookSink_Validation(t *testing.T) {
+	_, err := NewWebhookSink(SinkConfig{Format: FormatSlack})
+	assert.Error(t, err, "missing url")
+	_, err = NewWebhookSink(SinkConfig{Format: "pager", URL: "http://example.com"})
+	assert.Error(t, err, "unknown format")
+	_, err = NewWebhookSink(SinkConfig{Format: FormatTelegram, URL: "http://example.com"})
+	assert.Error(t, err, "telegram without chat id")
+}
@@ -0,0 +1,296 @@
+package paper
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"polytracker/internal/analytics"
+	"polytracker/internal/db"
+)
+
+const (
+	DefaultStartingCash = 10000.0
+	DefaultAmount       = 100.0
+
+	// minNotional is the smallest order worth mirroring.
+	minNotional = 1.0
+	// dust is the share count below which a position is treated as closed.
+	dust = 1e-9
+)
+
+// Portfolio mirrors watchlisted traders' trades into a simulated account.
+type Portfolio struct {
+	db                *db.DB
+	StartingCash      float64
+	DefaultAllocation db.PaperAllocation // applies to watched traders without their own rule
+}
+
+func NewPortfolio(database *db.DB) *Portfolio {
+	return &Portfolio{
+		db:           database,
+		StartingCash: DefaultStartingCash,
+		DefaultAllocation: db.PaperAllocation{
+			Mode:   db.AllocationFixed,
+			Amount: DefaultAmount,
+		},
+	}
+}
+
+// Holding is an open position marked to market.
+type Holding struct {
+	db.PaperPosition
+	Question      string
+	Mark          float64
+	Value         float64
+	UnrealizedPnL float64
+}
+
+// Valuation is the portfolio marked to the latest market snapshots.
+type Valuation struct {
+	StartingCash  float64
+	Cash          float64
+	MarketValue   float64
+	Equity        float64
+	RealizedPnL   float64
+	UnrealizedPnL float64
+	TotalPnL      float64
+	Holdings      []Holding
+}
+
+// Sync mirrors every trade by a watched trader made since they were added to the
+// watchlist and not yet seen, oldest first. It returns the number of trades copied.
+// Trades that cannot be copied (no cash, position cap reached, nothing to sell)
+// are still recorded as seen so later syncs do not copy them at a stale price.
+func (p *Portfolio) Sync() (int, error) {
+	account, err := p.account()
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := p.db.ListWatchlist()
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		trade   db.Trade
+		history []db.Trade
+	}
+	var queue []pending
+	for _, item := range items {
+		trades, err := p.db.GetTradesByTrader(item.TraderID)
+		if err != nil {
+			return 0, err
+		}
+		for _, t := range trades {
+			if t.Timestamp.Before(item.CreatedAt) {
+				continue
+			}
+			seen, err := p.db.HasPaperFill(t.ID)
+			if err != nil {
+				return 0, err
+			}
+			if !seen {
+				queue = append(queue, pending{trade: t, history: trades})
+			}
+		}
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].trade.Timestamp.Before(queue[j].trade.Timestamp)
+	})
+
+	cash := account.Cash
+	copied := 0
+	for _, q := range queue {
+		fill, pos, newCash, err := p.mirror(q.trade, q.history, cash)
+		if err != nil {
+			return copied, err
+		}
+		recorded, err := p.db.RecordPaperFill(fill, pos, newCash)
+		if err != nil {
+			return copied, err
+		}
+		if recorded {
+			cash = newCash
+			if pos != nil {
+				copied++
+			}
+		}
+	}
+	return copied, nil
+}
+
+// account returns the paper account, funding it with StartingCash on first use.
+func (p *Portfolio) account() (*db.PaperAccount, error) {
+	account, err := p.db.GetPaperAccount()
+	if err != nil || account != nil {
+		return account, err
+	}
+	if err := p.db.ResetPaperPortfolio(p.StartingCash); err != nil {
+		return nil, err
+	}
+	return p.db.GetPaperAccount()
+}
+
+// mirror computes the copy of t given the trader's full history and the current
+// cash. A nil position means the trade is recorded as seen but not copied.
+func (p *Portfolio) mirror(t db.Trade, history []db.Trade, cash float64) (*db.PaperFill, *db.PaperPosition, float64, error) {
+	side := normalizeSide(t.Side)
+	fill := &db.PaperFill{
+		SourceTradeID: t.ID,
+		TraderID:      t.TraderID,
+		MarketID:      t.MarketID,
+		Side:          side,
+		Type:          strings.ToUpper(t.Type),
+		Price:         t.Price,
+		Timestamp:     t.Timestamp,
+	}
+	if t.Price <= 0 {
+		return fill, nil, cash, nil
+	}
+
+	pos, err := p.db.GetPaperPosition(t.MarketID, side)
+	if err != nil {
+		return nil, nil, cash, err
+	}
+	if pos == nil {
+		pos = &db.PaperPosition{MarketID: t.MarketID, Side: side}
+	}
+	pos.UpdatedAt = t.Timestamp
+
+	if analytics.IsBuy(t) {
+		alloc, err := p.allocation(t.TraderID)
+		if err != nil {
+			return nil, nil, cash, err
+		}
+		notional := alloc.Amount
+		if alloc.Mode == db.AllocationProportional {
+			notional = alloc.Amount * t.Price * t.Size
+		}
+		if alloc.MaxPosition > 0 {
+			notional = math.Min(notional, alloc.MaxPosition-pos.CostBasis)
+		}
+		notional = math.Min(notional, cash)
+		if notional < minNotional {
+			return fill, nil, cash, nil
+		}
+
+		fill.Shares = notional / t.Price
+		fill.Notional = notional
+		pos.Shares += fill.Shares
+		pos.CostBasis += notional
+		return fill, pos, cash - notional, nil
+	}
+
+	if pos.Shares <= dust {
+		return fill, nil, cash, nil
+	}
+	// Sell the same fraction of our position as the trader sold of theirs; if their
+	// holding is unknown, follow them out entirely.
+	frac := 1.0
+	if held := holdingBefore(history, t); held > 0 {
+		frac = math.Min(1, t.Size/held)
+	}
+	avgCost := pos.CostBasis / pos.Shares
+
+	fill.Shares = pos.Shares * frac
+	fill.Notional = fill.Shares * t.Price
+	pos.RealizedPnL += fill.Shares * (t.Price - avgCost)
+	pos.CostBasis -= fill.Shares * avgCost
+	pos.Shares -= fill.Shares
+	if pos.Shares <= dust {
+		pos.Shares, pos.CostBasis = 0, 0
+	}
+	return fill, pos, cash + fill.Notional, nil
+}
+
+func (p *Portfolio) allocation(traderID string) (db.PaperAllocation, error) {
+	alloc, err := p.db.GetPaperAllocation(traderID)
+	if err != nil {
+		return db.PaperAllocation{}, err
+	}
+	if alloc == nil {
+		return p.DefaultAllocation, nil
+	}
+	return *alloc, nil
+}
+
+// Value marks every open position to its latest snapshot, falling back to the
+// average entry price for markets without one. It only reads: before the
+// account is first funded it reports an empty portfolio holding StartingCash.
+func (p *Portfolio) Value() (*Valuation, error) {
+	account, err := p.db.GetPaperAccount()
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return &Valuation{StartingCash: p.StartingCash, Cash: p.StartingCash, Equity: p.StartingCash}, nil
+	}
+	positions, err := p.db.ListPaperPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Valuation{
+		StartingCash: account.StartingCash,
+		Cash:         account.Cash,
+	}
+	for _, pos := range positions {
+		v.RealizedPnL += pos.RealizedPnL
+		if pos.Shares <= dust {
+			continue
+		}
+
+		h := Holding{PaperPosition: pos, Mark: pos.CostBasis / pos.Shares}
+		snapshot, err := p.db.GetLatestMarketSnapshot(pos.MarketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark %s: %w", pos.MarketID, err)
+		}
+		if snapshot != nil {
+			h.Mark = snapshot.YesPrice
+			if pos.Side == "NO" {
+				h.Mark = snapshot.NoPrice
+			}
+		}
+		if market, err := p.db.GetMarket(pos.MarketID); err == nil && market != nil {
+			h.Question = market.Question
+		}
+		h.Value = h.Mark * pos.Shares
+		h.UnrealizedPnL = h.Value - pos.CostBasis
+
+		v.MarketValue += h.Value
+		v.UnrealizedPnL += h.UnrealizedPnL
+		v.Holdings = append(v.Holdings, h)
+	}
+
+	v.Equity = v.Cash + v.MarketValue
+	v.TotalPnL = v.Equity - v.StartingCash
+	return v, nil
+}
+
+// holdingBefore is the trader's net share count in t's market outcome from the
+// trades they made before t.
+func holdingBefore(history []db.Trade, t db.Trade) float64 {
+	side := normalizeSide(t.Side)
+	held := 0.0
+	for _, h := range history {
+		if h.ID == t.ID || h.MarketID != t.MarketID || normalizeSide(h.Side) != side || h.Timestamp.After(t.Timestamp) {
+			continue
+		}
+		if analytics.IsBuy(h) {
+			held += h.Size
+		} else {
+			held -= h.Size
+		}
+	}
+	return held
+}
+
+func normalizeSide(side string) string {
+	if strings.EqualFold(side, "no") {
+		return "NO"
+	}
+	return "YES"
+}
@@ -0,0 +1,137 @@
+package paper
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortfolio_Sync(t *testing.T) {
+	dbPath := "test_paper.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xa", LastScanned: time.Now()}))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain?"}))
+
+	// Trades from before the trader was watched are history, not signals.
+	past := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "old", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.30, Size: 100, Timestamp: past,
+	}))
+	require.NoError(t, database.AddToWatchlist("0xa", ""))
+
+	now := time.Now().Add(time.Minute)
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t1", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.40, Size: 100, Timestamp: now,
+	}))
+
+	p := NewPortfolio(database)
+	p.StartingCash = 1000
+
+	// Valuing before the first sync reports the starting cash without funding
+	// the account.
+	v, err := p.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, v.Equity)
+	assert.Empty(t, v.Holdings)
+	account, err := database.GetPaperAccount()
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	copied, err := p.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, 1, copied)
+
+	// A second sync is a no-op.
+	copied, err = p.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, 0, copied)
+
+	pos, err := database.GetPaperPosition("m1", "YES")
+	require.NoError(t, err)
+	require.NotNil(t, pos)
+	assert.InDelta(t, 250.0, pos.Shares, 0.001)
+	assert.InDelta(t, 100.0, pos.CostBasis, 0.001)
+
+	// The trader sells half of the 200 shares they held; we follow with half of ours.
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t2", TraderID: "0xa", MarketID: "m1", Type: "SELL", Side: "YES", Price: 0.60, Size: 100, Timestamp: now.Add(time.Hour),
+	}))
+	copied, err = p.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, 1, copied)
+
+	pos, err = database.GetPaperPosition("m1", "YES")
+	require.NoError(t, err)
+	assert.InDelta(t, 125.0, pos.Shares, 0.001)
+	assert.InDelta(t, 25.0, pos.RealizedPnL, 0.001)
+
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{
+		MarketID: "m1", YesPrice: 0.80, NoPrice: 0.20, Timestamp: now.Add(2 * time.Hour),
+	}))
+
+	v, err = p.Value()
+	require.NoError(t, err)
+	assert.InDelta(t, 975.0, v.Cash, 0.001)
+	assert.InDelta(t, 100.0, v.MarketValue, 0.001)
+	assert.InDelta(t, 75.0, v.TotalPnL, 0.001)
+	assert.InDelta(t, 50.0, v.UnrealizedPnL, 0.001)
+	require.Len(t, v.Holdings, 1)
+	assert.Equal(t, "Will it rain?", v.Holdings[0].Question)
+
+	fills, err := database.ListPaperFills(10)
+	require.NoError(t, err)
+	assert.Len(t, fills, 2)
+}
+
+func TestPortfolio_AllocationRules(t *testing.T) {
+	dbPath := "test_paper_alloc.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	defer database.Close()
+
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xa", LastScanned: time.Now()}))
+	require.NoError(t, database.AddToWatchlist("0xa", ""))
+	require.NoError(t, database.SavePaperAllocation(&db.PaperAllocation{
+		TraderID: "0xa", Mode: db.AllocationProportional, Amount: 0.5, MaxPosition: 150,
+	}))
+
+	now := time.Now().Add(time.Minute)
+	for i, id := range []string{"t1", "t2", "t3"} {
+		require.NoError(t, database.SaveTrade(&db.Trade{
+			ID: id, TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.5, Size: 200,
+			Timestamp: now.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	p := NewPortfolio(database)
+	copied, err := p.Sync()
+	require.NoError(t, err)
+	// 0.5 * $100 notional is $50 per buy, so three buys reach the $150 cap.
+	assert.Equal(t, 3, copied)
+
+	pos, err := database.GetPaperPosition("m1", "NO")
+	require.NoError(t, err)
+	require.NotNil(t, pos)
+	assert.InDelta(t, 150.0, pos.CostBasis, 0.001)
+
+	require.NoError(t, database.SaveTrade(&db.Trade{
+		ID: "t4", TraderID: "0xa", MarketID: "m1", Type: "BUY", Side: "NO", Price: 0.5, Size: 200, Timestamp: now.Add(time.Hour),
+	}))
+	copied, err = p.Sync()
+	require.NoError(t, err)
+	assert.Equal(t, 0, copied)
+
+	seen, err := database.HasPaperFill("t4")
+	require.NoError(t, err)
+	assert.True(t, seen)
+}
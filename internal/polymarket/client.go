@@ -3,10 +3,15 @@ package polymarket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"polytracker/internal/metrics"
+
 	"github.com/go-resty/resty/v2"
 	"golang.org/x/time/rate"
 )
@@ -20,6 +25,9 @@ type Client struct {
 	gammaResty  *resty.Client
 	clobResty   *resty.Client
 	rateLimiter *rate.Limiter
+	apiKey      string
+	apiSecret   string
+	passphrase  string
 }
 
 type Config struct {
@@ -70,12 +78,18 @@ func NewClient(cfg Config) *Client {
 		gammaResty:  gammaResty,
 		clobResty:   clobResty,
 		rateLimiter: limiter,
+		apiKey:      cfg.APIKey,
+		apiSecret:   cfg.APISecret,
+		passphrase:  cfg.Passphrase,
 	}
 
 	// Apply rate limiting middleware
 	gammaResty.OnBeforeRequest(c.beforeRequest)
 	clobResty.OnBeforeRequest(c.beforeRequest)
 
+	instrument(gammaResty, "gamma")
+	instrument(clobResty, "clob")
+
 	return c
 }
 
@@ -84,7 +98,42 @@ func (c *Client) beforeRequest(client *resty.Client, req *resty.Request) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return c.rateLimiter.Wait(ctx)
+	start := time.Now()
+	err := c.rateLimiter.Wait(ctx)
+	metrics.RateLimitWait.Observe(time.Since(start).Seconds())
+	return err
+}
+
+// instrument records request counts and latency for every response, and for
+// requests that failed without one.
+func instrument(client *resty.Client, api string) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		metrics.ObservePolymarketRequest(api, endpointLabel(resp.Request.URL), resp.StatusCode(), resp.Time())
+		return nil
+	})
+	client.OnError(func(req *resty.Request, err error) {
+		var respErr *resty.ResponseError
+		if errors.As(err, &respErr) {
+			return // already counted by the response hook
+		}
+		metrics.ObservePolymarketRequest(api, endpointLabel(req.URL), 0, time.Since(req.Time))
+	})
+}
+
+// endpointLabel reduces a request URL to its route, replacing IDs in the path
+// so the label has a bounded number of values.
+func endpointLabel(rawURL string) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	if strings.HasPrefix(path, "/markets/") {
+		return "/markets/{id}"
+	}
+	if path == "" {
+		return "/"
+	}
+	return path
 }
 
 // ErrorResponse represents a generic API error
@@ -6,6 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"polytracker/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestGetMarket(t *testing.T) {
@@ -97,3 +101,48 @@ func TestRateLimiting(t *testing.T) {
 		}
 	}
 }
+
+func TestClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/markets/missing" {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Market{ID: "123"})
+	}))
+	defer server.Close()
+
+	ok := metrics.PolymarketRequests.WithLabelValues("gamma", "/markets/{id}", "200")
+	notFound := metrics.PolymarketRequests.WithLabelValues("gamma", "/markets/{id}", "404")
+	okBefore, notFoundBefore := testutil.ToFloat64(ok), testutil.ToFloat64(notFound)
+
+	client := NewClient(Config{GammaBaseURL: server.URL})
+	if _, err := client.GetMarket(context.Background(), "123"); err != nil {
+		t.Fatalf("Failed to get market: %v", err)
+	}
+	if _, err := client.GetMarket(context.Background(), "missing"); err == nil {
+		t.Fatal("Expected an error for a missing market")
+	}
+
+	if got := testutil.ToFloat64(ok) - okBefore; got != 1 {
+		t.Errorf("Expected 1 successful request recorded, got %v", got)
+	}
+	if got := testutil.ToFloat64(notFound) - notFoundBefore; got != 1 {
+		t.Errorf("Expected 1 not-found request recorded, got %v", got)
+	}
+}
+
+func TestEndpointLabel(t *testing.T) {
+	tests := map[string]string{
+		"http://127.0.0.1:1234/markets/0xabc": "/markets/{id}",
+		"http://127.0.0.1:1234/markets":       "/markets",
+		"https://clob.polymarket.com/trades":  "/trades",
+		"https://clob.polymarket.com":         "/",
+	}
+	for in, want := range tests {
+		if got := endpointLabel(in); got != want {
+			t.Errorf("endpointLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"polytracker/internal/db"
+	"polytracker/internal/metrics"
+	"strings"
 	"time"
 )
 
@@ -27,6 +29,7 @@ func (f *Fetcher) FetchTraderHistory(ctx context.Context, address string) error
 	// 1. Fetch account trades from Polymarket CLOB
 	apiTrades, err := f.client.GetAccountTrades(ctx, address)
 	if err != nil {
+		metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
 		return fmt.Errorf("failed to fetch account trades: %w", err)
 	}
 
@@ -37,6 +40,7 @@ func (f *Fetcher) FetchTraderHistory(ctx context.Context, address string) error
 
 		// 3. Ensure market info is in DB
 		if err := f.ensureMarket(ctx, at.MarketID); err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
 			log.Printf("Warning: failed to ensure market %s: %v", at.MarketID, err)
 			continue
 		}
@@ -52,19 +56,29 @@ func (f *Fetcher) FetchTraderHistory(ctx context.Context, address string) error
 			Timestamp: time.Unix(at.Timestamp, 0),
 		}
 		
+		switch {
+		case strings.EqualFold(at.Maker, address):
+			t.Role = "maker"
+		case strings.EqualFold(at.Taker, address):
+			t.Role = "taker"
+		}
+
 		// Note: Side (YES/NO) is tricky without knowing which token was traded.
 		// For now, we'll default to YES or try to infer if we had more info.
 		t.Side = "YES" 
 
 		if err := f.db.SaveTrade(t); err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
 			log.Printf("Error saving trade %s: %v", t.ID, err)
 			continue
 		}
+		metrics.TradesProcessed.WithLabelValues(metrics.ComponentFetcher).Inc()
 
 		// 5. Fetch and store market snapshot for this trade time
 		// Ideally we'd get a snapshot AT the trade time, but for now we'll just 
 		// get the current market state as a snapshot if we don't have one recently.
 		if err := f.ensureSnapshot(ctx, at.MarketID); err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
 			log.Printf("Warning: failed to ensure snapshot for market %s: %v", at.MarketID, err)
 		}
 	}
@@ -86,19 +100,39 @@ func (f *Fetcher) ensureMarket(ctx context.Context, marketID string) error {
 	if err != nil {
 		return err
 	}
+	metrics.MarketsProcessed.WithLabelValues(metrics.ComponentFetcher).Inc()
 
 	dbMarket := &db.Market{
-		ID:       apiMarket.ID,
-		Question: apiMarket.Question,
-		Status:   "active", // Default
+		ID:          apiMarket.ID,
+		Question:    apiMarket.Question,
+		Description: apiMarket.Description,
+		Category:    apiMarket.Category,
+		Status:      "active", // Default
+	}
+	if endsAt, err := time.Parse(time.RFC3339, apiMarket.EndDate); err == nil {
+		dbMarket.EndsAt = endsAt
 	}
 	if apiMarket.Closed {
 		dbMarket.Status = "closed"
 	}
+	if apiMarket.Resolution != "" {
+		dbMarket.Status = "resolved"
+		dbMarket.Resolution = apiMarket.Resolution
+		dbMarket.ResolvedAt = resolutionTime(dbMarket.EndsAt)
+	}
 
 	return f.db.SaveMarket(dbMarket)
 }
 
+// resolutionTime estimates when a market resolved: Gamma does not report it,
+// so the market's end date is used once it has passed, and now otherwise.
+func resolutionTime(endsAt time.Time) time.Time {
+	if now := time.Now(); endsAt.IsZero() || endsAt.After(now) {
+		return now
+	}
+	return endsAt
+}
+
 func (f *Fetcher) ensureSnapshot(ctx context.Context, marketID string) error {
 	// Check if we have a recent snapshot (e.g., within the last hour)
 	latest, err := f.db.GetLatestMarketSnapshot(marketID)
@@ -131,3 +165,133 @@ func (f *Fetcher) ensureSnapshot(ctx context.Context, marketID string) error {
 
 	return f.db.SaveMarketSnapshot(snapshot)
 }
+
+// FetchMarket stores the market if it is new, its recent trades, and a price
+// snapshot unless one was taken within the last hour.
+func (f *Fetcher) FetchMarket(ctx context.Context, marketID string) error {
+	if err := f.ensureMarket(ctx, marketID); err != nil {
+		metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
+		return fmt.Errorf("failed to fetch market: %w", err)
+	}
+	if err := f.saveMarketTrades(ctx, marketID); err != nil {
+		metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
+		return err
+	}
+	if err := f.ensureSnapshot(ctx, marketID); err != nil {
+		metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
+		return fmt.Errorf("failed to snapshot market: %w", err)
+	}
+	return nil
+}
+
+// saveMarketTrades stores the market's recent trades, each under its taker, or
+// its maker when no taker is reported. Trades already stored, such as those
+// fetched with a trader's history, are left as they are.
+func (f *Fetcher) saveMarketTrades(ctx context.Context, marketID string) error {
+	apiTrades, err := f.client.GetTrades(ctx, marketID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch market trades: %w", err)
+	}
+	stored, err := f.db.GetTradesByMarket(marketID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(stored))
+	for _, t := range stored {
+		seen[t.ID] = true
+	}
+
+	for _, at := range apiTrades {
+		if seen[at.ID] {
+			continue
+		}
+		t := &db.Trade{
+			ID:        at.ID,
+			TraderID:  at.Taker,
+			MarketID:  marketID,
+			Type:      at.Side,
+			Side:      "YES", // The CLOB does not report which token was traded.
+			Price:     at.Price,
+			Size:      at.Size,
+			Timestamp: time.Unix(at.Timestamp, 0),
+			Role:      "taker",
+		}
+		if t.TraderID == "" {
+			t.TraderID, t.Role = at.Maker, "maker"
+		}
+		if t.TraderID == "" {
+			continue
+		}
+		if err := f.db.SaveTrade(t); err != nil {
+			return err
+		}
+		metrics.TradesProcessed.WithLabelValues(metrics.ComponentFetcher).Inc()
+	}
+	return nil
+}
+
+// SnapshotMarkets stores a price snapshot for every stored market that is still
+// open, skipping markets snapshotted within the last hour. It returns how many
+// markets were checked.
+func (f *Fetcher) SnapshotMarkets(ctx context.Context) (int, error) {
+	markets, err := f.db.ListMarketsExcludingStatus("closed", "resolved")
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range markets {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := f.ensureSnapshot(ctx, m.ID); err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
+			log.Printf("Warning: failed to snapshot market %s: %v", m.ID, err)
+			continue
+		}
+		metrics.MarketsProcessed.WithLabelValues(metrics.ComponentFetcher).Inc()
+	}
+	return len(markets), nil
+}
+
+// SyncResolutions refreshes the status of every unresolved market, marking it
+// closed or resolved when Gamma reports so. It returns how many markets changed.
+func (f *Fetcher) SyncResolutions(ctx context.Context) (int, error) {
+	markets, err := f.db.ListMarketsExcludingStatus("resolved")
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, m := range markets {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		apiMarket, err := f.client.GetMarket(ctx, m.ID)
+		if err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentFetcher).Inc()
+			log.Printf("Warning: failed to refresh market %s: %v", m.ID, err)
+			continue
+		}
+		metrics.MarketsProcessed.WithLabelValues(metrics.ComponentFetcher).Inc()
+
+		status := m.Status
+		switch {
+		case apiMarket.Resolution != "":
+			status = "resolved"
+		case apiMarket.Closed:
+			status = "closed"
+		}
+		if status == m.Status {
+			continue
+		}
+		m.Status = status
+		if status == "resolved" {
+			m.Resolution = apiMarket.Resolution
+			m.ResolvedAt = resolutionTime(m.EndsAt)
+		}
+		if err := f.db.SaveMarket(&m); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"polytracker/internal/db"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,6 +26,8 @@ func TestFetcher_FetchTraderHistory(t *testing.T) {
 	mockMarket := Market{
 		ID:       "m1",
 		Question: "Will it rain?",
+		Category: "Weather",
+		EndDate:  "2024-06-30T12:00:00Z",
 		Tokens: []Token{
 			{TokenID: "t1", Outcome: "Yes", Price: 0.6},
 			{TokenID: "t2", Outcome: "No", Price: 0.4},
@@ -97,6 +100,12 @@ func TestFetcher_FetchTraderHistory(t *testing.T) {
 	if market.Question != "Will it rain?" {
 		t.Errorf("Expected question 'Will it rain?', got '%s'", market.Question)
 	}
+	if market.Category != "Weather" {
+		t.Errorf("Expected category 'Weather', got '%s'", market.Category)
+	}
+	if !market.EndsAt.Equal(time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected end date 2024-06-30 12:00 UTC, got %s", market.EndsAt)
+	}
 
 	snapshot, err := database.GetLatestMarketSnapshot("m1")
 	if err != nil {
@@ -109,3 +118,155 @@ func TestFetcher_FetchTraderHistory(t *testing.T) {
 		t.Errorf("Expected yes price 0.6, got %f", snapshot.YesPrice)
 	}
 }
+
+func TestFetcher_SyncResolutions(t *testing.T) {
+	dbPath := "test_fetcher_resolutions.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create DB: %v", err)
+	}
+	defer database.Close()
+
+	for _, m := range []db.Market{
+		{ID: "open", Status: "active"},
+		{ID: "closing", Status: "active"},
+		{ID: "resolving", Status: "closed"},
+		{ID: "done", Status: "resolved"},
+	} {
+		m := m
+		if err := database.SaveMarket(&m); err != nil {
+			t.Fatalf("Failed to save market: %v", err)
+		}
+	}
+
+	var requested []string
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/markets/")
+		requested = append(requested, id)
+		m := Market{ID: id}
+		switch id {
+		case "closing":
+			m.Closed = true
+		case "resolving":
+			m.Closed = true
+			m.Resolution = "Yes"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(m)
+	}))
+	defer gammaServer.Close()
+
+	fetcher := NewFetcher(NewClient(Config{GammaBaseURL: gammaServer.URL}), database)
+	changed, err := fetcher.SyncResolutions(context.Background())
+	if err != nil {
+		t.Fatalf("SyncResolutions failed: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 markets to change, got %d", changed)
+	}
+	if len(requested) != 3 {
+		t.Errorf("Expected resolved markets to be skipped, requested %v", requested)
+	}
+
+	for id, want := range map[string]string{"open": "active", "closing": "closed", "resolving": "resolved"} {
+		m, err := database.GetMarket(id)
+		if err != nil || m == nil {
+			t.Fatalf("Failed to get market %s: %v", id, err)
+		}
+		if m.Status != want {
+			t.Errorf("Market %s: expected status %s, got %s", id, want, m.Status)
+		}
+	}
+
+	resolved, err := database.GetMarket("resolving")
+	if err != nil {
+		t.Fatalf("Failed to get market: %v", err)
+	}
+	if resolved.Resolution != "Yes" || resolved.ResolvedAt.IsZero() {
+		t.Errorf("Expected the Yes resolution recorded, got %q at %v", resolved.Resolution, resolved.ResolvedAt)
+	}
+}
+
+func TestFetcher_FetchMarket(t *testing.T) {
+	dbPath := "test_fetcher_market.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create DB: %v", err)
+	}
+	defer database.Close()
+
+	requests := 0
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Market{
+			ID:       "m1",
+			Question: "Will it rain?",
+			Tokens:   []Token{{Outcome: "Yes", Price: 0.65}, {Outcome: "No", Price: 0.35}},
+		})
+	}))
+	defer gammaServer.Close()
+
+	clobServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("market_id"); got != "m1" {
+			t.Errorf("Expected trades for m1, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Trade{
+			{ID: "trade-1", MarketID: "m1", Price: 0.6, Size: 50, Side: "BUY", Timestamp: time.Now().Unix(), Maker: "0xmaker", Taker: "0xtaker"},
+			{ID: "trade-2", MarketID: "m1", Price: 0.62, Size: 10, Side: "SELL", Timestamp: time.Now().Unix(), Maker: "0xmaker"},
+			{ID: "trade-3", MarketID: "m1", Price: 0.61, Size: 20, Side: "BUY", Timestamp: time.Now().Unix(), Taker: "0xother"},
+		})
+	}))
+	defer clobServer.Close()
+
+	// A trade already stored with a trader's history keeps its attribution.
+	if err := database.SaveTrade(&db.Trade{ID: "trade-3", TraderID: "0xtracked", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.61, Size: 20, Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save trade: %v", err)
+	}
+
+	fetcher := NewFetcher(NewClient(Config{GammaBaseURL: gammaServer.URL, CLOBBaseURL: clobServer.URL}), database)
+	if err := fetcher.FetchMarket(context.Background(), "m1"); err != nil {
+		t.Fatalf("FetchMarket failed: %v", err)
+	}
+
+	trades, err := database.GetTradesByMarket("m1")
+	if err != nil {
+		t.Fatalf("Failed to get trades: %v", err)
+	}
+	byID := make(map[string]db.Trade)
+	for _, tr := range trades {
+		byID[tr.ID] = tr
+	}
+	if len(byID) != 3 {
+		t.Fatalf("Expected 3 trades, got %+v", trades)
+	}
+	if tr := byID["trade-1"]; tr.TraderID != "0xtaker" || tr.Role != "taker" || tr.Type != "BUY" {
+		t.Errorf("Expected trade-1 under its taker, got %+v", tr)
+	}
+	if tr := byID["trade-2"]; tr.TraderID != "0xmaker" || tr.Role != "maker" {
+		t.Errorf("Expected trade-2 under its maker, got %+v", tr)
+	}
+	if tr := byID["trade-3"]; tr.TraderID != "0xtracked" {
+		t.Errorf("Expected trade-3 left under 0xtracked, got %+v", tr)
+	}
+
+	m, err := database.GetMarket("m1")
+	if err != nil || m == nil || m.Question != "Will it rain?" {
+		t.Fatalf("Expected market to be saved, got %+v, %v", m, err)
+	}
+	s, err := database.GetLatestMarketSnapshot("m1")
+	if err != nil || s == nil || s.YesPrice != 0.65 {
+		t.Fatalf("Expected snapshot to be saved, got %+v, %v", s, err)
+	}
+
+	// A second fetch within the hour reuses both.
+	if err := fetcher.FetchMarket(context.Background(), "m1"); err != nil {
+		t.Fatalf("FetchMarket failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 Gamma requests, got %d", requests)
+	}
+}
@@ -8,6 +8,9 @@ import (
 type Market struct {
 	ID            string   `json:"id"`
 	Question      string   `json:"question"`
+	Description   string   `json:"description"`
+	Category      string   `json:"category"`
+	EndDate       string   `json:"endDate"`
 	ConditionID   string   `json:"conditionId"`
 	Slug          string   `json:"slug"`
 	Resolution    string   `json:"resolution"`
@@ -0,0 +1,252 @@
+package polymarket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// DefaultChainID is Polygon mainnet, where the Polymarket exchange is deployed.
+	DefaultChainID = 137
+	// DefaultExchangeAddress is the Polymarket CTF Exchange contract that verifies orders.
+	DefaultExchangeAddress = "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"
+
+	OrderSideBuy  = "BUY"
+	OrderSideSell = "SELL"
+
+	zeroAddress = "0x0000000000000000000000000000000000000000"
+	// amountDecimals is the fixed-point precision of USDC and outcome tokens.
+	amountDecimals = 6
+)
+
+var (
+	orderTypeHash = keccak256([]byte("Order(uint256 salt,address maker,address signer,address taker,uint256 tokenId," +
+		"uint256 makerAmount,uint256 takerAmount,uint256 expiration,uint256 nonce,uint256 feeRateBps,uint8 side,uint8 signatureType)"))
+	domainTypeHash = keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+)
+
+// Order is a CTF Exchange limit order as submitted to the CLOB. Amounts are in
+// six-decimal base units: a BUY offers MakerAmount USDC for TakerAmount outcome
+// tokens, a SELL the reverse.
+type Order struct {
+	Salt          int64  `json:"salt"`
+	Maker         string `json:"maker"`
+	Signer        string `json:"signer"`
+	Taker         string `json:"taker"`
+	TokenID       string `json:"tokenId"`
+	MakerAmount   string `json:"makerAmount"`
+	TakerAmount   string `json:"takerAmount"`
+	Expiration    string `json:"expiration"`
+	Nonce         string `json:"nonce"`
+	FeeRateBps    string `json:"feeRateBps"`
+	Side          string `json:"side"`
+	SignatureType int    `json:"signatureType"`
+	Signature     string `json:"signature,omitempty"`
+}
+
+// OrderSigner builds and signs orders with an externally owned account key.
+type OrderSigner struct {
+	key      *secp256k1.PrivateKey
+	address  string
+	chainID  int64
+	exchange string
+}
+
+// NewOrderSigner parses a hex private key (with or without 0x). Zero values for
+// chainID and exchange select Polygon mainnet and the CTF Exchange.
+func NewOrderSigner(privateKey string, chainID int64, exchange string) (*OrderSigner, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(privateKey), "0x"))
+	if err != nil || len(raw) != 32 {
+		return nil, fmt.Errorf("invalid private key: expected 32 hex-encoded bytes")
+	}
+	if chainID == 0 {
+		chainID = DefaultChainID
+	}
+	if exchange == "" {
+		exchange = DefaultExchangeAddress
+	}
+	if _, err := addressBytes(exchange); err != nil {
+		return nil, fmt.Errorf("invalid exchange address: %w", err)
+	}
+
+	key := secp256k1.PrivKeyFromBytes(raw)
+	return &OrderSigner{
+		key:      key,
+		address:  publicKeyAddress(key.PubKey()),
+		chainID:  chainID,
+		exchange: exchange,
+	}, nil
+}
+
+// Address is the signer's checksummed Ethereum address.
+func (s *OrderSigner) Address() string {
+	return s.address
+}
+
+// BuildOrder creates an unsigned good-til-cancelled order for size outcome tokens
+// at price (0-1).
+func (s *OrderSigner) BuildOrder(tokenID, side string, price, size float64) (*Order, error) {
+	side = strings.ToUpper(side)
+	if side != OrderSideBuy && side != OrderSideSell {
+		return nil, fmt.Errorf("invalid side %q: expected BUY or SELL", side)
+	}
+	if price <= 0 || price >= 1 {
+		return nil, fmt.Errorf("invalid price %.4f: must be between 0 and 1", price)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid size %.4f: must be positive", size)
+	}
+	if _, ok := new(big.Int).SetString(tokenID, 10); !ok {
+		return nil, fmt.Errorf("invalid token ID %q", tokenID)
+	}
+
+	salt, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	tokens := toBaseUnits(size)
+	usdc := toBaseUnits(price * size)
+	order := &Order{
+		Salt:        salt.Int64(),
+		Maker:       s.address,
+		Signer:      s.address,
+		Taker:       zeroAddress,
+		TokenID:     tokenID,
+		MakerAmount: usdc,
+		TakerAmount: tokens,
+		Expiration:  "0",
+		Nonce:       "0",
+		FeeRateBps:  "0",
+		Side:        side,
+	}
+	if side == OrderSideSell {
+		order.MakerAmount, order.TakerAmount = tokens, usdc
+	}
+	return order, nil
+}
+
+// Sign sets the order's EIP-712 signature.
+func (s *OrderSigner) Sign(order *Order) error {
+	digest, err := s.OrderHash(order)
+	if err != nil {
+		return err
+	}
+
+	// SignCompact returns v || r || s with v = 27 + recovery id; Ethereum expects r || s || v.
+	compact := ecdsa.SignCompact(s.key, digest, false)
+	sig := append(compact[1:], compact[0])
+	order.Signature = "0x" + hex.EncodeToString(sig)
+	return nil
+}
+
+// OrderHash is the EIP-712 digest of the order under the signer's exchange domain.
+func (s *OrderSigner) OrderHash(order *Order) ([]byte, error) {
+	structHash, err := hashOrder(order)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.domainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte{0x19, 0x01}, domain, structHash), nil
+}
+
+func (s *OrderSigner) domainSeparator() ([]byte, error) {
+	exchange, err := addressBytes(s.exchange)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(
+		domainTypeHash,
+		keccak256([]byte("Polymarket CTF Exchange")),
+		keccak256([]byte("1")),
+		uint256(big.NewInt(s.chainID)),
+		leftPad(exchange),
+	), nil
+}
+
+func hashOrder(o *Order) ([]byte, error) {
+	var words [][]byte
+	words = append(words, orderTypeHash, uint256(big.NewInt(o.Salt)))
+
+	for _, a := range []string{o.Maker, o.Signer, o.Taker} {
+		b, err := addressBytes(a)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, leftPad(b))
+	}
+
+	for _, n := range []string{o.TokenID, o.MakerAmount, o.TakerAmount, o.Expiration, o.Nonce, o.FeeRateBps} {
+		v, ok := new(big.Int).SetString(n, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid order integer %q", n)
+		}
+		words = append(words, uint256(v))
+	}
+
+	side := int64(0)
+	if o.Side == OrderSideSell {
+		side = 1
+	}
+	words = append(words, uint256(big.NewInt(side)), uint256(big.NewInt(int64(o.SignatureType))))
+	return keccak256(words...), nil
+}
+
+func toBaseUnits(v float64) string {
+	return big.NewInt(int64(math.Round(v * math.Pow10(amountDecimals)))).String()
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func uint256(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, 32))
+}
+
+func leftPad(b []byte) []byte {
+	out := make([]byte, 32)
+	copy(out[32-len(b):], b)
+	return out
+}
+
+func addressBytes(addr string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(addr, "0x"))
+	if err != nil || len(b) != 20 {
+		return nil, fmt.Errorf("invalid address %q", addr)
+	}
+	return b, nil
+}
+
+// publicKeyAddress derives the EIP-55 checksummed address of a public key.
+func publicKeyAddress(pub *secp256k1.PublicKey) string {
+	hash := keccak256(pub.SerializeUncompressed()[1:])
+	return checksumAddress(hash[12:])
+}
+
+func checksumAddress(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && hash[i] >= '8' {
+			out[i] = c - 32
+		}
+	}
+	return "0x" + string(out)
+}
@@ -0,0 +1,213 @@
+package polymarket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"polytracker/internal/db"
+)
+
+// ErrOrderCancelled is returned when the confirmation prompt is declined.
+var ErrOrderCancelled = errors.New("order cancelled")
+
+// OrderLimits caps exposure from orders placed through polytracker. Zero disables a cap.
+type OrderLimits struct {
+	MaxMarketNotional float64 // total submitted notional per market
+	MaxDailyNotional  float64 // total submitted notional since local midnight
+}
+
+// OrderRequest describes a limit order to place.
+type OrderRequest struct {
+	MarketID string
+	TokenID  string
+	Side     string // BUY/SELL
+	Price    float64
+	Size     float64
+}
+
+// Notional is the USDC value of the order.
+func (r OrderRequest) Notional() float64 {
+	return r.Price * r.Size
+}
+
+// OrderResult is the outcome of PlaceOrder.
+type OrderResult struct {
+	Order  *Order
+	Record *db.Order
+}
+
+type postOrderRequest struct {
+	Order     *Order `json:"order"`
+	Owner     string `json:"owner"`
+	OrderType string `json:"orderType"`
+}
+
+// OrderResponse is the CLOB reply to a posted order.
+type OrderResponse struct {
+	Success  bool   `json:"success"`
+	ErrorMsg string `json:"errorMsg"`
+	OrderID  string `json:"orderID"`
+	Status   string `json:"status"`
+}
+
+// OrderManager signs orders, enforces notional caps and, unless DryRun is set,
+// submits them to the CLOB. Every order, sent or not, is recorded.
+type OrderManager struct {
+	client *Client
+	db     *db.DB
+	signer *OrderSigner
+	Limits OrderLimits
+	DryRun bool
+	// Confirm is asked before a live order is sent; returning false cancels it.
+	Confirm func(req OrderRequest) bool
+}
+
+func NewOrderManager(client *Client, database *db.DB, signer *OrderSigner) *OrderManager {
+	return &OrderManager{
+		client: client,
+		db:     database,
+		signer: signer,
+		DryRun: true,
+	}
+}
+
+// PlaceOrder builds and signs the order, checks it against the limits and, for
+// live orders that are confirmed, posts it to the exchange.
+func (m *OrderManager) PlaceOrder(ctx context.Context, req OrderRequest) (*OrderResult, error) {
+	order, err := m.signer.BuildOrder(req.TokenID, req.Side, req.Price, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkLimits(req); err != nil {
+		return nil, err
+	}
+	if err := m.signer.Sign(order); err != nil {
+		return nil, fmt.Errorf("failed to sign order: %w", err)
+	}
+
+	record := &db.Order{
+		MarketID: req.MarketID,
+		TokenID:  req.TokenID,
+		Side:     order.Side,
+		Price:    req.Price,
+		Size:     req.Size,
+		Notional: req.Notional(),
+		Status:   db.OrderStatusDryRun,
+	}
+	result := &OrderResult{Order: order, Record: record}
+
+	if m.DryRun {
+		return result, m.db.SaveOrder(record)
+	}
+	if m.Confirm != nil && !m.Confirm(req) {
+		return nil, ErrOrderCancelled
+	}
+
+	resp, postErr := m.client.PostOrder(ctx, order, m.signer.Address())
+	if postErr != nil {
+		record.Status = db.OrderStatusRejected
+		record.Error = postErr.Error()
+	} else {
+		record.Status = db.OrderStatusSubmitted
+		record.ExchangeOrderID = resp.OrderID
+	}
+	if err := m.db.SaveOrder(record); err != nil {
+		return result, err
+	}
+	return result, postErr
+}
+
+func (m *OrderManager) checkLimits(req OrderRequest) error {
+	notional := req.Notional()
+
+	if m.Limits.MaxMarketNotional > 0 {
+		spent, err := m.db.SumSubmittedNotional(req.MarketID, time.Time{})
+		if err != nil {
+			return err
+		}
+		if spent+notional > m.Limits.MaxMarketNotional {
+			return fmt.Errorf("order of $%.2f would exceed the per-market cap of $%.2f ($%.2f already placed)",
+				notional, m.Limits.MaxMarketNotional, spent)
+		}
+	}
+
+	if m.Limits.MaxDailyNotional > 0 {
+		now := time.Now()
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		spent, err := m.db.SumSubmittedNotional("", midnight)
+		if err != nil {
+			return err
+		}
+		if spent+notional > m.Limits.MaxDailyNotional {
+			return fmt.Errorf("order of $%.2f would exceed the daily cap of $%.2f ($%.2f already placed today)",
+				notional, m.Limits.MaxDailyNotional, spent)
+		}
+	}
+	return nil
+}
+
+// PostOrder submits a signed good-til-cancelled order using L2 API key authentication.
+func (c *Client) PostOrder(ctx context.Context, order *Order, address string) (*OrderResponse, error) {
+	if c.apiKey == "" || c.apiSecret == "" || c.passphrase == "" {
+		return nil, fmt.Errorf("polymarket API key, secret and passphrase are required to place orders")
+	}
+
+	body, err := json.Marshal(postOrderRequest{Order: order, Owner: c.apiKey, OrderType: "GTC"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode order: %w", err)
+	}
+
+	headers, err := c.l2Headers(address, http.MethodPost, "/order", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result OrderResponse
+	resp, err := c.clobResty.R().
+		SetContext(ctx).
+		SetHeaders(headers).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&result).
+		Post("/order")
+	if err != nil {
+		return nil, fmt.Errorf("failed to post order: %w", err)
+	}
+	if err := c.checkError(resp); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("order rejected: %s", result.ErrorMsg)
+	}
+	return &result, nil
+}
+
+// l2Headers signs a CLOB request with the API secret: an HMAC-SHA256 over
+// timestamp, method, path and body, base64url encoded.
+func (c *Client) l2Headers(address, method, path string, body []byte) (map[string]string, error) {
+	secret, err := base64.URLEncoding.DecodeString(c.apiSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid polymarket API secret: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + strings.ToUpper(method) + path + string(body)))
+
+	return map[string]string{
+		"POLY_ADDRESS":    address,
+		"POLY_SIGNATURE":  base64.URLEncoding.EncodeToString(mac.Sum(nil)),
+		"POLY_TIMESTAMP":  timestamp,
+		"POLY_API_KEY":    c.apiKey,
+		"POLY_PASSPHRASE": c.passphrase,
+	}, nil
+}
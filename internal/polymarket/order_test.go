@@ -0,0 +1,169 @@
+package polymarket
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"polytracker/internal/db"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPrivateKey = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	testAddress    = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"
+	testTokenID    = "71321045679252212594626385532706912750332728571942532289631379312455583992563"
+	testAPISecret  = "c2VjcmV0LXNlY3JldC1zZWNyZXQ="
+)
+
+func TestOrderSigner_BuildAndSign(t *testing.T) {
+	signer, err := NewOrderSigner(testPrivateKey, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, testAddress, signer.Address())
+
+	order, err := signer.BuildOrder(testTokenID, "buy", 0.42, 100)
+	require.NoError(t, err)
+	assert.Equal(t, OrderSideBuy, order.Side)
+	assert.Equal(t, "42000000", order.MakerAmount)
+	assert.Equal(t, "100000000", order.TakerAmount)
+
+	sell, err := signer.BuildOrder(testTokenID, "SELL", 0.42, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "100000000", sell.MakerAmount)
+	assert.Equal(t, "42000000", sell.TakerAmount)
+
+	require.NoError(t, signer.Sign(order))
+	sig, err := hex.DecodeString(strings.TrimPrefix(order.Signature, "0x"))
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+	assert.Contains(t, []byte{27, 28}, sig[64])
+
+	// The signature must recover to the signer over the EIP-712 digest.
+	digest, err := signer.OrderHash(order)
+	require.NoError(t, err)
+	compact := append([]byte{sig[64]}, sig[:64]...)
+	pub, _, err := ecdsa.RecoverCompact(compact, digest)
+	require.NoError(t, err)
+	assert.Equal(t, testAddress, publicKeyAddress(pub))
+
+	// The digest covers every field.
+	other := *order
+	other.TakerAmount = "100000001"
+	otherDigest, err := signer.OrderHash(&other)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, otherDigest)
+
+	_, err = signer.BuildOrder(testTokenID, "BUY", 1.2, 10)
+	assert.Error(t, err)
+	_, err = NewOrderSigner("0x1234", 0, "")
+	assert.Error(t, err)
+}
+
+// newStandInCLOB serves POST /order like the CLOB, verifying L2 auth, and counts calls.
+func newStandInCLOB(t *testing.T, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/order" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		secret, _ := base64.URLEncoding.DecodeString(testAPISecret)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(r.Header.Get("POLY_TIMESTAMP") + "POST/order" + string(body)))
+		if r.Header.Get("POLY_SIGNATURE") != base64.URLEncoding.EncodeToString(mac.Sum(nil)) {
+			t.Error("POLY_SIGNATURE does not match the request")
+		}
+		if r.Header.Get("POLY_ADDRESS") != testAddress || r.Header.Get("POLY_API_KEY") != "key" || r.Header.Get("POLY_PASSPHRASE") != "pass" {
+			t.Errorf("Unexpected auth headers: %v", r.Header)
+		}
+
+		var req postOrderRequest
+		if err := json.Unmarshal(body, &req); err != nil || req.Order == nil || req.Order.Signature == "" {
+			t.Errorf("Expected a signed order, got %s", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(OrderResponse{Success: true, OrderID: "0xabc", Status: "live"})
+	}))
+}
+
+func newTestOrderManager(t *testing.T, serverURL, dbPath string) (*OrderManager, *db.DB) {
+	database, err := db.NewDB(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		database.Close()
+		os.Remove(dbPath)
+	})
+
+	client := NewClient(Config{CLOBBaseURL: serverURL, APIKey: "key", APISecret: testAPISecret, Passphrase: "pass"})
+	signer, err := NewOrderSigner(testPrivateKey, 0, "")
+	require.NoError(t, err)
+	return NewOrderManager(client, database, signer), database
+}
+
+func TestOrderManager_DryRunByDefault(t *testing.T) {
+	var calls int32
+	server := newStandInCLOB(t, &calls)
+	defer server.Close()
+
+	m, database := newTestOrderManager(t, server.URL, "test_orders_dry.db")
+	res, err := m.PlaceOrder(context.Background(), OrderRequest{MarketID: "m1", TokenID: testTokenID, Side: "BUY", Price: 0.5, Size: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(0), calls)
+	assert.Equal(t, db.OrderStatusDryRun, res.Record.Status)
+	assert.NotEmpty(t, res.Order.Signature)
+
+	orders, err := database.ListOrders(10)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, db.OrderStatusDryRun, orders[0].Status)
+}
+
+func TestOrderManager_LiveWithLimits(t *testing.T) {
+	var calls int32
+	server := newStandInCLOB(t, &calls)
+	defer server.Close()
+
+	m, _ := newTestOrderManager(t, server.URL, "test_orders_live.db")
+	m.DryRun = false
+	m.Limits = OrderLimits{MaxMarketNotional: 8, MaxDailyNotional: 12}
+
+	// Declined confirmation sends nothing.
+	m.Confirm = func(OrderRequest) bool { return false }
+	_, err := m.PlaceOrder(context.Background(), OrderRequest{MarketID: "m1", TokenID: testTokenID, Side: "BUY", Price: 0.5, Size: 10})
+	assert.ErrorIs(t, err, ErrOrderCancelled)
+	assert.Equal(t, int32(0), calls)
+
+	m.Confirm = func(OrderRequest) bool { return true }
+	res, err := m.PlaceOrder(context.Background(), OrderRequest{MarketID: "m1", TokenID: testTokenID, Side: "BUY", Price: 0.5, Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, db.OrderStatusSubmitted, res.Record.Status)
+	assert.Equal(t, "0xabc", res.Record.ExchangeOrderID)
+
+	// $5 more in m1 breaks the $8 per-market cap.
+	_, err = m.PlaceOrder(context.Background(), OrderRequest{MarketID: "m1", TokenID: testTokenID, Side: "BUY", Price: 0.5, Size: 10})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "per-market cap")
+
+	// $8 in m2 fits the market cap but breaks the $12 daily cap.
+	_, err = m.PlaceOrder(context.Background(), OrderRequest{MarketID: "m2", TokenID: testTokenID, Side: "BUY", Price: 0.4, Size: 20})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "daily cap")
+	assert.Equal(t, int32(1), calls)
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"polytracker/internal/db"
+	"polytracker/internal/metrics"
 	"time"
 )
 
 type Scanner struct {
-	client *Client
-	db     *db.DB
+	client        *Client
+	db            *db.DB
+	excludedTypes map[string]bool
 }
 
 func NewScanner(client *Client, database *db.DB) *Scanner {
@@ -20,6 +22,15 @@ func NewScanner(client *Client, database *db.DB) *Scanner {
 	}
 }
 
+// SetExcludedTypes makes the scanner skip traders whose stored classifier label
+// (e.g. market_maker, bot) is one of the given types.
+func (s *Scanner) SetExcludedTypes(types []string) {
+	s.excludedTypes = make(map[string]bool, len(types))
+	for _, t := range types {
+		s.excludedTypes[t] = true
+	}
+}
+
 // ScanRecentActivity fetches recent markets and their trades to identify active traders
 func (s *Scanner) ScanRecentActivity(ctx context.Context, marketLimit int) error {
 	markets, err := s.client.ListMarkets(ctx, marketLimit)
@@ -38,18 +49,33 @@ func (s *Scanner) ScanRecentActivity(ctx context.Context, marketLimit int) error
 		log.Printf("Scanning market: %s", m.Question)
 		trades, err := s.client.GetTrades(ctx, m.ID)
 		if err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentScanner).Inc()
 			log.Printf("Warning: failed to get trades for market %s: %v", m.ID, err)
 			continue
 		}
+		metrics.MarketsProcessed.WithLabelValues(metrics.ComponentScanner).Inc()
+		metrics.TradesProcessed.WithLabelValues(metrics.ComponentScanner).Add(float64(len(trades)))
 
 		for _, t := range trades {
 			s.processTrade(t, traderStats)
 		}
 	}
 
+	var labels map[string]string
+	if len(s.excludedTypes) > 0 {
+		labels, err = s.db.GetTraderLabels()
+		if err != nil {
+			return fmt.Errorf("failed to load trader labels: %w", err)
+		}
+	}
+
 	// Save traders to database
 	for _, trader := range traderStats {
+		if s.excludedTypes[labels[trader.Address]] {
+			continue
+		}
 		if err := s.db.SaveTrader(trader); err != nil {
+			metrics.Errors.WithLabelValues(metrics.ComponentScanner).Inc()
 			log.Printf("Error saving trader %s: %v", trader.Address, err)
 		}
 	}
@@ -73,3 +73,56 @@ func TestScanner_ScanRecentActivity(t *testing.T) {
 		t.Errorf("addr1 not found in DB")
 	}
 }
+
+func TestScanner_ExcludedTypes(t *testing.T) {
+	dbPath := "test_scanner_excluded.db"
+	defer os.Remove(dbPath)
+	database, err := db.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.SaveTraderClassification(&db.TraderClassification{
+		TraderID: "addr1",
+		Label:    db.TraderTypeMarketMaker,
+	}); err != nil {
+		t.Fatalf("Failed to save classification: %v", err)
+	}
+
+	mockMarkets := []Market{
+		{ID: "m1", Question: "Market 1"},
+	}
+	mockTrades := []Trade{
+		{ID: "t1", MarketID: "m1", Price: 0.5, Size: 100, Maker: "addr1", Taker: "addr2"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/markets" {
+			json.NewEncoder(w).Encode(mockMarkets)
+		} else if r.URL.Path == "/trades" {
+			json.NewEncoder(w).Encode(mockTrades)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{
+		GammaBaseURL: server.URL,
+		CLOBBaseURL:  server.URL,
+	})
+
+	scanner := NewScanner(client, database)
+	scanner.SetExcludedTypes([]string{db.TraderTypeMarketMaker})
+	if err := scanner.ScanRecentActivity(context.Background(), 1); err != nil {
+		t.Fatalf("ScanRecentActivity failed: %v", err)
+	}
+
+	traders, err := database.ListTraders()
+	if err != nil {
+		t.Fatalf("Failed to list traders: %v", err)
+	}
+	if len(traders) != 1 || traders[0].Address != "addr2" {
+		t.Errorf("Expected only addr2 to be saved, got %+v", traders)
+	}
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// DefaultLockTTL bounds how long a crashed daemon can hold a job's lock.
+	DefaultLockTTL = time.Hour
+	// DefaultShutdownTimeout is how long Stop waits for running jobs before cancelling them.
+	DefaultShutdownTimeout = 30 * time.Second
+)
+
+// ErrLocked is returned by Run when another process holds the job's lock.
+var ErrLocked = errors.New("job is locked by another process")
+
+// Job is a named task run on a cron schedule.
+type Job struct {
+	Name     string
+	Schedule string // standard five-field cron expression or descriptor such as @hourly
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on their schedules. Each run takes a lock in SQLite so
+// daemons sharing a database never run the same job concurrently, and is
+// recorded in the job history.
+type Scheduler struct {
+	db              *db.DB
+	owner           string
+	cron            *cron.Cron
+	ctx             context.Context
+	cancel          context.CancelFunc
+	LockTTL         time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// NewScheduler creates a scheduler whose locks are held under owner, which
+// should identify this process (for example host:pid).
+func NewScheduler(database *db.DB, owner string) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		db:              database,
+		owner:           owner,
+		cron:            cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+		ctx:             ctx,
+		cancel:          cancel,
+		LockTTL:         DefaultLockTTL,
+		ShutdownTimeout: DefaultShutdownTimeout,
+	}
+}
+
+// Add schedules a job. It fails if the schedule does not parse.
+func (s *Scheduler) Add(job Job) error {
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", job.Schedule, job.Name, err)
+	}
+	s.cron.Schedule(schedule, cron.FuncJob(func() {
+		err := s.Run(job)
+		switch {
+		case errors.Is(err, ErrLocked):
+			log.Printf("Skipping %s: %v", job.Name, err)
+		case err != nil:
+			log.Printf("Job %s failed: %v", job.Name, err)
+		}
+	}))
+	return nil
+}
+
+// Run executes a job immediately under its lock and records the run.
+func (s *Scheduler) Run(job Job) error {
+	ok, err := s.db.AcquireJobLock(job.Name, s.owner, s.LockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLocked
+	}
+	defer func() {
+		if err := s.db.ReleaseJobLock(job.Name, s.owner); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+
+	run, err := s.db.StartJobRun(job.Name, s.owner)
+	if err != nil {
+		return err
+	}
+	log.Printf("Running %s", job.Name)
+	runErr := job.Run(s.ctx)
+	if err := s.db.FinishJobRun(run, runErr); err != nil {
+		return err
+	}
+	if runErr == nil {
+		log.Printf("Finished %s in %s", job.Name, run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond))
+	}
+	return runErr
+}
+
+// Start begins running scheduled jobs in the background.
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs and waits for running jobs to finish. Jobs
+// still running after ShutdownTimeout have their context cancelled.
+func (s *Scheduler) Stop() {
+	done := s.cron.Stop().Done()
+	select {
+	case <-done:
+	case <-time.After(s.ShutdownTimeout):
+		log.Printf("Jobs still running after %s; cancelling", s.ShutdownTimeout)
+		s.cancel()
+		<-done
+	}
+	s.cancel()
+}
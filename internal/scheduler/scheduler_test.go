@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDB(t *testing.T, path string) *db.DB {
+	t.Cleanup(func() { os.Remove(path) })
+	database, err := db.NewDB(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestScheduler_RunRecordsHistory(t *testing.T) {
+	database := newTestDB(t, "test_scheduler.db")
+	s := NewScheduler(database, "test")
+
+	require.NoError(t, s.Run(Job{Name: "ok", Run: func(ctx context.Context) error { return nil }}))
+	err := s.Run(Job{Name: "broken", Run: func(ctx context.Context) error { return errors.New("boom") }})
+	assert.EqualError(t, err, "boom")
+
+	runs, err := database.ListJobRuns("", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	byJob := map[string]db.JobRun{runs[0].Job: runs[0], runs[1].Job: runs[1]}
+	assert.Equal(t, db.JobStatusSucceeded, byJob["ok"].Status)
+	assert.Equal(t, db.JobStatusFailed, byJob["broken"].Status)
+	assert.Equal(t, "boom", byJob["broken"].Error)
+
+	// Locks are released after each run.
+	ok, err := database.AcquireJobLock("ok", "someone-else", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestScheduler_RespectsOtherOwnersLock(t *testing.T) {
+	database := newTestDB(t, "test_scheduler_lock.db")
+	ok, err := database.AcquireJobLock("scan", "other-daemon", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ran := false
+	s := NewScheduler(database, "test")
+	err = s.Run(Job{Name: "scan", Run: func(ctx context.Context) error { ran = true; return nil }})
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.False(t, ran)
+
+	runs, err := database.ListJobRuns("scan", 10)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestScheduler_AddRejectsBadSchedule(t *testing.T) {
+	database := newTestDB(t, "test_scheduler_parse.db")
+	s := NewScheduler(database, "test")
+
+	assert.NoError(t, s.Add(Job{Name: "a", Schedule: "*/5 * * * *", Run: func(context.Context) error { return nil }}))
+	assert.NoError(t, s.Add(Job{Name: "b", Schedule: "@hourly", Run: func(context.Context) error { return nil }}))
+	assert.Error(t, s.Add(Job{Name: "c", Schedule: "every five minutes", Run: func(context.Context) error { return nil }}))
+}
+
+func TestScheduler_StopCancelsSlowJobs(t *testing.T) {
+	database := newTestDB(t, "test_scheduler_stop.db")
+	s := NewScheduler(database, "test")
+	s.ShutdownTimeout = 20 * time.Millisecond
+
+	started := make(chan struct{})
+	var once sync.Once
+	require.NoError(t, s.Add(Job{Name: "slow", Schedule: "@every 1s", Run: func(ctx context.Context) error {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return ctx.Err()
+	}}))
+
+	s.Start()
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job never started")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop did not cancel the running job")
+	}
+
+	runs, err := database.ListJobRuns("slow", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, db.JobStatusFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Error, "context canceled")
+}
@@ -2,17 +2,18 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"polytracker/internal/claude"
 	"polytracker/internal/db"
+	"polytracker/internal/export"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -24,6 +25,7 @@ const (
 	analysisStateAnalyzing
 	analysisStateComplete
 	analysisStateError
+	analysisStateCancelled
 )
 
 type AnalysisKeyMap struct {
@@ -33,6 +35,8 @@ type AnalysisKeyMap struct {
 	Save  key.Binding
 	Copy  key.Binding
 	Retry key.Binding
+	Ask   key.Binding
+	Send  key.Binding
 }
 
 var analysisKeys = AnalysisKeyMap{
@@ -46,7 +50,7 @@ var analysisKeys = AnalysisKeyMap{
 	),
 	Back: key.NewBinding(
 		key.WithKeys("esc", "backspace"),
-		key.WithHelp("esc", "back"),
+		key.WithHelp("esc", "back / cancel"),
 	),
 	Save: key.NewBinding(
 		key.WithKeys("s"),
@@ -60,12 +64,22 @@ var analysisKeys = AnalysisKeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "retry"),
 	),
+	Ask: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "follow up"),
+	),
+	Send: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "send"),
+	),
 }
 
 type Analysis struct {
 	trader       *db.Trader
 	trades       []db.Trade
 	markets      map[string]*db.Market
+	profile      *db.TraderProfile
+	snapshots    map[string]*db.MarketSnapshot
 	thesis       string
 	state        analysisState
 	styles       Styles
@@ -77,20 +91,57 @@ type Analysis struct {
 	savedPath    string
 	copied       bool
 	claudeClient *claude.Client
+
+	// Streaming state: the live stream, its cancel func, when it started and
+	// whether the view follows the end of the thesis as it grows.
+	stream    <-chan claude.StreamEvent
+	cancel    context.CancelFunc
+	started   time.Time
+	follow    bool
+	result    *claude.AnalysisResult
+	elapsed   time.Duration
+	truncated bool
+	// prompt is the rendered prompt, kept to report its estimated size.
+	prompt *claude.Prompt
+
+	// Follow-up chat: stored is the saved analysis the conversation belongs
+	// to and messages its transcript. While asking, chatInput has focus;
+	// while a reply streams, question and reply hold the turn in progress.
+	stored     *db.Analysis
+	messages   []db.AnalysisMessage
+	chatInput  textinput.Model
+	asking     bool
+	chatStream <-chan claude.StreamEvent
+	question   string
+	reply      string
+	chatErr    error
 }
 
 // Messages for analysis flow
 type AnalysisDataFetchedMsg struct {
-	Trades  []db.Trade
-	Markets map[string]*db.Market
+	Trades    []db.Trade
+	Markets   map[string]*db.Market
+	Profile   *db.TraderProfile
+	Snapshots map[string]*db.MarketSnapshot
+}
+
+// AnalysisChunkMsg carries a piece of the thesis as Claude streams it.
+type AnalysisChunkMsg struct {
+	Text   string
+	stream <-chan claude.StreamEvent
 }
 
 type AnalysisCompleteMsg struct {
 	Thesis string
+	// Result holds the model and token usage; nil when not reported.
+	Result    *claude.AnalysisResult
+	Truncated bool
+	stream    <-chan claude.StreamEvent
 }
 
 type AnalysisErrorMsg struct {
-	Err error
+	Err    error
+	stream <-chan claude.StreamEvent
 }
 
 type AnalysisSavedMsg struct {
@@ -101,11 +152,46 @@ type AnalysisCopiedMsg struct{}
 
 type AnalysisRetryMsg struct{}
 
+// AnalysisStoredMsg reports the saved analysis that follow-up questions are
+// attached to, or why it could not be saved.
+type AnalysisStoredMsg struct {
+	Analysis *db.Analysis
+	Err      error
+}
+
+// AnalysisResumedMsg carries a saved analysis and its follow-up conversation.
+type AnalysisResumedMsg struct {
+	Analysis *db.Analysis
+	Messages []db.AnalysisMessage
+}
+
+// ChatChunkMsg carries a piece of a follow-up reply as Claude streams it.
+type ChatChunkMsg struct {
+	Text   string
+	stream <-chan claude.StreamEvent
+}
+
+type ChatReplyMsg struct {
+	Result    *claude.AnalysisResult
+	Truncated bool
+	stream    <-chan claude.StreamEvent
+}
+
+type ChatErrorMsg struct {
+	Err    error
+	stream <-chan claude.StreamEvent
+}
+
 func NewAnalysis(trader *db.Trader, styles Styles, claudeClient *claude.Client) *Analysis {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(styles.Header.GetBackground())
 
+	ti := textinput.New()
+	ti.Placeholder = "Ask a follow-up question..."
+	ti.CharLimit = 1000
+	ti.Width = 60
+
 	return &Analysis{
 		trader:       trader,
 		trades:       nil,
@@ -116,6 +202,7 @@ func NewAnalysis(trader *db.Trader, styles Styles, claudeClient *claude.Client)
 		scrollOffset: 0,
 		spinner:      s,
 		claudeClient: claudeClient,
+		chatInput:    ti,
 	}
 }
 
@@ -131,51 +218,207 @@ func (a *Analysis) FetchData(database *db.DB) tea.Cmd {
 			return AnalysisErrorMsg{Err: fmt.Errorf("no trader selected")}
 		}
 
-		trades, err := database.GetTradesByTrader(a.trader.Address)
+		data, err := claude.LoadTraderData(database, a.trader)
 		if err != nil {
-			return AnalysisErrorMsg{Err: fmt.Errorf("failed to fetch trades: %w", err)}
-		}
-
-		markets := make(map[string]*db.Market)
-		for _, t := range trades {
-			if _, exists := markets[t.MarketID]; !exists {
-				market, err := database.GetMarket(t.MarketID)
-				if err == nil && market != nil {
-					markets[t.MarketID] = market
-				}
-			}
+			return AnalysisErrorMsg{Err: err}
 		}
 
 		return AnalysisDataFetchedMsg{
-			Trades:  trades,
-			Markets: markets,
+			Trades:    data.Trades,
+			Markets:   data.Markets,
+			Profile:   data.Profile,
+			Snapshots: data.Snapshots,
 		}
 	}
 }
 
-// RunAnalysis sends the data to Claude for analysis
+// RunAnalysis starts streaming the analysis from Claude. Chunks arrive as
+// AnalysisChunkMsgs followed by an AnalysisCompleteMsg or AnalysisErrorMsg.
 func (a *Analysis) RunAnalysis() tea.Cmd {
+	if a.claudeClient == nil {
+		return func() tea.Msg { return AnalysisErrorMsg{Err: claude.ErrNoAPIKey} }
+	}
+
+	data := claude.TraderData{
+		Trader:    a.trader,
+		Trades:    a.trades,
+		Markets:   a.markets,
+		Profile:   a.profile,
+		Snapshots: a.snapshots,
+	}
+	if prompt, err := a.claudeClient.BuildPrompt(data); err == nil {
+		a.prompt = prompt
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	a.cancel = cancel
+	a.stream = a.claudeClient.StreamAnalyzeTrader(ctx, data)
+	a.started = time.Now()
+	a.follow = true
+	return waitForStream(a.stream)
+}
+
+// waitForStream turns the next stream event into a message. It returns nil
+// once the stream closes without a final event, which happens on cancel.
+func waitForStream(stream <-chan claude.StreamEvent) tea.Cmd {
 	return func() tea.Msg {
-		if a.claudeClient == nil {
-			return AnalysisErrorMsg{Err: claude.ErrNoAPIKey}
+		ev, ok := <-stream
+		if !ok {
+			return nil
 		}
-
-		data := claude.TraderData{
-			Trader:  a.trader,
-			Trades:  a.trades,
-			Markets: a.markets,
+		switch {
+		case ev.Result != nil:
+			return AnalysisCompleteMsg{
+				Thesis:    ev.Result.Thesis,
+				Result:    ev.Result,
+				Truncated: errors.Is(ev.Err, claude.ErrTokenLimit),
+				stream:    stream,
+			}
+		case ev.Err != nil:
+			return AnalysisErrorMsg{Err: ev.Err, stream: stream}
+		default:
+			return AnalysisChunkMsg{Text: ev.Text, stream: stream}
 		}
+	}
+}
+
+// Cancel aborts a running stream, if any.
+func (a *Analysis) Cancel() {
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+}
+
+// endStream cancels the stream and forgets it, so any of its messages still
+// in flight are ignored.
+func (a *Analysis) endStream() {
+	a.Cancel()
+	a.stream = nil
+}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-		defer cancel()
+// current reports whether a message belongs to the live stream, or to no
+// stream at all, rather than to one that has ended or been cancelled.
+func (a *Analysis) current(stream <-chan claude.StreamEvent) bool {
+	return stream == a.stream
+}
 
-		result, err := a.claudeClient.AnalyzeTrader(ctx, data)
+// StoreAnalysis saves a finished analysis so follow-up questions can be
+// attached to it.
+func StoreAnalysis(database *db.DB, analysis *db.Analysis) tea.Cmd {
+	return func() tea.Msg {
+		if err := database.SaveAnalysis(analysis); err != nil {
+			return AnalysisStoredMsg{Err: err}
+		}
+		return AnalysisStoredMsg{Analysis: analysis}
+	}
+}
+
+// LoadLatest loads the trader's latest saved analysis and its follow-up
+// conversation instead of writing a new analysis. Analyses saved without
+// their prompt are replayed against the trader's current data.
+func (a *Analysis) LoadLatest(database *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		if a.trader == nil {
+			return AnalysisErrorMsg{Err: fmt.Errorf("no trader selected")}
+		}
+		analysis, err := database.GetAnalysisByTrader(a.trader.Address)
 		if err != nil {
 			return AnalysisErrorMsg{Err: err}
 		}
+		if analysis == nil {
+			return AnalysisErrorMsg{Err: fmt.Errorf("no saved analysis for this trader; press 'r' to write one")}
+		}
+		messages, err := database.GetAnalysisMessages(analysis.ID)
+		if err != nil {
+			return AnalysisErrorMsg{Err: err}
+		}
+		if analysis.Prompt == "" && a.claudeClient != nil {
+			data, err := claude.LoadTraderData(database, a.trader)
+			if err != nil {
+				return AnalysisErrorMsg{Err: err}
+			}
+			prompt, err := a.claudeClient.BuildPrompt(data)
+			if err != nil {
+				return AnalysisErrorMsg{Err: err}
+			}
+			analysis.Prompt = prompt.Text
+		}
+		return AnalysisResumedMsg{Analysis: analysis, Messages: messages}
+	}
+}
 
-		return AnalysisCompleteMsg{Thesis: result.Thesis}
+// Ask streams the reply to a follow-up question. Chunks arrive as
+// ChatChunkMsgs followed by a ChatReplyMsg or ChatErrorMsg.
+func (a *Analysis) Ask(question string) tea.Cmd {
+	if a.claudeClient == nil {
+		return func() tea.Msg { return ChatErrorMsg{Err: claude.ErrNoAPIKey} }
 	}
+
+	conv := claude.Conversation{Prompt: a.stored.Prompt, Thesis: a.stored.Thesis, Messages: a.messages}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	a.cancel = cancel
+	a.chatStream = a.claudeClient.StreamChat(ctx, conv, question)
+	a.question = question
+	a.reply = ""
+	a.chatErr = nil
+	a.follow = true
+	return tea.Batch(waitForChat(a.chatStream), a.spinner.Tick)
+}
+
+// waitForChat is waitForStream for follow-up replies.
+func waitForChat(stream <-chan claude.StreamEvent) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-stream
+		if !ok {
+			return nil
+		}
+		switch {
+		case ev.Result != nil:
+			return ChatReplyMsg{
+				Result:    ev.Result,
+				Truncated: errors.Is(ev.Err, claude.ErrTokenLimit),
+				stream:    stream,
+			}
+		case ev.Err != nil:
+			return ChatErrorMsg{Err: ev.Err, stream: stream}
+		default:
+			return ChatChunkMsg{Text: ev.Text, stream: stream}
+		}
+	}
+}
+
+// endChat cancels the reply stream and drops the turn in progress.
+func (a *Analysis) endChat() {
+	a.Cancel()
+	a.chatStream = nil
+	a.question = ""
+	a.reply = ""
+}
+
+// currentChat reports whether a chat message belongs to the live reply
+// stream, or to no stream at all.
+func (a *Analysis) currentChat(stream <-chan claude.StreamEvent) bool {
+	return stream == a.chatStream
+}
+
+// SaveLastTurn saves the latest question and reply with the stored analysis.
+func (a *Analysis) SaveLastTurn(database *db.DB) tea.Cmd {
+	if len(a.messages) < 2 {
+		return nil
+	}
+	asked, reply := a.messages[len(a.messages)-2], a.messages[len(a.messages)-1]
+	return func() tea.Msg {
+		if err := database.SaveAnalysisMessages(&asked, &reply); err != nil {
+			return ChatErrorMsg{Err: fmt.Errorf("failed to save follow-up: %w", err)}
+		}
+		return nil
+	}
+}
+
+// IsAsking reports whether the follow-up question input has focus.
+func (a *Analysis) IsAsking() bool {
+	return a.asking
 }
 
 // SaveThesis saves the thesis to a markdown file
@@ -185,28 +428,13 @@ func (a *Analysis) SaveThesis() tea.Cmd {
 			return AnalysisErrorMsg{Err: fmt.Errorf("no thesis to save")}
 		}
 
-		// Create exports directory if it doesn't exist
-		exportDir := "exports"
-		if err := os.MkdirAll(exportDir, 0755); err != nil {
-			return AnalysisErrorMsg{Err: fmt.Errorf("failed to create exports directory: %w", err)}
+		analysis := &db.Analysis{Thesis: a.thesis}
+		if a.result != nil {
+			analysis.Model = a.result.Model
+			analysis.Summary = a.result.Summary
 		}
-
-		// Generate filename with trader address and timestamp
-		shortAddr := a.trader.Address
-		if len(shortAddr) > 10 {
-			shortAddr = shortAddr[:10]
-		}
-		filename := fmt.Sprintf("thesis_%s_%s.md", shortAddr, time.Now().Format("20060102_150405"))
-		path := filepath.Join(exportDir, filename)
-
-		// Build markdown content
-		var content strings.Builder
-		content.WriteString(fmt.Sprintf("# Trading Thesis: %s\n\n", a.trader.Address))
-		content.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
-		content.WriteString("---\n\n")
-		content.WriteString(a.thesis)
-
-		if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		path, err := export.NewExporter("exports").ExportAnalysisMarkdown(a.trader, analysis, "")
+		if err != nil {
 			return AnalysisErrorMsg{Err: fmt.Errorf("failed to save thesis: %w", err)}
 		}
 
@@ -224,7 +452,7 @@ func (a *Analysis) Update(msg tea.Msg) (*Analysis, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case spinner.TickMsg:
-		if a.state == analysisStateFetching || a.state == analysisStateAnalyzing {
+		if a.state == analysisStateFetching || a.state == analysisStateAnalyzing || a.chatStream != nil {
 			a.spinner, cmd = a.spinner.Update(msg)
 			cmds = append(cmds, cmd)
 		}
@@ -232,31 +460,129 @@ func (a *Analysis) Update(msg tea.Msg) (*Analysis, tea.Cmd) {
 	case AnalysisDataFetchedMsg:
 		a.trades = msg.Trades
 		a.markets = msg.Markets
+		a.profile = msg.Profile
+		a.snapshots = msg.Snapshots
 		a.state = analysisStateAnalyzing
 		cmds = append(cmds, a.RunAnalysis())
 		cmds = append(cmds, a.spinner.Tick)
 
+	case AnalysisChunkMsg:
+		if !a.current(msg.stream) || a.state != analysisStateAnalyzing {
+			break
+		}
+		a.thesis += msg.Text
+		cmds = append(cmds, waitForStream(a.stream))
+
 	case AnalysisCompleteMsg:
+		if !a.current(msg.stream) || a.state == analysisStateCancelled {
+			break
+		}
+		a.endStream()
 		a.thesis = msg.Thesis
+		a.result = msg.Result
+		a.truncated = msg.Truncated
 		a.state = analysisStateComplete
-		a.scrollOffset = 0
+		if !a.started.IsZero() {
+			a.elapsed = time.Since(a.started)
+		}
 
 	case AnalysisErrorMsg:
+		if !a.current(msg.stream) || a.state == analysisStateCancelled {
+			break
+		}
+		a.endStream()
 		a.err = msg.Err
 		a.state = analysisStateError
 
 	case AnalysisSavedMsg:
 		a.savedPath = msg.Path
 
+	case AnalysisStoredMsg:
+		a.stored = msg.Analysis
+		if msg.Err != nil {
+			a.chatErr = fmt.Errorf("failed to save analysis; follow-ups are unavailable: %w", msg.Err)
+		}
+
+	case AnalysisResumedMsg:
+		a.stored = msg.Analysis
+		a.messages = msg.Messages
+		a.thesis = msg.Analysis.Thesis
+		a.result = &claude.AnalysisResult{
+			Thesis:       msg.Analysis.Thesis,
+			Model:        msg.Analysis.Model,
+			InputTokens:  msg.Analysis.InputTokens,
+			OutputTokens: msg.Analysis.OutputTokens,
+			CostUSD:      msg.Analysis.CostUSD,
+			CreatedAt:    msg.Analysis.CreatedAt,
+			Summary:      msg.Analysis.Summary,
+		}
+		a.state = analysisStateComplete
+
+	case ChatChunkMsg:
+		if !a.currentChat(msg.stream) || a.chatStream == nil {
+			break
+		}
+		a.reply += msg.Text
+		cmds = append(cmds, waitForChat(a.chatStream))
+
+	case ChatReplyMsg:
+		if !a.currentChat(msg.stream) || a.chatStream == nil {
+			break
+		}
+		asked, reply := msg.Result.Messages(a.stored.ID, a.question)
+		a.messages = append(a.messages, *asked, *reply)
+		if msg.Truncated {
+			a.chatErr = claude.ErrTokenLimit
+		}
+		a.endChat()
+
+	case ChatErrorMsg:
+		if !a.currentChat(msg.stream) {
+			break
+		}
+		a.endChat()
+		a.chatErr = msg.Err
+
 	case AnalysisCopiedMsg:
 		a.copied = true
 
 	case tea.KeyMsg:
+		if a.asking {
+			switch {
+			case msg.Type == tea.KeyEsc:
+				a.asking = false
+				a.chatInput.Blur()
+			case key.Matches(msg, analysisKeys.Send):
+				question := strings.TrimSpace(a.chatInput.Value())
+				if question == "" {
+					return a, nil
+				}
+				a.asking = false
+				a.chatInput.Blur()
+				a.chatInput.Reset()
+				return a, a.Ask(question)
+			default:
+				a.chatInput, cmd = a.chatInput.Update(msg)
+				return a, cmd
+			}
+			return a, nil
+		}
+
 		switch {
 		case key.Matches(msg, analysisKeys.Back):
+			if a.chatStream != nil {
+				a.endChat()
+				return a, nil
+			}
+			if a.state == analysisStateAnalyzing && a.stream != nil {
+				a.endStream()
+				a.state = analysisStateCancelled
+				return a, nil
+			}
 			return a, func() tea.Msg { return GoBackMsg{} }
 
 		case key.Matches(msg, analysisKeys.Up):
+			a.follow = false
 			if a.scrollOffset > 0 {
 				a.scrollOffset--
 			}
@@ -276,13 +602,30 @@ func (a *Analysis) Update(msg tea.Msg) (*Analysis, tea.Cmd) {
 				// For now we just set a flag to show the user the action was attempted
 			}
 
+		case key.Matches(msg, analysisKeys.Ask):
+			if a.state == analysisStateComplete && a.stored != nil && a.chatStream == nil {
+				a.asking = true
+				a.follow = true
+				a.chatErr = nil
+				a.chatInput.Focus()
+				return a, textinput.Blink
+			}
+
 		case key.Matches(msg, analysisKeys.Retry):
-			if a.state == analysisStateError || a.state == analysisStateComplete {
+			if a.chatStream != nil {
+				break
+			}
+			if a.state == analysisStateError || a.state == analysisStateComplete || a.state == analysisStateCancelled {
 				a.state = analysisStateFetching
 				a.thesis = ""
 				a.err = nil
 				a.savedPath = ""
 				a.copied = false
+				a.result = nil
+				a.truncated = false
+				a.stored = nil
+				a.messages = nil
+				a.chatErr = nil
 				return a, func() tea.Msg { return AnalysisRetryMsg{} }
 			}
 		}
@@ -305,11 +648,38 @@ func (a *Analysis) View() string {
 	case analysisStateFetching:
 		sections = append(sections, a.renderProgress("Fetching trader data..."))
 	case analysisStateAnalyzing:
-		sections = append(sections, a.renderProgress("Analyzing with Claude AI..."))
+		if a.thesis == "" {
+			message := "Analyzing with Claude AI..."
+			if a.prompt != nil {
+				message = fmt.Sprintf("Analyzing with Claude AI (~%d prompt tokens, %d of %d trades)...",
+					a.prompt.EstimatedTokens, a.prompt.SampledTrades, a.prompt.TotalTrades)
+			}
+			sections = append(sections, a.renderProgress(message))
+		} else {
+			sections = append(sections, a.renderThesis())
+			sections = append(sections, a.spinner.View()+a.styles.Subtle.Render(" Writing... (esc to cancel)"))
+		}
 	case analysisStateError:
 		sections = append(sections, a.renderError())
 	case analysisStateComplete:
+		if a.result != nil && a.result.Summary != nil {
+			sections = append(sections, a.renderSummary(a.result.Summary))
+		}
 		sections = append(sections, a.renderThesis())
+		if a.truncated {
+			sections = append(sections, a.styles.Subtle.Render("Response was cut off at the token limit."))
+		}
+		if usage := a.renderUsage(); usage != "" {
+			sections = append(sections, usage)
+		}
+		if conversation := a.renderConversation(); conversation != "" {
+			sections = append(sections, conversation)
+		}
+	case analysisStateCancelled:
+		if a.thesis != "" {
+			sections = append(sections, a.renderThesis())
+		}
+		sections = append(sections, a.styles.Subtle.Render("Analysis cancelled. Press 'r' to retry or 'esc' to go back."))
 	}
 
 	// Status messages
@@ -338,6 +708,20 @@ func (a *Analysis) View() string {
 		visibleHeight = 20
 	}
 
+	// While streaming, keep the newest text in view until the user scrolls up;
+	// scrolling back down to the end resumes following.
+	maxOffset := len(lines) - visibleHeight
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if a.state == analysisStateAnalyzing || a.chatStream != nil || a.asking {
+		if a.follow {
+			a.scrollOffset = maxOffset
+		} else if a.scrollOffset >= maxOffset {
+			a.follow = true
+		}
+	}
+
 	endIdx := a.scrollOffset + visibleHeight
 	if endIdx > len(lines) {
 		endIdx = len(lines)
@@ -382,6 +766,64 @@ func (a *Analysis) renderProgress(message string) string {
 	)
 }
 
+// renderUsage summarizes the model, token usage, cost and duration of a finished analysis.
+func (a *Analysis) renderUsage() string {
+	if a.result == nil {
+		return ""
+	}
+	parts := []string{}
+	if a.result.Model != "" {
+		parts = append(parts, a.result.Model)
+	}
+	parts = append(parts, fmt.Sprintf("%d input / %d output tokens", a.result.InputTokens, a.result.OutputTokens))
+	if a.result.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.4f", a.result.CostUSD))
+	}
+	if a.elapsed > 0 {
+		parts = append(parts, a.elapsed.Round(100*time.Millisecond).String())
+	}
+	return a.styles.Subtle.Render(strings.Join(parts, " · "))
+}
+
+// renderConversation shows the follow-up questions and replies below the
+// thesis, the reply being streamed and the question input.
+func (a *Analysis) renderConversation() string {
+	you := lipgloss.NewStyle().Bold(true).Foreground(a.styles.Highlight.GetForeground())
+	assistant := lipgloss.NewStyle().Bold(true).Foreground(a.styles.Header.GetBackground())
+
+	var lines []string
+	turn := func(role, content string) {
+		if role == db.MessageRoleUser {
+			lines = append(lines, "", you.Render("You: ")+content)
+			return
+		}
+		lines = append(lines, "", assistant.Render("Claude:"), renderMarkdown(a.styles, content))
+	}
+	for _, m := range a.messages {
+		turn(m.Role, m.Content)
+	}
+	if a.chatStream != nil {
+		turn(db.MessageRoleUser, a.question)
+		if a.reply != "" {
+			turn(db.MessageRoleAssistant, a.reply)
+		}
+		lines = append(lines, "", a.spinner.View()+a.styles.Subtle.Render(" Replying... (esc to stop)"))
+	}
+	if a.chatErr != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555"))
+		lines = append(lines, "", errorStyle.Render(fmt.Sprintf("Follow-up: %v", a.chatErr)))
+	}
+	if a.asking {
+		inputBox := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(a.styles.Highlight.GetForeground()).
+			Padding(0, 1).
+			Width(a.width - 6)
+		lines = append(lines, "", inputBox.Render(a.chatInput.View()))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (a *Analysis) renderError() string {
 	errorBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -413,6 +855,43 @@ func (a *Analysis) renderError() string {
 	)
 }
 
+// renderSummary shows Claude's structured conclusions above the narrative.
+func (a *Analysis) renderSummary(s *db.ThesisSummary) string {
+	label := lipgloss.NewStyle().Bold(true).Foreground(a.styles.Header.GetBackground())
+	field := func(name, value string) string {
+		return label.Render(name+": ") + value
+	}
+
+	lines := []string{
+		strings.Join([]string{
+			field("Archetype", s.Archetype),
+			field("Risk", fmt.Sprintf("%d/10", s.RiskScore)),
+			field("Timing", s.TimingStyle),
+			field("Edge", s.EdgeConfidence),
+			field("Copyability", s.Copyability),
+		}, "  "),
+		field("Focus", strings.Join(s.MarketFocus, ", ")),
+	}
+	if len(s.KeyRisks) > 0 {
+		lines = append(lines, label.Render("Key risks:"))
+		for _, risk := range s.KeyRisks {
+			lines = append(lines, a.styles.Highlight.Render("- ")+risk)
+		}
+	}
+
+	summaryBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(a.styles.Header.GetBackground()).
+		Padding(0, 2).
+		Width(a.width - 6)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		summaryBox.Render(strings.Join(lines, "\n")),
+	)
+}
+
 func (a *Analysis) renderThesis() string {
 	thesisBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -432,12 +911,17 @@ func (a *Analysis) renderThesis() string {
 
 // renderMarkdown applies basic markdown styling to the thesis content
 func (a *Analysis) renderMarkdown(content string) string {
+	return renderMarkdown(a.styles, content)
+}
+
+// renderMarkdown applies basic markdown styling to Claude's output.
+func renderMarkdown(styles Styles, content string) string {
 	lines := strings.Split(content, "\n")
 	var result []string
 
-	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(a.styles.Header.GetBackground())
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Header.GetBackground())
 	boldStyle := lipgloss.NewStyle().Bold(true)
-	listStyle := a.styles.Highlight
+	listStyle := styles.Highlight
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -472,7 +956,7 @@ func (a *Analysis) renderMarkdown(content string) string {
 		}
 
 		// Bold text (simple pattern: **text**)
-		processed := a.processBoldText(line)
+		processed := processBoldText(line)
 
 		result = append(result, processed)
 	}
@@ -481,7 +965,7 @@ func (a *Analysis) renderMarkdown(content string) string {
 }
 
 // processBoldText handles **bold** text patterns
-func (a *Analysis) processBoldText(line string) string {
+func processBoldText(line string) string {
 	boldStyle := lipgloss.NewStyle().Bold(true)
 	result := line
 
@@ -505,11 +989,23 @@ func (a *Analysis) processBoldText(line string) string {
 
 func (a *Analysis) HelpText() string {
 	switch a.state {
-	case analysisStateFetching, analysisStateAnalyzing:
+	case analysisStateFetching:
 		return "esc: cancel"
+	case analysisStateAnalyzing:
+		return "j/k: scroll | esc: cancel"
+	case analysisStateCancelled:
+		return "r: retry | esc: back"
 	case analysisStateError:
 		return "r: retry | esc: back"
 	case analysisStateComplete:
+		switch {
+		case a.asking:
+			return "enter: send | esc: cancel"
+		case a.chatStream != nil:
+			return "j/k: scroll | esc: stop reply"
+		case a.stored != nil:
+			return "s: save | f: follow up | r: retry | j/k: scroll | esc: back"
+		}
 		return "s: save | r: retry | j/k: scroll | esc: back"
 	default:
 		return "esc: back"
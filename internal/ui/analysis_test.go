@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -299,3 +301,232 @@ func TestAnalysisGetThesis(t *testing.T) {
 
 	assert.Equal(t, "Test thesis content", analysis.GetThesis())
 }
+
+func TestAnalysisStreaming(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(80, 24)
+
+	stream := make(chan claude.StreamEvent, 1)
+	analysis.state = analysisStateAnalyzing
+	analysis.stream = stream
+	analysis.follow = true
+	analysis.started = time.Now()
+
+	analysis, cmd := analysis.Update(AnalysisChunkMsg{Text: "## Strategy\n\n", stream: stream})
+	assert.NotNil(t, cmd, "a chunk should wait for the next one")
+	analysis, _ = analysis.Update(AnalysisChunkMsg{Text: "Buys early.", stream: stream})
+	assert.Equal(t, "## Strategy\n\nBuys early.", analysis.thesis)
+	assert.Contains(t, analysis.View(), "Buys early.")
+	assert.Contains(t, analysis.View(), "Writing")
+
+	// The command returned for a chunk reads the next event off the stream.
+	stream <- claude.StreamEvent{Text: " Holds late."}
+	msg := waitForStream(stream)()
+	chunk, ok := msg.(AnalysisChunkMsg)
+	assert.True(t, ok)
+	assert.Equal(t, " Holds late.", chunk.Text)
+
+	result := &claude.AnalysisResult{Thesis: "## Strategy\n\nBuys early. Holds late.", Model: "claude-test", InputTokens: 1200, OutputTokens: 340}
+	stream <- claude.StreamEvent{Result: result, Err: claude.ErrTokenLimit}
+	complete, ok := waitForStream(stream)().(AnalysisCompleteMsg)
+	assert.True(t, ok)
+	assert.True(t, complete.Truncated)
+
+	analysis, _ = analysis.Update(complete)
+	assert.Equal(t, analysisStateComplete, analysis.state)
+	assert.Equal(t, result.Thesis, analysis.thesis)
+	assert.Nil(t, analysis.stream)
+	view := analysis.View()
+	assert.Contains(t, view, "1200 input / 340 output tokens")
+	assert.Contains(t, view, "claude-test")
+	assert.Contains(t, view, "token limit")
+
+	close(stream)
+	assert.Nil(t, waitForStream(stream)(), "a closed stream produces no message")
+}
+
+func TestAnalysisStreaming_EscCancels(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(80, 24)
+
+	stream := make(chan claude.StreamEvent)
+	cancelled := false
+	analysis.state = analysisStateAnalyzing
+	analysis.stream = stream
+	analysis.cancel = func() { cancelled = true }
+	analysis, _ = analysis.Update(AnalysisChunkMsg{Text: "Partial", stream: stream})
+
+	analysis, cmd := analysis.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd, "esc while streaming cancels instead of going back")
+	assert.True(t, cancelled)
+	assert.Equal(t, analysisStateCancelled, analysis.state)
+	assert.Contains(t, analysis.View(), "Partial")
+	assert.Contains(t, analysis.View(), "cancelled")
+	assert.Contains(t, analysis.HelpText(), "retry")
+
+	// Late messages from the cancelled stream are ignored.
+	analysis, _ = analysis.Update(AnalysisChunkMsg{Text: " more", stream: stream})
+	analysis, _ = analysis.Update(AnalysisErrorMsg{Err: context.Canceled, stream: stream})
+	assert.Equal(t, "Partial", analysis.thesis)
+	assert.Equal(t, analysisStateCancelled, analysis.state)
+
+	// A second esc goes back.
+	_, cmd = analysis.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.NotNil(t, cmd)
+	_, ok := cmd().(GoBackMsg)
+	assert.True(t, ok)
+}
+
+func TestAnalysisStreaming_FollowsOutput(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(80, 12)
+
+	stream := make(chan claude.StreamEvent)
+	analysis.state = analysisStateAnalyzing
+	analysis.stream = stream
+	analysis.follow = true
+	for i := 1; i <= 30; i++ {
+		analysis, _ = analysis.Update(AnalysisChunkMsg{Text: fmt.Sprintf("line %d\n", i), stream: stream})
+	}
+	assert.Contains(t, analysis.View(), "line 30")
+
+	// Scrolling up stops following.
+	for i := 0; i < 5; i++ {
+		analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyUp})
+	}
+	analysis, _ = analysis.Update(AnalysisChunkMsg{Text: "line 31\n", stream: stream})
+	assert.NotContains(t, analysis.View(), "line 31")
+}
+
+func TestAnalysisView_Summary(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(120, 60)
+	analysis.state = analysisStateAnalyzing
+
+	analysis, _ = analysis.Update(AnalysisCompleteMsg{
+		Thesis: "Late momentum trader.",
+		Result: &claude.AnalysisResult{
+			Thesis: "Late momentum trader.",
+			Summary: &db.ThesisSummary{
+				Archetype:      "momentum",
+				MarketFocus:    []string{"politics", "crypto"},
+				RiskScore:      7,
+				TimingStyle:    "late",
+				EdgeConfidence: "medium",
+				Copyability:    "high",
+				KeyRisks:       []string{"Thin books near resolution"},
+			},
+		},
+	})
+
+	view := analysis.View()
+	assert.Contains(t, view, "momentum")
+	assert.Contains(t, view, "7/10")
+	assert.Contains(t, view, "politics, crypto")
+	assert.Contains(t, view, "Thin books near resolution")
+	assert.Contains(t, view, "Late momentum trader.")
+}
+
+func TestAnalysisView_PromptEstimate(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(120, 40)
+	analysis.state = analysisStateAnalyzing
+	analysis.prompt = &claude.Prompt{EstimatedTokens: 8200, Budget: 12000, SampledTrades: 120, TotalTrades: 900}
+
+	assert.Contains(t, analysis.View(), "~8200 prompt tokens, 120 of 900 trades")
+}
+
+func TestAnalysisChat(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(120, 60)
+
+	// Follow-ups need a saved analysis to attach to.
+	analysis, _ = analysis.Update(AnalysisCompleteMsg{Thesis: "Late momentum trader."})
+	analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	assert.False(t, analysis.IsAsking())
+
+	analysis, _ = analysis.Update(AnalysisStoredMsg{Analysis: &db.Analysis{ID: 7, Thesis: "Late momentum trader.", Prompt: "the prompt"}})
+	assert.Contains(t, analysis.HelpText(), "f: follow up")
+	analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	assert.True(t, analysis.IsAsking())
+	assert.Equal(t, "enter: send | esc: cancel", analysis.HelpText())
+
+	// Keys go to the input while asking, including ones bound to actions.
+	analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Why sports?")})
+	analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	assert.Equal(t, "Why sports?s", analysis.chatInput.Value())
+	assert.Empty(t, analysis.savedPath)
+
+	analysis, _ = analysis.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, analysis.IsAsking())
+
+	// Simulate a reply streaming in.
+	stream := make(chan claude.StreamEvent)
+	analysis.chatStream = stream
+	analysis.question = "Why sports?"
+	analysis, cmd := analysis.Update(ChatChunkMsg{Text: "Better edges.", stream: stream})
+	assert.NotNil(t, cmd)
+	assert.Contains(t, analysis.View(), "Replying")
+	assert.Contains(t, analysis.View(), "Better edges.")
+
+	analysis, _ = analysis.Update(ChatReplyMsg{Result: &claude.AnalysisResult{Thesis: "Better edges.", OutputTokens: 12}, stream: stream})
+	assert.Nil(t, analysis.chatStream)
+	assert.Len(t, analysis.messages, 2)
+	assert.Equal(t, int64(7), analysis.messages[0].AnalysisID)
+	assert.Equal(t, db.MessageRoleUser, analysis.messages[0].Role)
+	assert.Equal(t, "Why sports?", analysis.messages[0].Content)
+	assert.Equal(t, int64(12), analysis.messages[1].OutputTokens)
+	view := analysis.View()
+	assert.Contains(t, view, "You: Why sports?")
+	assert.Contains(t, view, "Better edges.")
+	assert.NotContains(t, view, "Replying")
+
+	// Late messages from a finished reply are ignored.
+	analysis, _ = analysis.Update(ChatChunkMsg{Text: " more", stream: stream})
+	assert.Len(t, analysis.messages, 2)
+}
+
+func TestAnalysisChat_EscStopsReply(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.state = analysisStateComplete
+	analysis.stored = &db.Analysis{ID: 1}
+
+	stream := make(chan claude.StreamEvent)
+	cancelled := false
+	analysis.chatStream = stream
+	analysis.cancel = func() { cancelled = true }
+	assert.Equal(t, "j/k: scroll | esc: stop reply", analysis.HelpText())
+
+	analysis, cmd := analysis.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd, "esc while replying stops the reply instead of going back")
+	assert.True(t, cancelled)
+	assert.Nil(t, analysis.chatStream)
+	assert.Empty(t, analysis.messages)
+}
+
+func TestAnalysisResumed(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234567890abcdef1234567890abcdef12345678"}
+	analysis := NewAnalysis(trader, DefaultStyles(), nil)
+	analysis.SetSize(120, 60)
+
+	analysis, _ = analysis.Update(AnalysisResumedMsg{
+		Analysis: &db.Analysis{ID: 3, Thesis: "Late momentum trader.", Model: "claude-test", Prompt: "the prompt"},
+		Messages: []db.AnalysisMessage{
+			{AnalysisID: 3, Role: db.MessageRoleUser, Content: "Is it copyable?"},
+			{AnalysisID: 3, Role: db.MessageRoleAssistant, Content: "Only with fast fills."},
+		},
+	})
+	assert.Equal(t, analysisStateComplete, analysis.state)
+	view := analysis.View()
+	assert.Contains(t, view, "Late momentum trader.")
+	assert.Contains(t, view, "claude-test")
+	assert.Contains(t, view, "You: Is it copyable?")
+	assert.Contains(t, view, "Only with fast fills.")
+}
@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+	"polytracker/internal/export"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type comparisonState int
+
+const (
+	comparisonStateRunning comparisonState = iota
+	comparisonStateComplete
+	comparisonStateError
+)
+
+// Comparison shows Claude's comparison of the traders marked on the leaderboard.
+// The comparison is saved to the database as soon as it arrives; 's' exports
+// it to markdown.
+type Comparison struct {
+	traders      []db.Trader
+	state        comparisonState
+	styles       Styles
+	width        int
+	height       int
+	scrollOffset int
+	spinner      spinner.Model
+	err          error
+	savedPath    string
+	claudeClient *claude.Client
+	cancel       context.CancelFunc
+	started      time.Time
+	elapsed      time.Duration
+
+	comparison *db.Comparison
+	truncated  bool
+	// saveErr is set when the comparison could not be stored.
+	saveErr error
+}
+
+// ComparisonCompleteMsg carries a finished comparison.
+type ComparisonCompleteMsg struct {
+	Comparison *db.Comparison
+	Truncated  bool
+	SaveErr    error
+	source     *Comparison
+}
+
+type ComparisonErrorMsg struct {
+	Err    error
+	source *Comparison
+}
+
+type ComparisonSavedMsg struct {
+	Path string
+}
+
+func NewComparison(traders []db.Trader, styles Styles, claudeClient *claude.Client) *Comparison {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.Header.GetBackground())
+
+	return &Comparison{
+		traders:      traders,
+		state:        comparisonStateRunning,
+		styles:       styles,
+		spinner:      s,
+		claudeClient: claudeClient,
+	}
+}
+
+func (c *Comparison) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+func (c *Comparison) Init() tea.Cmd {
+	return c.spinner.Tick
+}
+
+// Run loads every trader's history, asks Claude for the comparison and saves
+// it, linked to all the traders.
+func (c *Comparison) Run(database *db.DB) tea.Cmd {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	c.cancel = cancel
+	c.started = time.Now()
+
+	return func() tea.Msg {
+		if c.claudeClient == nil {
+			return ComparisonErrorMsg{Err: claude.ErrNoAPIKey, source: c}
+		}
+		if database == nil {
+			return ComparisonErrorMsg{Err: fmt.Errorf("no database connection"), source: c}
+		}
+
+		data := make([]claude.TraderData, len(c.traders))
+		ids := make([]string, len(c.traders))
+		for i := range c.traders {
+			var err error
+			if data[i], err = claude.LoadTraderData(database, &c.traders[i]); err != nil {
+				return ComparisonErrorMsg{Err: err, source: c}
+			}
+			ids[i] = c.traders[i].Address
+		}
+
+		result, err := c.claudeClient.CompareTraders(ctx, data)
+		if result == nil {
+			return ComparisonErrorMsg{Err: err, source: c}
+		}
+
+		comparison := result.Comparison(ids)
+		saveErr := database.SaveComparison(comparison)
+		return ComparisonCompleteMsg{
+			Comparison: comparison,
+			Truncated:  errors.Is(err, claude.ErrTokenLimit),
+			SaveErr:    saveErr,
+			source:     c,
+		}
+	}
+}
+
+// Cancel aborts a running comparison, if any.
+func (c *Comparison) Cancel() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// Export writes the comparison to a markdown file.
+func (c *Comparison) Export() tea.Cmd {
+	return func() tea.Msg {
+		traders := make([]*db.Trader, len(c.traders))
+		for i := range c.traders {
+			traders[i] = &c.traders[i]
+		}
+		path, err := export.NewExporter("exports").ExportComparisonMarkdown(traders, c.comparison, "")
+		if err != nil {
+			return ComparisonErrorMsg{Err: fmt.Errorf("failed to save comparison: %w", err), source: c}
+		}
+		return ComparisonSavedMsg{Path: path}
+	}
+}
+
+func (c *Comparison) Update(msg tea.Msg) (*Comparison, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if c.state == comparisonStateRunning {
+			c.spinner, cmd = c.spinner.Update(msg)
+			return c, cmd
+		}
+
+	case ComparisonCompleteMsg:
+		if msg.source != c {
+			break
+		}
+		c.Cancel()
+		c.comparison = msg.Comparison
+		c.truncated = msg.Truncated
+		c.saveErr = msg.SaveErr
+		c.state = comparisonStateComplete
+		c.elapsed = time.Since(c.started)
+
+	case ComparisonErrorMsg:
+		if msg.source != c {
+			break
+		}
+		c.Cancel()
+		c.err = msg.Err
+		c.state = comparisonStateError
+
+	case ComparisonSavedMsg:
+		c.savedPath = msg.Path
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, analysisKeys.Back):
+			c.Cancel()
+			return c, func() tea.Msg { return GoBackMsg{} }
+
+		case key.Matches(msg, analysisKeys.Up):
+			if c.scrollOffset > 0 {
+				c.scrollOffset--
+			}
+
+		case key.Matches(msg, analysisKeys.Down):
+			c.scrollOffset++
+
+		case key.Matches(msg, analysisKeys.Save):
+			if c.state == comparisonStateComplete {
+				return c, c.Export()
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Comparison) View() string {
+	sections := []string{c.renderHeader()}
+
+	switch c.state {
+	case comparisonStateRunning:
+		sections = append(sections, "", c.spinner.View()+fmt.Sprintf(" Comparing %d traders with Claude AI...", len(c.traders)))
+	case comparisonStateError:
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555"))
+		errMsg := "Unknown error"
+		if c.err != nil {
+			errMsg = c.err.Error()
+		}
+		sections = append(sections, "", errStyle.Bold(true).Render("Error"), errStyle.Render(errMsg))
+	case comparisonStateComplete:
+		box := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(c.styles.Header.GetBackground()).
+			Padding(1, 2).
+			Width(c.width - 6)
+		sections = append(sections, "", box.Render(renderMarkdown(c.styles, c.comparison.Thesis)))
+		if c.truncated {
+			sections = append(sections, c.styles.Subtle.Render("Response was cut off at the token limit."))
+		}
+		sections = append(sections, c.renderUsage())
+		if c.saveErr != nil {
+			sections = append(sections, c.styles.Subtle.Render(fmt.Sprintf("Warning: failed to save comparison: %v", c.saveErr)))
+		}
+	}
+
+	if c.savedPath != "" {
+		sections = append(sections, "", c.styles.Highlight.Render(fmt.Sprintf("Saved to: %s", c.savedPath)))
+	}
+
+	lines := strings.Split(lipgloss.JoinVertical(lipgloss.Left, sections...), "\n")
+	if c.scrollOffset >= len(lines) {
+		c.scrollOffset = len(lines) - 1
+	}
+	if c.scrollOffset < 0 {
+		c.scrollOffset = 0
+	}
+	visibleHeight := c.height - 4
+	if visibleHeight < 1 {
+		visibleHeight = 20
+	}
+	endIdx := c.scrollOffset + visibleHeight
+	if endIdx > len(lines) {
+		endIdx = len(lines)
+	}
+	return strings.Join(lines[c.scrollOffset:endIdx], "\n")
+}
+
+func (c *Comparison) renderHeader() string {
+	names := make([]string, len(c.traders))
+	for i, t := range c.traders {
+		names[i] = t.Username
+		if names[i] == "" {
+			names[i] = t.Address
+			if len(names[i]) > 12 {
+				names[i] = names[i][:6] + "..." + names[i][len(names[i])-4:]
+			}
+		}
+	}
+	return c.styles.Header.Render(fmt.Sprintf(" COMPARISON: %s ", strings.Join(names, " vs ")))
+}
+
+// renderUsage summarizes the model, token usage, cost and duration of the comparison.
+func (c *Comparison) renderUsage() string {
+	parts := []string{}
+	if c.comparison.Model != "" {
+		parts = append(parts, c.comparison.Model)
+	}
+	parts = append(parts, fmt.Sprintf("%d input / %d output tokens", c.comparison.InputTokens, c.comparison.OutputTokens))
+	if c.comparison.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.4f", c.comparison.CostUSD))
+	}
+	if c.elapsed > 0 {
+		parts = append(parts, c.elapsed.Round(100*time.Millisecond).String())
+	}
+	return c.styles.Subtle.Render(strings.Join(parts, " · "))
+}
+
+func (c *Comparison) HelpText() string {
+	switch c.state {
+	case comparisonStateRunning:
+		return "esc: cancel"
+	case comparisonStateComplete:
+		return "s: save | j/k: scroll | esc: back"
+	default:
+		return "esc: back"
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func comparisonTraders() []db.Trader {
+	return []db.Trader{
+		{Address: "0x1234567890abcdef1234567890abcdef12345678", Username: "alice"},
+		{Address: "0xabcdef1234567890abcdef1234567890abcdef12"},
+	}
+}
+
+func TestComparisonView_Running(t *testing.T) {
+	c := NewComparison(comparisonTraders(), DefaultStyles(), nil)
+	c.SetSize(100, 30)
+
+	view := c.View()
+	assert.Contains(t, view, "COMPARISON: alice vs 0xabcd...ef12")
+	assert.Contains(t, view, "Comparing 2 traders")
+	assert.Equal(t, "esc: cancel", c.HelpText())
+}
+
+func TestComparisonRun_NoClient(t *testing.T) {
+	c := NewComparison(comparisonTraders(), DefaultStyles(), nil)
+	msg := c.Run(nil)()
+
+	errMsg, ok := msg.(ComparisonErrorMsg)
+	require.True(t, ok, "expected ComparisonErrorMsg, got %T", msg)
+	assert.ErrorIs(t, errMsg.Err, claude.ErrNoAPIKey)
+
+	c, _ = c.Update(msg)
+	assert.Equal(t, comparisonStateError, c.state)
+	assert.Contains(t, c.View(), "not configured")
+}
+
+func TestComparisonUpdate_Complete(t *testing.T) {
+	c := NewComparison(comparisonTraders(), DefaultStyles(), nil)
+	c.SetSize(100, 40)
+
+	// Messages from another comparison are ignored.
+	other := NewComparison(comparisonTraders(), DefaultStyles(), nil)
+	c, _ = c.Update(ComparisonErrorMsg{Err: errors.New("stale"), source: other})
+	assert.Equal(t, comparisonStateRunning, c.state)
+
+	c, _ = c.Update(ComparisonCompleteMsg{
+		Comparison: &db.Comparison{Thesis: "## Verdict\nAlice leads.", Model: "claude-test", InputTokens: 900, OutputTokens: 90},
+		source:     c,
+	})
+	assert.Equal(t, comparisonStateComplete, c.state)
+	view := c.View()
+	assert.Contains(t, view, "Verdict")
+	assert.Contains(t, view, "Alice leads.")
+	assert.Contains(t, view, "900 input / 90 output tokens")
+
+	_, cmd := c.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(GoBackMsg)
+	assert.True(t, ok)
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 
+	"polytracker/internal/claude"
 	"polytracker/internal/db"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -12,6 +13,7 @@ import (
 )
 
 const (
+	colMark     = "mark"
 	colRank     = "rank"
 	colAddress  = "address"
 	colUsername = "username"
@@ -31,6 +33,10 @@ type LeaderboardKeyMap struct {
 	Enter    key.Binding
 	SortWin  key.Binding
 	SortPNL  key.Binding
+	Filter   key.Binding
+	Category key.Binding
+	Mark     key.Binding
+	Compare  key.Binding
 }
 
 var leaderboardKeys = LeaderboardKeyMap{
@@ -62,13 +68,35 @@ var leaderboardKeys = LeaderboardKeyMap{
 		key.WithKeys("p"),
 		key.WithHelp("p", "sort by P&L"),
 	),
+	Filter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter by type"),
+	),
+	Category: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "filter by category"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark for comparison"),
+	),
+	Compare: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "compare marked"),
+	),
 }
 
+// traderTypeFilters is the cycle order for the trader type filter; empty means all.
+var traderTypeFilters = []string{"", db.TraderTypeDirectional, db.TraderTypeMarketMaker, db.TraderTypeBot}
+
 type Leaderboard struct {
 	table       table.Model
 	traders     []db.Trader
 	sortField   db.SortField
 	sortOrder   db.SortOrder
+	typeFilter  string
+	category    string
+	categories  []string
 	currentPage int
 	totalPages  int
 	totalCount  int
@@ -76,17 +104,26 @@ type Leaderboard struct {
 	height      int
 	styles      Styles
 	selected    *db.Trader
+	// marked holds the traders marked for comparison, in the order they were
+	// marked; marks survive paging, sorting and filtering.
+	marked []db.Trader
 }
 
 type tradersLoadedMsg struct {
 	traders    []db.Trader
 	totalCount int
+	categories []string
 }
 
 type TraderSelectedMsg struct {
 	Trader *db.Trader
 }
 
+// CompareTradersMsg asks for a Claude comparison of the marked traders.
+type CompareTradersMsg struct {
+	Traders []db.Trader
+}
+
 func NewLeaderboard(styles Styles) *Leaderboard {
 	l := &Leaderboard{
 		sortField:   db.SortByProfitLoss,
@@ -100,6 +137,7 @@ func NewLeaderboard(styles Styles) *Leaderboard {
 
 func (l *Leaderboard) createTable() table.Model {
 	columns := []table.Column{
+		table.NewColumn(colMark, "", 2),
 		table.NewColumn(colRank, "#", 4).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
 		table.NewColumn(colAddress, "Address", 14),
 		table.NewColumn(colUsername, "Username", 16),
@@ -130,17 +168,26 @@ func (l *Leaderboard) SetSize(width, height int) {
 
 func (l *Leaderboard) LoadTraders(database *db.DB) tea.Cmd {
 	return func() tea.Msg {
-		count, err := database.CountTraders()
+		opts := db.ListTradersOptions{
+			SortBy:     l.sortField,
+			Order:      l.sortOrder,
+			Limit:      pageSize,
+			Offset:     l.currentPage * pageSize,
+			TraderType: l.typeFilter,
+			Category:   l.category,
+		}
+
+		count, err := database.CountTradersWithOptions(opts)
 		if err != nil {
 			return nil
 		}
 
-		traders, err := database.ListTradersWithOptions(db.ListTradersOptions{
-			SortBy: l.sortField,
-			Order:  l.sortOrder,
-			Limit:  pageSize,
-			Offset: l.currentPage * pageSize,
-		})
+		traders, err := database.ListTradersWithOptions(opts)
+		if err != nil {
+			return nil
+		}
+
+		categories, err := database.ListCategories()
 		if err != nil {
 			return nil
 		}
@@ -148,6 +195,7 @@ func (l *Leaderboard) LoadTraders(database *db.DB) tea.Cmd {
 		return tradersLoadedMsg{
 			traders:    traders,
 			totalCount: count,
+			categories: categories,
 		}
 	}
 }
@@ -159,6 +207,7 @@ func (l *Leaderboard) Update(msg tea.Msg) (*Leaderboard, tea.Cmd) {
 	case tradersLoadedMsg:
 		l.traders = msg.traders
 		l.totalCount = msg.totalCount
+		l.categories = msg.categories
 		l.totalPages = (msg.totalCount + pageSize - 1) / pageSize
 		if l.totalPages == 0 {
 			l.totalPages = 1
@@ -178,6 +227,20 @@ func (l *Leaderboard) Update(msg tea.Msg) (*Leaderboard, tea.Cmd) {
 					}
 				}
 			}
+		case key.Matches(msg, leaderboardKeys.Mark):
+			if trader := l.highlightedTrader(); trader != nil {
+				l.toggleMark(*trader)
+				l.table = l.table.WithRows(l.buildRows())
+			}
+			return l, nil
+		case key.Matches(msg, leaderboardKeys.Compare):
+			if len(l.marked) >= claude.MinCompareTraders && len(l.marked) <= claude.MaxCompareTraders {
+				traders := append([]db.Trader(nil), l.marked...)
+				return l, func() tea.Msg {
+					return CompareTradersMsg{Traders: traders}
+				}
+			}
+			return l, nil
 		case key.Matches(msg, leaderboardKeys.SortWin):
 			if l.sortField == db.SortByWinRate {
 				l.toggleSortOrder()
@@ -196,6 +259,14 @@ func (l *Leaderboard) Update(msg tea.Msg) (*Leaderboard, tea.Cmd) {
 			}
 			l.currentPage = 0
 			return l, nil
+		case key.Matches(msg, leaderboardKeys.Filter):
+			l.cycleTypeFilter()
+			l.currentPage = 0
+			return l, nil
+		case key.Matches(msg, leaderboardKeys.Category):
+			l.cycleCategory()
+			l.currentPage = 0
+			return l, nil
 		}
 	}
 
@@ -203,6 +274,57 @@ func (l *Leaderboard) Update(msg tea.Msg) (*Leaderboard, tea.Cmd) {
 	return l, cmd
 }
 
+// highlightedTrader returns the trader under the cursor, or nil.
+func (l *Leaderboard) highlightedTrader() *db.Trader {
+	row := l.table.HighlightedRow()
+	if row.Data == nil {
+		return nil
+	}
+	idx, ok := row.Data[colRank].(int)
+	if !ok {
+		return nil
+	}
+	i := idx - 1 - l.currentPage*pageSize
+	if i < 0 || i >= len(l.traders) {
+		return nil
+	}
+	return &l.traders[i]
+}
+
+// toggleMark marks or unmarks a trader for comparison. Marking stops at
+// claude.MaxCompareTraders.
+func (l *Leaderboard) toggleMark(trader db.Trader) {
+	for i, t := range l.marked {
+		if t.Address == trader.Address {
+			l.marked = append(l.marked[:i], l.marked[i+1:]...)
+			return
+		}
+	}
+	if len(l.marked) < claude.MaxCompareTraders {
+		l.marked = append(l.marked, trader)
+	}
+}
+
+func (l *Leaderboard) isMarked(address string) bool {
+	for _, t := range l.marked {
+		if t.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+// Marked returns the traders marked for comparison.
+func (l *Leaderboard) Marked() []db.Trader {
+	return l.marked
+}
+
+// ClearMarks unmarks every trader.
+func (l *Leaderboard) ClearMarks() {
+	l.marked = nil
+	l.table = l.table.WithRows(l.buildRows())
+}
+
 func (l *Leaderboard) toggleSortOrder() {
 	if l.sortOrder == db.SortDesc {
 		l.sortOrder = db.SortAsc
@@ -211,6 +333,35 @@ func (l *Leaderboard) toggleSortOrder() {
 	}
 }
 
+func (l *Leaderboard) cycleTypeFilter() {
+	for i, f := range traderTypeFilters {
+		if f == l.typeFilter {
+			l.typeFilter = traderTypeFilters[(i+1)%len(traderTypeFilters)]
+			return
+		}
+	}
+	l.typeFilter = ""
+}
+
+// cycleCategory steps through all categories, then back to ranking on overall stats.
+func (l *Leaderboard) cycleCategory() {
+	for i, c := range l.categories {
+		if c == l.category {
+			if i+1 < len(l.categories) {
+				l.category = l.categories[i+1]
+			} else {
+				l.category = ""
+			}
+			return
+		}
+	}
+	if l.category == "" && len(l.categories) > 0 {
+		l.category = l.categories[0]
+		return
+	}
+	l.category = ""
+}
+
 func (l *Leaderboard) buildRows() []table.Row {
 	rows := make([]table.Row, len(l.traders))
 	for i, t := range l.traders {
@@ -225,7 +376,13 @@ func (l *Leaderboard) buildRows() []table.Row {
 			username = "-"
 		}
 
+		mark := ""
+		if l.isMarked(t.Address) {
+			mark = "✓"
+		}
+
 		rows[i] = table.NewRow(table.RowData{
+			colMark:     mark,
 			colRank:     rank,
 			colAddress:  address,
 			colUsername: username,
@@ -273,18 +430,36 @@ func (l *Leaderboard) View() string {
 		sortIndicator += " ↑"
 	}
 
+	typeIndicator := "all"
+	if l.typeFilter != "" {
+		typeIndicator = l.typeFilter
+	}
+
+	categoryIndicator := "all"
+	if l.category != "" {
+		categoryIndicator = l.category
+	}
+
 	header := l.styles.Subtle.Render(fmt.Sprintf(
-		"Sorted by: %s | Page %d/%d | Total: %d traders",
+		"Sorted by: %s | Type: %s | Category: %s | Page %d/%d | Total: %d traders",
 		sortIndicator,
+		typeIndicator,
+		categoryIndicator,
 		l.currentPage+1,
 		l.totalPages,
 		l.totalCount,
 	))
 
+	marks := ""
+	if len(l.marked) > 0 {
+		marks = l.styles.Highlight.Render(fmt.Sprintf("Marked for comparison: %d of %d (C to compare)",
+			len(l.marked), claude.MaxCompareTraders))
+	}
+
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		header,
-		"",
+		marks,
 		l.table.View(),
 	)
 }
@@ -297,6 +472,14 @@ func (l *Leaderboard) GetSortOrder() db.SortOrder {
 	return l.sortOrder
 }
 
+func (l *Leaderboard) GetTypeFilter() string {
+	return l.typeFilter
+}
+
+func (l *Leaderboard) GetCategory() string {
+	return l.category
+}
+
 func (l *Leaderboard) GetCurrentPage() int {
 	return l.currentPage
 }
@@ -314,5 +497,5 @@ func (l *Leaderboard) PrevPage() {
 }
 
 func (l *Leaderboard) HelpText() string {
-	return "↑/↓: navigate • enter: view details • w: sort by win% • p: sort by P&L"
+	return "↑/↓: navigate • enter: view details • w: sort by win% • p: sort by P&L • f: filter type • c: filter category • space: mark • C: compare marked"
 }
@@ -127,6 +127,90 @@ func TestLeaderboardSortToggle(t *testing.T) {
 	}
 }
 
+func TestLeaderboardTypeFilter(t *testing.T) {
+	database := setupTestDB(t)
+
+	for _, addr := range []string{"0xaaaa", "0xbbbb"} {
+		if err := database.SaveTrader(&db.Trader{Address: addr, LastScanned: time.Now()}); err != nil {
+			t.Fatalf("Failed to save trader: %v", err)
+		}
+	}
+	if err := database.SaveTraderClassification(&db.TraderClassification{
+		TraderID: "0xbbbb", Label: db.TraderTypeMarketMaker, Confidence: 0.8, UpdatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save classification: %v", err)
+	}
+
+	lb := NewLeaderboard(DefaultStyles())
+
+	// f cycles: all -> directional -> market_maker
+	lb, _ = lb.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if lb.GetTypeFilter() != db.TraderTypeDirectional {
+		t.Errorf("Expected directional filter, got %q", lb.GetTypeFilter())
+	}
+	lb, _ = lb.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if lb.GetTypeFilter() != db.TraderTypeMarketMaker {
+		t.Errorf("Expected market_maker filter, got %q", lb.GetTypeFilter())
+	}
+
+	msg := lb.LoadTraders(database)()
+	loadedMsg, ok := msg.(tradersLoadedMsg)
+	if !ok {
+		t.Fatalf("Expected tradersLoadedMsg, got %T", msg)
+	}
+	if loadedMsg.totalCount != 1 || len(loadedMsg.traders) != 1 || loadedMsg.traders[0].Address != "0xbbbb" {
+		t.Errorf("Expected only the market maker, got %+v", loadedMsg.traders)
+	}
+
+	if !strings.Contains(lb.View(), "Type: market_maker") {
+		t.Error("View should show the active type filter")
+	}
+}
+
+func TestLeaderboardCategoryFilter(t *testing.T) {
+	database := setupTestDB(t)
+
+	if err := database.SaveMarket(&db.Market{ID: "m1", Question: "Final?", Category: "Sports"}); err != nil {
+		t.Fatalf("Failed to save market: %v", err)
+	}
+	for _, addr := range []string{"0xaaaa", "0xbbbb"} {
+		if err := database.SaveTrader(&db.Trader{Address: addr, LastScanned: time.Now()}); err != nil {
+			t.Fatalf("Failed to save trader: %v", err)
+		}
+	}
+	if err := database.SaveTrade(&db.Trade{
+		ID: "t1", TraderID: "0xbbbb", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.5, Size: 10, Timestamp: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save trade: %v", err)
+	}
+
+	lb := NewLeaderboard(DefaultStyles())
+	lb, _ = lb.Update(lb.LoadTraders(database)())
+
+	// c cycles: all -> Sports -> all
+	lb, _ = lb.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if lb.GetCategory() != "Sports" {
+		t.Fatalf("Expected Sports category, got %q", lb.GetCategory())
+	}
+
+	msg := lb.LoadTraders(database)()
+	loadedMsg, ok := msg.(tradersLoadedMsg)
+	if !ok {
+		t.Fatalf("Expected tradersLoadedMsg, got %T", msg)
+	}
+	if loadedMsg.totalCount != 1 || len(loadedMsg.traders) != 1 || loadedMsg.traders[0].Address != "0xbbbb" {
+		t.Errorf("Expected only the Sports trader, got %+v", loadedMsg.traders)
+	}
+	if !strings.Contains(lb.View(), "Category: Sports") {
+		t.Error("View should show the active category")
+	}
+
+	lb, _ = lb.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if lb.GetCategory() != "" {
+		t.Errorf("Expected category filter to reset, got %q", lb.GetCategory())
+	}
+}
+
 func TestLeaderboardView(t *testing.T) {
 	styles := DefaultStyles()
 	lb := NewLeaderboard(styles)
@@ -199,3 +283,53 @@ func TestFormatVolume(t *testing.T) {
 		}
 	}
 }
+
+func TestLeaderboardMarkAndCompare(t *testing.T) {
+	lb := NewLeaderboard(DefaultStyles())
+	lb, _ = lb.Update(tradersLoadedMsg{
+		traders: []db.Trader{
+			{Address: "0xaaa", Username: "alice"},
+			{Address: "0xbbb", Username: "bob"},
+		},
+		totalCount: 2,
+	})
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	compare := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}}
+
+	lb, _ = lb.Update(space)
+	if len(lb.Marked()) != 1 || lb.Marked()[0].Address != "0xaaa" {
+		t.Fatalf("Expected 0xaaa to be marked, got %+v", lb.Marked())
+	}
+	if _, cmd := lb.Update(compare); cmd != nil {
+		t.Error("Expected no comparison with a single marked trader")
+	}
+
+	lb, _ = lb.Update(tea.KeyMsg{Type: tea.KeyDown})
+	lb, _ = lb.Update(space)
+	if !strings.Contains(lb.View(), "Marked for comparison: 2") {
+		t.Error("View should show the number of marked traders")
+	}
+
+	_, cmd := lb.Update(compare)
+	if cmd == nil {
+		t.Fatal("Expected a compare command")
+	}
+	msg, ok := cmd().(CompareTradersMsg)
+	if !ok {
+		t.Fatalf("Expected CompareTradersMsg, got %T", cmd())
+	}
+	if len(msg.Traders) != 2 || msg.Traders[0].Address != "0xaaa" || msg.Traders[1].Address != "0xbbb" {
+		t.Errorf("Expected traders in marking order, got %+v", msg.Traders)
+	}
+
+	// Marking again unmarks.
+	lb, _ = lb.Update(space)
+	if len(lb.Marked()) != 1 || lb.Marked()[0].Address != "0xaaa" {
+		t.Errorf("Expected only 0xaaa to stay marked, got %+v", lb.Marked())
+	}
+	lb.ClearMarks()
+	if len(lb.Marked()) != 0 {
+		t.Error("Expected no marks after ClearMarks")
+	}
+}
@@ -0,0 +1,410 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// marketParticipantsLimit caps the participants listed on the market screen.
+const marketParticipantsLimit = 10
+
+type marketState int
+
+const (
+	marketStateLoading marketState = iota
+	marketStateReady
+	marketStateAnalyzing
+	marketStateError
+)
+
+type MarketKeyMap struct {
+	Analyze key.Binding
+}
+
+var marketKeys = MarketKeyMap{
+	Analyze: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "analyze market"),
+	),
+}
+
+// MarketDetail shows who is trading a market, the net flow by outcome and
+// Claude's latest read on smart-money positioning. 'a' asks for a new read,
+// which is saved as soon as it arrives.
+type MarketDetail struct {
+	marketID     string
+	data         claude.MarketData
+	overview     claude.MarketPromptData
+	analysis     *db.MarketAnalysis
+	state        marketState
+	styles       Styles
+	width        int
+	height       int
+	scrollOffset int
+	spinner      spinner.Model
+	err          error
+	claudeClient *claude.Client
+	cancel       context.CancelFunc
+
+	truncated bool
+	// saveErr is set when the latest analysis could not be stored.
+	saveErr error
+}
+
+// OpenMarketMsg opens the market screen.
+type OpenMarketMsg struct {
+	MarketID string
+}
+
+type marketLoadedMsg struct {
+	data     claude.MarketData
+	analysis *db.MarketAnalysis
+	err      error
+	source   *MarketDetail
+}
+
+// MarketAnalysisCompleteMsg carries a finished market analysis.
+type MarketAnalysisCompleteMsg struct {
+	Analysis  *db.MarketAnalysis
+	Truncated bool
+	SaveErr   error
+	source    *MarketDetail
+}
+
+// AnalyzeMarketMsg asks for a new analysis of the open market.
+type AnalyzeMarketMsg struct{}
+
+type MarketAnalysisErrorMsg struct {
+	Err    error
+	source *MarketDetail
+}
+
+func NewMarketDetail(marketID string, styles Styles, claudeClient *claude.Client) *MarketDetail {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.Header.GetBackground())
+
+	return &MarketDetail{
+		marketID:     marketID,
+		state:        marketStateLoading,
+		styles:       styles,
+		spinner:      s,
+		claudeClient: claudeClient,
+	}
+}
+
+func (md *MarketDetail) SetSize(width, height int) {
+	md.width = width
+	md.height = height
+}
+
+// Load reads the market, its trades and snapshots, and its latest analysis.
+func (md *MarketDetail) Load(database *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		market, err := database.GetMarket(md.marketID)
+		if err != nil {
+			return marketLoadedMsg{err: err, source: md}
+		}
+		if market == nil {
+			return marketLoadedMsg{err: fmt.Errorf("market not found: %s", md.marketID), source: md}
+		}
+		data, err := claude.LoadMarketData(database, market)
+		if err != nil {
+			return marketLoadedMsg{err: err, source: md}
+		}
+		analysis, err := database.GetMarketAnalysisByMarket(md.marketID)
+		if err != nil {
+			return marketLoadedMsg{err: err, source: md}
+		}
+		return marketLoadedMsg{data: data, analysis: analysis, source: md}
+	}
+}
+
+// Analyze asks Claude for a read on the market and saves it.
+func (md *MarketDetail) Analyze(database *db.DB) tea.Cmd {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	md.cancel = cancel
+	md.state = marketStateAnalyzing
+	md.err = nil
+	data := md.data
+
+	analyze := func() tea.Msg {
+		if md.claudeClient == nil {
+			return MarketAnalysisErrorMsg{Err: claude.ErrNoAPIKey, source: md}
+		}
+		result, err := md.claudeClient.AnalyzeMarket(ctx, data)
+		if result == nil {
+			return MarketAnalysisErrorMsg{Err: err, source: md}
+		}
+		analysis := result.MarketAnalysis(md.marketID)
+		return MarketAnalysisCompleteMsg{
+			Analysis:  analysis,
+			Truncated: errors.Is(err, claude.ErrTokenLimit),
+			SaveErr:   database.SaveMarketAnalysis(analysis),
+			source:    md,
+		}
+	}
+	return tea.Batch(analyze, md.spinner.Tick)
+}
+
+// Cancel aborts a running analysis, if any.
+func (md *MarketDetail) Cancel() {
+	if md.cancel != nil {
+		md.cancel()
+		md.cancel = nil
+	}
+}
+
+func (md *MarketDetail) Update(msg tea.Msg) (*MarketDetail, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if md.state == marketStateAnalyzing {
+			md.spinner, cmd = md.spinner.Update(msg)
+			return md, cmd
+		}
+
+	case marketLoadedMsg:
+		if msg.source != md {
+			break
+		}
+		if msg.err != nil {
+			md.err = msg.err
+			md.state = marketStateError
+			break
+		}
+		md.data = msg.data
+		md.overview = claude.BuildMarketPromptData(msg.data)
+		md.analysis = msg.analysis
+		md.state = marketStateReady
+
+	case MarketAnalysisCompleteMsg:
+		// Results of a cancelled analysis are dropped.
+		if msg.source != md || md.state != marketStateAnalyzing {
+			break
+		}
+		md.Cancel()
+		md.analysis = msg.Analysis
+		md.truncated = msg.Truncated
+		md.saveErr = msg.SaveErr
+		md.state = marketStateReady
+
+	case MarketAnalysisErrorMsg:
+		if msg.source != md || md.state != marketStateAnalyzing {
+			break
+		}
+		md.Cancel()
+		md.err = msg.Err
+		md.state = marketStateReady
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, analysisKeys.Back):
+			if md.state == marketStateAnalyzing {
+				md.Cancel()
+				md.state = marketStateReady
+				return md, nil
+			}
+			return md, func() tea.Msg { return GoBackMsg{} }
+
+		case key.Matches(msg, analysisKeys.Up):
+			if md.scrollOffset > 0 {
+				md.scrollOffset--
+			}
+
+		case key.Matches(msg, analysisKeys.Down):
+			md.scrollOffset++
+
+		case key.Matches(msg, marketKeys.Analyze):
+			if md.state == marketStateReady {
+				return md, func() tea.Msg { return AnalyzeMarketMsg{} }
+			}
+		}
+	}
+
+	return md, nil
+}
+
+func (md *MarketDetail) View() string {
+	if md.state == marketStateLoading {
+		return md.styles.Subtle.Render("Loading market...")
+	}
+	if md.state == marketStateError && md.data.Market == nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Render(fmt.Sprintf("Error loading market: %v", md.err))
+	}
+
+	sections := []string{md.renderHeader(), md.renderFlows(), md.renderParticipants(), md.renderAnalysis()}
+
+	lines := strings.Split(lipgloss.JoinVertical(lipgloss.Left, sections...), "\n")
+	if md.scrollOffset >= len(lines) {
+		md.scrollOffset = len(lines) - 1
+	}
+	if md.scrollOffset < 0 {
+		md.scrollOffset = 0
+	}
+	visibleHeight := md.height - 4
+	if visibleHeight < 1 {
+		visibleHeight = 20
+	}
+	endIdx := md.scrollOffset + visibleHeight
+	if endIdx > len(lines) {
+		endIdx = len(lines)
+	}
+	return strings.Join(lines[md.scrollOffset:endIdx], "\n")
+}
+
+func (md *MarketDetail) renderHeader() string {
+	m := md.data.Market
+	question := m.Question
+	if question == "" {
+		question = m.ID
+	}
+
+	var info []string
+	if m.Category != "" {
+		info = append(info, m.Category)
+	}
+	if m.Status != "" {
+		info = append(info, m.Status)
+	}
+	if !m.EndsAt.IsZero() {
+		info = append(info, "ends "+m.EndsAt.Format("2006-01-02"))
+	}
+	if s := md.overview.Latest; s != nil {
+		info = append(info, fmt.Sprintf("YES %s / NO %s",
+			md.styles.Highlight.Render(fmt.Sprintf("%.3f", s.YesPrice)),
+			md.styles.Highlight.Render(fmt.Sprintf("%.3f", s.NoPrice))))
+	}
+	info = append(info, fmt.Sprintf("%d trades by %d traders", md.overview.TotalTrades, md.overview.TotalParticipants))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		md.styles.Header.Render(fmt.Sprintf(" MARKET: %s ", question)),
+		"",
+		strings.Join(info, " · "),
+	)
+}
+
+func (md *MarketDetail) renderFlows() string {
+	if len(md.overview.Flows) == 0 {
+		return ""
+	}
+	lines := []string{
+		"",
+		md.styles.Subtle.Render(fmt.Sprintf("%-8s %12s %12s %12s %16s %8s", "Outcome", "Bought", "Sold", "Net", "Profitable Net", "Traders")),
+	}
+	for _, f := range md.overview.Flows {
+		lines = append(lines, fmt.Sprintf("%-8s %12s %12s %12s %16s %8d",
+			f.Outcome,
+			fmt.Sprintf("$%.2f", f.Bought),
+			fmt.Sprintf("$%.2f", f.Sold),
+			formatPNL(f.Net),
+			formatPNL(f.ProfitableNet),
+			f.Traders))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (md *MarketDetail) renderParticipants() string {
+	participants := md.overview.Participants
+	if len(participants) == 0 {
+		return "\n" + md.styles.Subtle.Render("No stored trades in this market.")
+	}
+	if len(participants) > marketParticipantsLimit {
+		participants = participants[:marketParticipantsLimit]
+	}
+
+	lines := []string{
+		"",
+		md.styles.Subtle.Render(fmt.Sprintf("%-16s %-13s %7s %8s %12s %11s %10s %10s", "Trader", "Type", "Win %", "ROI %", "P&L", "Volume", "Net YES", "Net NO")),
+	}
+	for _, p := range participants {
+		name := p.Name
+		if len(name) > 16 {
+			name = name[:6] + "..." + name[len(name)-4:]
+		}
+		label := p.Label
+		if label == "" {
+			label = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%-16s %-13s %7s %8s %12s %11s %10.2f %10.2f",
+			name, label,
+			formatPercent(p.WinRate),
+			formatPercent(p.ROI),
+			formatPNL(p.ProfitLoss),
+			fmt.Sprintf("$%.2f", p.Volume),
+			p.NetYes, p.NetNo))
+	}
+	if more := len(md.overview.Participants) - len(participants); more > 0 {
+		lines = append(lines, md.styles.Subtle.Render(fmt.Sprintf("  ... and %d more traders", more)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (md *MarketDetail) renderAnalysis() string {
+	sections := []string{""}
+
+	switch {
+	case md.state == marketStateAnalyzing:
+		sections = append(sections, md.spinner.View()+" Asking Claude who is trading this market and why...")
+	case md.analysis != nil:
+		box := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(md.styles.Header.GetBackground()).
+			Padding(1, 2).
+			Width(md.width - 6)
+		sections = append(sections, box.Render(renderMarkdown(md.styles, md.analysis.Thesis)))
+		if md.truncated {
+			sections = append(sections, md.styles.Subtle.Render("Response was cut off at the token limit."))
+		}
+		sections = append(sections, md.renderUsage())
+		if md.saveErr != nil {
+			sections = append(sections, md.styles.Subtle.Render(fmt.Sprintf("Warning: failed to save market analysis: %v", md.saveErr)))
+		}
+	default:
+		sections = append(sections, md.styles.Subtle.Render("No analysis yet. Press 'a' to ask Claude for a read on smart-money positioning."))
+	}
+
+	if md.err != nil {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555"))
+		sections = append(sections, errStyle.Render(fmt.Sprintf("Analysis failed: %v", md.err)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderUsage summarizes when and by which model the shown analysis was
+// written, and what it cost.
+func (md *MarketDetail) renderUsage() string {
+	a := md.analysis
+	parts := []string{a.CreatedAt.Format("2006-01-02 15:04")}
+	if a.Model != "" {
+		parts = append(parts, a.Model)
+	}
+	parts = append(parts, fmt.Sprintf("%d input / %d output tokens", a.InputTokens, a.OutputTokens))
+	if a.CostUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.4f", a.CostUSD))
+	}
+	return md.styles.Subtle.Render(strings.Join(parts, " · "))
+}
+
+func (md *MarketDetail) HelpText() string {
+	switch md.state {
+	case marketStateAnalyzing:
+		return "esc: cancel"
+	case marketStateReady:
+		return "a: analyze market | j/k: scroll | esc: back"
+	default:
+		return "esc: back"
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polytracker/internal/claude"
+	"polytracker/internal/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketDetailLoad(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now()
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain?", Category: "weather"}))
+	require.NoError(t, database.SaveTrader(&db.Trader{Address: "0xwhale", Username: "whale", WinRate: 0.7, ProfitLoss: 5000}))
+	require.NoError(t, database.SaveTrade(&db.Trade{ID: "t1", TraderID: "0xwhale", MarketID: "m1", Type: "BUY", Side: "YES", Price: 0.4, Size: 100, Timestamp: now}))
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{MarketID: "m1", YesPrice: 0.55, NoPrice: 0.45, Timestamp: now}))
+
+	md := NewMarketDetail("m1", DefaultStyles(), nil)
+	md.SetSize(120, 60)
+	assert.Contains(t, md.View(), "Loading market")
+
+	md, _ = md.Update(md.Load(database)())
+	view := md.View()
+	assert.Contains(t, view, "MARKET: Will it rain?")
+	assert.Contains(t, view, "1 trades by 1 traders")
+	assert.Contains(t, view, "whale")
+	assert.Contains(t, view, "70.0%")
+	assert.Contains(t, view, "No analysis yet")
+	assert.Equal(t, "a: analyze market | j/k: scroll | esc: back", md.HelpText())
+
+	require.NoError(t, database.SaveMarketAnalysis(&db.MarketAnalysis{MarketID: "m1", Thesis: "Smart money is long YES.", Model: "claude-test"}))
+	md, _ = md.Update(md.Load(database)())
+	assert.Contains(t, md.View(), "Smart money is long YES.")
+	assert.Contains(t, md.View(), "claude-test")
+
+	missing := NewMarketDetail("nope", DefaultStyles(), nil)
+	missing, _ = missing.Update(missing.Load(database)())
+	assert.Contains(t, missing.View(), "market not found")
+}
+
+func TestMarketDetailAnalyze(t *testing.T) {
+	md := NewMarketDetail("m1", DefaultStyles(), nil)
+	md.SetSize(120, 60)
+	md, _ = md.Update(marketLoadedMsg{data: claude.MarketData{Market: &db.Market{ID: "m1", Question: "Will it rain?"}}, source: md})
+
+	_, cmd := md.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(AnalyzeMarketMsg)
+	assert.True(t, ok)
+
+	md.Analyze(nil)
+	assert.Contains(t, md.View(), "Asking Claude")
+	assert.Equal(t, "esc: cancel", md.HelpText())
+
+	// Messages from another market screen are ignored.
+	other := NewMarketDetail("m1", DefaultStyles(), nil)
+	md, _ = md.Update(MarketAnalysisCompleteMsg{Analysis: &db.MarketAnalysis{Thesis: "stale"}, source: other})
+	assert.Equal(t, marketStateAnalyzing, md.state)
+
+	md, _ = md.Update(MarketAnalysisCompleteMsg{
+		Analysis:  &db.MarketAnalysis{MarketID: "m1", Thesis: "Flow favors NO.", InputTokens: 800, OutputTokens: 80, CreatedAt: time.Now()},
+		Truncated: true,
+		source:    md,
+	})
+	assert.Equal(t, marketStateReady, md.state)
+	view := md.View()
+	assert.Contains(t, view, "Flow favors NO.")
+	assert.Contains(t, view, "800 input / 80 output tokens")
+	assert.Contains(t, view, "token limit")
+}
+
+func TestMarketDetailAnalyze_EscCancels(t *testing.T) {
+	md := NewMarketDetail("m1", DefaultStyles(), nil)
+	md, _ = md.Update(marketLoadedMsg{data: claude.MarketData{Market: &db.Market{ID: "m1"}}, source: md})
+	md.Analyze(nil)
+	cancelled := false
+	md.cancel = func() { cancelled = true }
+
+	md, cmd := md.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd, "esc while analyzing cancels instead of going back")
+	assert.True(t, cancelled)
+	assert.Equal(t, marketStateReady, md.state)
+
+	// The cancelled request's error is dropped.
+	md, _ = md.Update(MarketAnalysisErrorMsg{Err: context.Canceled, source: md})
+	assert.Nil(t, md.err)
+
+	_, cmd = md.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(GoBackMsg)
+	assert.True(t, ok)
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"polytracker/internal/db"
+	"polytracker/internal/paper"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+)
+
+const (
+	colPosMarket = "market"
+	colPosSide   = "side"
+	colPosShares = "shares"
+	colPosAvg    = "avg"
+	colPosMark   = "mark"
+	colPosValue  = "value"
+	colPosPNL    = "pnl"
+	// colPosMarketID is row data only, used to open the market screen.
+	colPosMarketID = "market_id"
+
+	recentFillsLimit = 8
+)
+
+type PortfolioKeyMap struct {
+	Refresh key.Binding
+	Open    key.Binding
+}
+
+var portfolioKeys = PortfolioKeyMap{
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open market"),
+	),
+}
+
+type Portfolio struct {
+	table     table.Model
+	valuation *paper.Valuation
+	fills     []db.PaperFill
+	err       error
+	width     int
+	height    int
+	styles    Styles
+}
+
+type portfolioLoadedMsg struct {
+	valuation *paper.Valuation
+	fills     []db.PaperFill
+	err       error
+}
+
+func NewPortfolio(styles Styles) *Portfolio {
+	p := &Portfolio{
+		styles: styles,
+	}
+	p.table = p.createTable()
+	return p
+}
+
+func (p *Portfolio) createTable() table.Model {
+	columns := []table.Column{
+		table.NewColumn(colPosMarket, "Market", 36),
+		table.NewColumn(colPosSide, "Side", 5),
+		table.NewColumn(colPosShares, "Shares", 10).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
+		table.NewColumn(colPosAvg, "Avg", 7).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
+		table.NewColumn(colPosMark, "Mark", 7).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
+		table.NewColumn(colPosValue, "Value", 12).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
+		table.NewColumn(colPosPNL, "P&L", 12).WithStyle(lipgloss.NewStyle().Align(lipgloss.Right)),
+	}
+
+	return table.New(columns).
+		WithRows([]table.Row{}).
+		Focused(true).
+		WithPageSize(10).
+		HeaderStyle(lipgloss.NewStyle().Bold(true).Foreground(p.styles.Highlight.GetForeground())).
+		HighlightStyle(lipgloss.NewStyle().
+			Bold(true).
+			Background(p.styles.ActiveTab.GetBorderBottomForeground()).
+			Foreground(lipgloss.Color("#FFF")))
+}
+
+func (p *Portfolio) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.table = p.table.WithTargetWidth(width - 4)
+}
+
+// LoadPortfolio marks the paper portfolio to market. It only reads stored fills;
+// mirroring new trades happens on scan.
+func (p *Portfolio) LoadPortfolio(database *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		valuation, err := paper.NewPortfolio(database).Value()
+		if err != nil {
+			return portfolioLoadedMsg{err: err}
+		}
+		fills, err := database.ListPaperFills(recentFillsLimit)
+		if err != nil {
+			return portfolioLoadedMsg{err: err}
+		}
+		return portfolioLoadedMsg{valuation: valuation, fills: fills}
+	}
+}
+
+func (p *Portfolio) Update(msg tea.Msg) (*Portfolio, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case portfolioLoadedMsg:
+		p.err = msg.err
+		p.valuation = msg.valuation
+		p.fills = msg.fills
+		p.table = p.table.WithRows(p.buildRows())
+		return p, nil
+
+	case tea.KeyMsg:
+		if key.Matches(msg, portfolioKeys.Open) {
+			if row := p.table.HighlightedRow(); row.Data != nil {
+				if marketID, ok := row.Data[colPosMarketID].(string); ok {
+					return p, func() tea.Msg { return OpenMarketMsg{MarketID: marketID} }
+				}
+			}
+			return p, nil
+		}
+	}
+
+	p.table, cmd = p.table.Update(msg)
+	return p, cmd
+}
+
+func (p *Portfolio) buildRows() []table.Row {
+	if p.valuation == nil {
+		return nil
+	}
+	rows := make([]table.Row, len(p.valuation.Holdings))
+	for i, h := range p.valuation.Holdings {
+		market := h.Question
+		if market == "" {
+			market = h.MarketID
+		}
+		if len(market) > 34 {
+			market = market[:31] + "..."
+		}
+
+		rows[i] = table.NewRow(table.RowData{
+			colPosMarket: market,
+			colPosSide:   h.Side,
+			colPosShares: fmt.Sprintf("%.2f", h.Shares),
+			colPosAvg:    fmt.Sprintf("%.3f", h.CostBasis/h.Shares),
+			colPosMark:   fmt.Sprintf("%.3f", h.Mark),
+			colPosValue:  fmt.Sprintf("$%.2f", h.Value),
+			colPosPNL:    formatPNL(h.UnrealizedPnL),
+
+			colPosMarketID: h.MarketID,
+		})
+	}
+	return rows
+}
+
+func (p *Portfolio) View() string {
+	if p.err != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Render(fmt.Sprintf("Error loading portfolio: %v", p.err))
+	}
+	if p.valuation == nil {
+		return p.styles.Subtle.Render("Loading portfolio...")
+	}
+
+	v := p.valuation
+	pnlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#50fa7b")) // Green
+	if v.TotalPnL < 0 {
+		pnlStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")) // Red
+	}
+
+	summary := fmt.Sprintf("Cash: %s | Positions: %s | Equity: %s | P&L: %s",
+		p.styles.Highlight.Render(fmt.Sprintf("$%.2f", v.Cash)),
+		p.styles.Highlight.Render(fmt.Sprintf("$%.2f", v.MarketValue)),
+		p.styles.Highlight.Render(fmt.Sprintf("$%.2f", v.Equity)),
+		pnlStyle.Render(fmt.Sprintf("%s (realized %s)", formatPNL(v.TotalPnL), formatPNL(v.RealizedPnL))),
+	)
+
+	sections := []string{summary, ""}
+	if len(v.Holdings) == 0 {
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(p.styles.Subtle.GetForeground()).
+			Padding(1, 4).
+			Render("No open paper positions.\n\nWatchlisted traders' new trades are mirrored here on each scan."))
+	} else {
+		sections = append(sections, p.table.View())
+	}
+
+	if len(p.fills) > 0 {
+		lines := []string{p.styles.Header.Render(" RECENT FILLS "), ""}
+		for _, f := range p.fills {
+			trader := f.TraderID
+			if len(trader) > 12 {
+				trader = trader[:6] + "..." + trader[len(trader)-4:]
+			}
+			lines = append(lines, fmt.Sprintf("%s  %-4s %-3s %8.2f @ %.3f  %-14s %s",
+				f.Timestamp.Format("01/02 15:04"), f.Type, f.Side, f.Shares, f.Price, trader, f.MarketID))
+		}
+		sections = append(sections, "", strings.Join(lines, "\n"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (p *Portfolio) HelpText() string {
+	return "↑/↓: navigate | enter: open market | r: refresh"
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"polytracker/internal/db"
+	"polytracker/internal/paper"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortfolioView_Empty(t *testing.T) {
+	p := NewPortfolio(GetStyles(Dracula))
+	p.SetSize(120, 40)
+
+	assert.Contains(t, p.View(), "Loading portfolio")
+
+	p, _ = p.Update(portfolioLoadedMsg{valuation: &paper.Valuation{StartingCash: 10000, Cash: 10000, Equity: 10000}})
+	view := p.View()
+	assert.Contains(t, view, "$10000.00")
+	assert.Contains(t, view, "No open paper positions")
+}
+
+func TestPortfolioLoad(t *testing.T) {
+	database := setupTestDB(t)
+
+	require.NoError(t, database.ResetPaperPortfolio(1000))
+	require.NoError(t, database.SaveMarket(&db.Market{ID: "m1", Question: "Will it rain?"}))
+	now := time.Now()
+	recorded, err := database.RecordPaperFill(
+		&db.PaperFill{SourceTradeID: "t1", TraderID: "0xaaaa", MarketID: "m1", Side: "YES", Type: "BUY", Price: 0.4, Shares: 250, Notional: 100, Timestamp: now},
+		&db.PaperPosition{MarketID: "m1", Side: "YES", Shares: 250, CostBasis: 100, UpdatedAt: now},
+		900,
+	)
+	require.NoError(t, err)
+	require.True(t, recorded)
+	require.NoError(t, database.SaveMarketSnapshot(&db.MarketSnapshot{MarketID: "m1", YesPrice: 0.5, NoPrice: 0.5, Timestamp: now}))
+
+	p := NewPortfolio(GetStyles(Dracula))
+	p.SetSize(120, 40)
+	msg := p.LoadPortfolio(database)()
+	loaded, ok := msg.(portfolioLoadedMsg)
+	require.True(t, ok)
+	require.NoError(t, loaded.err)
+
+	p, _ = p.Update(loaded)
+	view := p.View()
+	assert.Contains(t, view, "Will it rain?")
+	assert.Contains(t, view, "$1025.00")
+	assert.Contains(t, view, "RECENT FILLS")
+
+	// Enter opens the highlighted holding's market.
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	open, ok := cmd().(OpenMarketMsg)
+	require.True(t, ok)
+	assert.Equal(t, "m1", open.MarketID)
+}
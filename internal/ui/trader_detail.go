@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	recentTradesLimit = 10
+	recentTradesLimit   = 10
+	similarTradersLimit = 5
 )
 
 type TraderDetailKeyMap struct {
@@ -20,8 +21,12 @@ type TraderDetailKeyMap struct {
 	Down    key.Binding
 	Back    key.Binding
 	Analyze key.Binding
+	Resume  key.Binding
 	Watch   key.Binding
 	Trades  key.Binding
+	Next    key.Binding
+	Prev    key.Binding
+	Market  key.Binding
 }
 
 var traderDetailKeys = TraderDetailKeyMap{
@@ -41,6 +46,10 @@ var traderDetailKeys = TraderDetailKeyMap{
 		key.WithKeys("a"),
 		key.WithHelp("a", "analyze"),
 	),
+	Resume: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "continue last analysis"),
+	),
 	Watch: key.NewBinding(
 		key.WithKeys("w"),
 		key.WithHelp("w", "watch"),
@@ -49,23 +58,45 @@ var traderDetailKeys = TraderDetailKeyMap{
 		key.WithKeys("t"),
 		key.WithHelp("t", "all trades"),
 	),
+	Next: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next trade"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "previous trade"),
+	),
+	Market: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "open market"),
+	),
 }
 
 type TraderDetail struct {
 	trader       *db.Trader
 	trades       []db.Trade
 	markets      map[string]*db.Market
+	profile      *db.TraderProfile
+	traderType   *db.TraderClassification
+	similar      []db.TraderSimilarity
+	categories   []db.CategoryStats
 	styles       Styles
 	width        int
 	height       int
 	scrollOffset int
 	showAllTrades bool
 	isOnWatchlist bool
+	// selectedTrade indexes the shown trade whose market 'm' opens.
+	selectedTrade int
 }
 
 type tradesLoadedMsg struct {
-	trades  []db.Trade
-	markets map[string]*db.Market
+	trades     []db.Trade
+	markets    map[string]*db.Market
+	profile    *db.TraderProfile
+	traderType *db.TraderClassification
+	similar    []db.TraderSimilarity
+	categories []db.CategoryStats
 }
 
 type watchlistStatusMsg struct {
@@ -76,6 +107,9 @@ type GoBackMsg struct{}
 
 type AnalyzeTraderMsg struct {
 	Trader *db.Trader
+	// Resume opens the trader's latest saved analysis and its follow-up
+	// conversation instead of writing a new one.
+	Resume bool
 }
 
 type ToggleWatchlistMsg struct {
@@ -121,9 +155,18 @@ func (td *TraderDetail) LoadTrades(database *db.DB) tea.Cmd {
 			}
 		}
 
+		profile, _ := database.GetTraderProfile(td.trader.Address)
+		traderType, _ := database.GetTraderClassification(td.trader.Address)
+		similar, _ := database.GetSimilarTraders(td.trader.Address, similarTradersLimit)
+		categories, _ := database.GetCategoryBreakdown(td.trader.Address)
+
 		return tradesLoadedMsg{
-			trades:  trades,
-			markets: markets,
+			trades:     trades,
+			markets:    markets,
+			profile:    profile,
+			traderType: traderType,
+			similar:    similar,
+			categories: categories,
 		}
 	}
 }
@@ -148,6 +191,10 @@ func (td *TraderDetail) Update(msg tea.Msg) (*TraderDetail, tea.Cmd) {
 	case tradesLoadedMsg:
 		td.trades = msg.trades
 		td.markets = msg.markets
+		td.profile = msg.profile
+		td.traderType = msg.traderType
+		td.similar = msg.similar
+		td.categories = msg.categories
 		return td, nil
 
 	case watchlistStatusMsg:
@@ -175,6 +222,12 @@ func (td *TraderDetail) Update(msg tea.Msg) (*TraderDetail, tea.Cmd) {
 			}
 			return td, nil
 
+		case key.Matches(msg, traderDetailKeys.Resume):
+			if td.trader != nil {
+				return td, func() tea.Msg { return AnalyzeTraderMsg{Trader: td.trader, Resume: true} }
+			}
+			return td, nil
+
 		case key.Matches(msg, traderDetailKeys.Watch):
 			if td.trader != nil {
 				return td, func() tea.Msg {
@@ -186,6 +239,28 @@ func (td *TraderDetail) Update(msg tea.Msg) (*TraderDetail, tea.Cmd) {
 		case key.Matches(msg, traderDetailKeys.Trades):
 			td.showAllTrades = !td.showAllTrades
 			td.scrollOffset = 0
+			if td.selectedTrade >= td.shownTrades() {
+				td.selectedTrade = 0
+			}
+			return td, nil
+
+		case key.Matches(msg, traderDetailKeys.Next):
+			if n := td.shownTrades(); n > 0 {
+				td.selectedTrade = (td.selectedTrade + 1) % n
+			}
+			return td, nil
+
+		case key.Matches(msg, traderDetailKeys.Prev):
+			if n := td.shownTrades(); n > 0 {
+				td.selectedTrade = (td.selectedTrade + n - 1) % n
+			}
+			return td, nil
+
+		case key.Matches(msg, traderDetailKeys.Market):
+			if td.selectedTrade < td.shownTrades() {
+				marketID := td.trades[td.selectedTrade].MarketID
+				return td, func() tea.Msg { return OpenMarketMsg{MarketID: marketID} }
+			}
 			return td, nil
 		}
 	}
@@ -206,6 +281,21 @@ func (td *TraderDetail) View() string {
 	// Stats section
 	sections = append(sections, td.renderStats())
 
+	// Behavioral profile section
+	if td.profile != nil && td.profile.Entries > 0 {
+		sections = append(sections, td.renderBehavior())
+	}
+
+	// Category breakdown section
+	if len(td.categories) > 0 {
+		sections = append(sections, td.renderCategories())
+	}
+
+	// Similar traders section
+	if len(td.similar) > 0 {
+		sections = append(sections, td.renderSimilar())
+	}
+
 	// Recent trades section
 	sections = append(sections, td.renderTrades())
 
@@ -261,11 +351,20 @@ func (td *TraderDetail) renderProfile() string {
 		Padding(1, 2).
 		Width(td.width - 6)
 
+	traderType := "unclassified"
+	if td.traderType != nil {
+		traderType = td.traderType.Label
+		if td.traderType.Label != db.TraderTypeUnknown {
+			traderType += fmt.Sprintf(" (%.0f%% confidence)", td.traderType.Confidence*100)
+		}
+	}
+
 	profileContent := lipgloss.JoinVertical(
 		lipgloss.Left,
 		td.styles.Highlight.Render("Address:  ")+shortAddress,
 		td.styles.Highlight.Render("Full:     ")+td.styles.Subtle.Render(address),
 		td.styles.Highlight.Render("Username: ")+username,
+		td.styles.Highlight.Render("Type:     ")+traderType,
 		td.styles.Highlight.Render("Scanned:  ")+t.LastScanned.Format("2006-01-02 15:04:05"),
 	)
 
@@ -328,6 +427,107 @@ func (td *TraderDetail) renderStats() string {
 	)
 }
 
+func (td *TraderDetail) renderBehavior() string {
+	p := td.profile
+
+	header := td.styles.Header.Render(" BEHAVIOR ")
+
+	behaviorBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(td.styles.Header.GetBackground()).
+		Padding(1, 2).
+		Width(td.width - 6)
+
+	behaviorContent := lipgloss.JoinVertical(
+		lipgloss.Left,
+		fmt.Sprintf("%-20s %s", "Median Hold:", td.styles.Highlight.Render(fmt.Sprintf("%.1fh", p.MedianHoldingHours))),
+		fmt.Sprintf("%-20s %s", "Late Entries (<24h):", td.styles.Highlight.Render(fmt.Sprintf("%.1f%%", p.LateEntryRatio*100))),
+		fmt.Sprintf("%-20s %s", "Avg Size / Mkt Vol:", td.styles.Highlight.Render(fmt.Sprintf("%.2f%%", p.AvgSizeShare*100))),
+		fmt.Sprintf("%-20s %s", "Scale-in Markets:", td.styles.Highlight.Render(fmt.Sprintf("%.1f%%", p.ScaleInRatio*100))),
+		fmt.Sprintf("%-20s %s", "Momentum Entries:", td.styles.Highlight.Render(fmt.Sprintf("%.1f%%", p.MomentumRatio*100))),
+		fmt.Sprintf("%-20s %s", "Contrarian Entries:", td.styles.Highlight.Render(fmt.Sprintf("%.1f%%", p.ContrarianRatio*100))),
+	)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		header,
+		"",
+		behaviorBox.Render(behaviorContent),
+	)
+}
+
+func (td *TraderDetail) renderCategories() string {
+	header := td.styles.Header.Render(" CATEGORIES ")
+
+	categoryBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(td.styles.Header.GetBackground()).
+		Padding(1, 2).
+		Width(td.width - 6)
+
+	lines := []string{
+		td.styles.Subtle.Render(fmt.Sprintf("%-18s %-12s %-7s %-8s %-10s %-7s", "Category", "P&L", "Win %", "ROI", "Volume", "Markets")),
+	}
+	for _, c := range td.categories {
+		pnlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#50fa7b")) // Green
+		if c.ProfitLoss < 0 {
+			pnlStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")) // Red
+		}
+		category := c.Category
+		if len(category) > 18 {
+			category = category[:15] + "..."
+		}
+		lines = append(lines, fmt.Sprintf("%-18s %s %-7s %-8s %-10s %-7d",
+			category,
+			pnlStyle.Render(fmt.Sprintf("%-12s", formatPNL(c.ProfitLoss))),
+			fmt.Sprintf("%.1f%%", c.WinRate*100),
+			fmt.Sprintf("%.1f%%", c.ROI*100),
+			formatVolume(c.Volume),
+			c.MarketCount,
+		))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		header,
+		"",
+		categoryBox.Render(strings.Join(lines, "\n")),
+	)
+}
+
+func (td *TraderDetail) renderSimilar() string {
+	header := td.styles.Header.Render(" SIMILAR TRADERS ")
+
+	similarBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(td.styles.Header.GetBackground()).
+		Padding(1, 2).
+		Width(td.width - 6)
+
+	lines := []string{
+		td.styles.Subtle.Render(fmt.Sprintf("%-44s %-7s %-8s %-8s %-6s", "Address", "Score", "Overlap", "Co-time", "Size")),
+	}
+	for _, s := range td.similar {
+		lines = append(lines, fmt.Sprintf("%-44s %s %-8s %-8s %-6s",
+			s.Other(td.trader.Address),
+			td.styles.Highlight.Render(fmt.Sprintf("%-7s", fmt.Sprintf("%.0f%%", s.Score*100))),
+			fmt.Sprintf("%.0f%%", s.MarketOverlap*100),
+			fmt.Sprintf("%.0f%%", s.CoTiming*100),
+			fmt.Sprintf("%.0f%%", s.Sizing*100),
+		))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		header,
+		"",
+		similarBox.Render(strings.Join(lines, "\n")),
+	)
+}
+
 func (td *TraderDetail) renderTrades() string {
 	title := " RECENT TRADES "
 	if td.showAllTrades {
@@ -355,18 +555,12 @@ func (td *TraderDetail) renderTrades() string {
 	var tradeLines []string
 
 	// Header row
-	headerLine := fmt.Sprintf("%-10s %-6s %-6s %-10s %-10s %-30s",
+	headerLine := fmt.Sprintf("  %-10s %-6s %-6s %-10s %-10s %-30s",
 		"Date", "Type", "Side", "Price", "Size", "Market")
 	tradeLines = append(tradeLines, td.styles.Subtle.Render(headerLine))
-	tradeLines = append(tradeLines, td.styles.Subtle.Render(strings.Repeat("-", 75)))
+	tradeLines = append(tradeLines, td.styles.Subtle.Render(strings.Repeat("-", 77)))
 
-	limit := recentTradesLimit
-	if td.showAllTrades {
-		limit = len(td.trades)
-	}
-	if limit > len(td.trades) {
-		limit = len(td.trades)
-	}
+	limit := td.shownTrades()
 
 	for i := 0; i < limit; i++ {
 		trade := td.trades[i]
@@ -386,7 +580,11 @@ func (td *TraderDetail) renderTrades() string {
 			}
 		}
 
-		tradeLine := fmt.Sprintf("%-10s %s %-6s %-10s %-10s %-30s",
+		cursor := "  "
+		if i == td.selectedTrade {
+			cursor = td.styles.Highlight.Render("> ")
+		}
+		tradeLine := cursor + fmt.Sprintf("%-10s %s %-6s %-10s %-10s %-30s",
 			trade.Timestamp.Format("01/02 15:04"),
 			typeStyle.Render(fmt.Sprintf("%-6s", trade.Type)),
 			trade.Side,
@@ -414,12 +612,20 @@ func (td *TraderDetail) renderTrades() string {
 	)
 }
 
+// shownTrades is how many trades the trade list shows.
+func (td *TraderDetail) shownTrades() int {
+	if td.showAllTrades || len(td.trades) < recentTradesLimit {
+		return len(td.trades)
+	}
+	return recentTradesLimit
+}
+
 func (td *TraderDetail) HelpText() string {
 	watchAction := "w: add to watchlist"
 	if td.isOnWatchlist {
 		watchAction = "w: remove from watchlist"
 	}
-	return fmt.Sprintf("esc: back | a: analyze | %s | t: toggle all trades | j/k: scroll", watchAction)
+	return fmt.Sprintf("esc: back | a: analyze | c: continue last analysis | %s | t: toggle all trades | tab/m: pick trade, open market | j/k: scroll", watchAction)
 }
 
 func (td *TraderDetail) GetTrader() *db.Trader {
@@ -121,6 +121,42 @@ func TestTraderDetailAnalyze(t *testing.T) {
 	assert.Equal(t, trader.Address, analyzeMsg.Trader.Address)
 }
 
+func TestTraderDetailOpenMarket(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234", Username: "test"}
+	td := NewTraderDetail(trader, DefaultStyles())
+	td.SetSize(100, 50)
+	td, _ = td.Update(tradesLoadedMsg{trades: []db.Trade{
+		{ID: "t1", MarketID: "market-one", Type: "buy", Side: "YES"},
+		{ID: "t2", MarketID: "market-two", Type: "sell", Side: "NO"},
+	}})
+
+	open := func() string {
+		_, cmd := td.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+		msg, ok := cmd().(OpenMarketMsg)
+		assert.True(t, ok)
+		return msg.MarketID
+	}
+	assert.Equal(t, "market-one", open())
+
+	td, _ = td.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, "market-two", open())
+	td, _ = td.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, "market-one", open(), "tab wraps around")
+	td, _ = td.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Equal(t, "market-two", open())
+}
+
+func TestTraderDetailResume(t *testing.T) {
+	trader := &db.Trader{Address: "0x1234", Username: "test"}
+	td := NewTraderDetail(trader, DefaultStyles())
+
+	_, cmd := td.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	analyzeMsg, ok := cmd().(AnalyzeTraderMsg)
+	assert.True(t, ok)
+	assert.True(t, analyzeMsg.Resume)
+	assert.Equal(t, trader.Address, analyzeMsg.Trader.Address)
+}
+
 func TestTraderDetailWatchlist(t *testing.T) {
 	trader := &db.Trader{
 		Address:  "0x1234",
@@ -172,6 +208,84 @@ func TestTraderDetailView(t *testing.T) {
 	assert.Contains(t, view, "testuser")
 }
 
+func TestTraderDetailView_Behavior(t *testing.T) {
+	trader := &db.Trader{
+		Address:  "0x1234",
+		Username: "test",
+	}
+
+	styles := DefaultStyles()
+	td := NewTraderDetail(trader, styles)
+	td.SetSize(100, 80)
+
+	// No profile loaded yet
+	assert.NotContains(t, td.View(), "BEHAVIOR")
+
+	td, _ = td.Update(tradesLoadedMsg{
+		profile: &db.TraderProfile{
+			TraderID:           "0x1234",
+			MedianHoldingHours: 18.5,
+			LateEntryRatio:     0.4,
+			Entries:            5,
+		},
+	})
+
+	view := td.View()
+	assert.Contains(t, view, "BEHAVIOR")
+	assert.Contains(t, view, "18.5h")
+	assert.Contains(t, view, "40.0%")
+}
+
+func TestTraderDetailView_Similar(t *testing.T) {
+	trader := &db.Trader{
+		Address:  "0xaaaa",
+		Username: "test",
+	}
+
+	styles := DefaultStyles()
+	td := NewTraderDetail(trader, styles)
+	td.SetSize(120, 80)
+
+	assert.NotContains(t, td.View(), "SIMILAR TRADERS")
+
+	td, _ = td.Update(tradesLoadedMsg{
+		similar: []db.TraderSimilarity{
+			{TraderA: "0xaaaa", TraderB: "0xbbbb", Score: 0.82, MarketOverlap: 0.9},
+		},
+	})
+
+	view := td.View()
+	assert.Contains(t, view, "SIMILAR TRADERS")
+	assert.Contains(t, view, "0xbbbb")
+	assert.Contains(t, view, "82%")
+}
+
+func TestTraderDetailView_Categories(t *testing.T) {
+	trader := &db.Trader{
+		Address:  "0xaaaa",
+		Username: "test",
+	}
+
+	styles := DefaultStyles()
+	td := NewTraderDetail(trader, styles)
+	td.SetSize(120, 80)
+
+	assert.NotContains(t, td.View(), "CATEGORIES")
+
+	td, _ = td.Update(tradesLoadedMsg{
+		categories: []db.CategoryStats{
+			{Category: "Sports", ProfitLoss: 250, WinRate: 0.75, ROI: 0.5, Volume: 1200, MarketCount: 4},
+			{Category: "Politics", ProfitLoss: -80, WinRate: 0.25, Volume: 900, MarketCount: 4},
+		},
+	})
+
+	view := td.View()
+	assert.Contains(t, view, "CATEGORIES")
+	assert.Contains(t, view, "Sports")
+	assert.Contains(t, view, "+$250.00")
+	assert.Contains(t, view, "-$80.00")
+}
+
 func TestTraderDetailHelpText(t *testing.T) {
 	trader := &db.Trader{
 		Address:  "0x1234",
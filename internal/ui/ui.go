@@ -7,6 +7,7 @@ import (
 	"polytracker/internal/claude"
 	"polytracker/internal/db"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -21,6 +22,9 @@ const (
 	stateSettings
 	stateTraderDetail
 	stateAnalysis
+	statePortfolio
+	stateComparison
+	stateMarket
 )
 
 type Model struct {
@@ -34,9 +38,28 @@ type Model struct {
 	traderDetail   *TraderDetail
 	watchlist      *Watchlist
 	analysis       *Analysis
+	portfolio      *Portfolio
+	comparison     *Comparison
+	market         *MarketDetail
 	db             *db.DB
 	claudeClient   *claude.Client
 	selectedTrader *db.Trader
+	unreadAlerts   int
+}
+
+type alertCountMsg struct {
+	count int
+}
+
+// loadAlertCount counts unread alerts for the footer. Errors leave the count unchanged.
+func loadAlertCount(database *db.DB) tea.Cmd {
+	return func() tea.Msg {
+		count, err := database.CountUnreadAlerts()
+		if err != nil {
+			return nil
+		}
+		return alertCountMsg{count: count}
+	}
 }
 
 func NewModel(themeName string) Model {
@@ -50,6 +73,7 @@ func NewModel(themeName string) Model {
 		styles:      styles,
 		leaderboard: NewLeaderboard(styles),
 		watchlist:   NewWatchlist(styles),
+		portfolio:   NewPortfolio(styles),
 	}
 }
 
@@ -68,7 +92,7 @@ func NewModelWithClaudeClient(themeName string, database *db.DB, claudeClient *c
 
 func (m Model) Init() tea.Cmd {
 	if m.db != nil && m.leaderboard != nil {
-		return m.leaderboard.LoadTraders(m.db)
+		return tea.Batch(m.leaderboard.LoadTraders(m.db), loadAlertCount(m.db))
 	}
 	return nil
 }
@@ -79,7 +103,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Handle global keys first
+		// Handle global keys first, unless they are being typed into a text input
+		if m.typing() && msg.String() != "ctrl+c" {
+			break
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -95,12 +122,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "3":
 			m.state = stateWatchlist
 			if m.db != nil && m.watchlist != nil {
-				return m, m.watchlist.LoadWatchlist(m.db)
+				return m, tea.Batch(m.watchlist.LoadWatchlist(m.db), loadAlertCount(m.db))
 			}
 			return m, nil
 		case "4":
 			m.state = stateSettings
 			return m, nil
+		case "5":
+			m.state = statePortfolio
+			if m.db != nil && m.portfolio != nil {
+				return m, m.portfolio.LoadPortfolio(m.db)
+			}
+			return m, nil
 		case "?":
 			// Toggle help? For now just stay.
 			return m, nil
@@ -122,6 +155,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.analysis != nil {
 			m.analysis.SetSize(m.width, contentHeight)
 		}
+		if m.portfolio != nil {
+			m.portfolio.SetSize(m.width, contentHeight)
+		}
+		if m.comparison != nil {
+			m.comparison.SetSize(m.width, contentHeight)
+		}
+		if m.market != nil {
+			m.market.SetSize(m.width, contentHeight)
+		}
 
 	case TraderSelectedMsg:
 		if msg.Trader != nil {
@@ -141,10 +183,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case GoBackMsg:
 		// Go back to previous state
+		if m.state == stateMarket {
+			// The market screen returns to the trader or portfolio it was opened from
+			if m.market != nil {
+				m.market.Cancel()
+			}
+			m.market = nil
+			m.state = m.previousState
+			return m, nil
+		}
 		if m.state == stateAnalysis {
 			// Go back to trader detail if we came from there
 			if m.previousState == stateTraderDetail && m.selectedTrader != nil {
 				m.state = stateTraderDetail
+				if m.analysis != nil {
+					m.analysis.Cancel()
+				}
 				m.analysis = nil
 				return m, nil
 			}
@@ -152,7 +206,59 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = stateLeaderboard
 		m.selectedTrader = nil
 		m.traderDetail = nil
+		if m.analysis != nil {
+			m.analysis.Cancel()
+		}
 		m.analysis = nil
+		if m.comparison != nil {
+			m.comparison.Cancel()
+		}
+		m.comparison = nil
+		return m, nil
+
+	case CompareTradersMsg:
+		if len(msg.Traders) > 0 {
+			m.previousState = m.state
+			m.state = stateComparison
+			m.comparison = NewComparison(msg.Traders, m.styles, m.claudeClient)
+			m.comparison.SetSize(m.width, m.height-6)
+			if m.leaderboard != nil {
+				m.leaderboard.ClearMarks()
+			}
+			return m, tea.Batch(m.comparison.Init(), m.comparison.Run(m.db))
+		}
+		return m, nil
+
+	case ComparisonCompleteMsg, ComparisonErrorMsg, ComparisonSavedMsg:
+		if m.comparison != nil {
+			m.comparison, cmd = m.comparison.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case OpenMarketMsg:
+		if msg.MarketID != "" {
+			m.previousState = m.state
+			m.state = stateMarket
+			m.market = NewMarketDetail(msg.MarketID, m.styles, m.claudeClient)
+			m.market.SetSize(m.width, m.height-6)
+			if m.db != nil {
+				return m, m.market.Load(m.db)
+			}
+		}
+		return m, nil
+
+	case marketLoadedMsg, MarketAnalysisCompleteMsg, MarketAnalysisErrorMsg:
+		if m.market != nil {
+			m.market, cmd = m.market.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case AnalyzeMarketMsg:
+		if m.market != nil && m.db != nil {
+			return m, m.market.Analyze(m.db)
+		}
 		return m, nil
 
 	case AnalyzeTraderMsg:
@@ -165,7 +271,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmds []tea.Cmd
 			cmds = append(cmds, m.analysis.Init())
 			if m.db != nil {
-				cmds = append(cmds, m.analysis.FetchData(m.db))
+				if msg.Resume {
+					cmds = append(cmds, m.analysis.LoadLatest(m.db))
+				} else {
+					cmds = append(cmds, m.analysis.FetchData(m.db))
+				}
 			}
 			return m, tea.Batch(cmds...)
 		}
@@ -178,20 +288,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
-	case AnalysisCompleteMsg:
+	case AnalysisChunkMsg:
 		if m.analysis != nil {
 			m.analysis, cmd = m.analysis.Update(msg)
 			cmds = append(cmds, cmd)
-			// Save analysis to database
+		}
+		return m, tea.Batch(cmds...)
+
+	case AnalysisCompleteMsg:
+		if m.analysis != nil && m.analysis.current(msg.stream) {
+			m.analysis, cmd = m.analysis.Update(msg)
+			cmds = append(cmds, cmd)
+			// Save analysis to database so follow-ups can be attached to it
 			if m.db != nil && m.selectedTrader != nil {
 				analysis := &db.Analysis{
 					TraderID: m.selectedTrader.Address,
 					Thesis:   msg.Thesis,
 				}
-				// Save without blocking UI
-				go func() {
-					_ = m.db.SaveAnalysis(analysis)
-				}()
+				if msg.Result != nil {
+					analysis = msg.Result.Analysis(m.selectedTrader.Address)
+				}
+				cmds = append(cmds, StoreAnalysis(m.db, analysis))
 			}
 		}
 		return m, tea.Batch(cmds...)
@@ -210,6 +327,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case AnalysisStoredMsg, AnalysisResumedMsg, ChatChunkMsg, ChatErrorMsg:
+		if m.analysis != nil {
+			m.analysis, cmd = m.analysis.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case ChatReplyMsg:
+		if m.analysis != nil && m.analysis.currentChat(msg.stream) {
+			m.analysis, cmd = m.analysis.Update(msg)
+			cmds = append(cmds, cmd)
+			if m.db != nil {
+				cmds = append(cmds, m.analysis.SaveLastTurn(m.db))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
 	case AnalysisRetryMsg:
 		if m.analysis != nil && m.db != nil {
 			return m, m.analysis.FetchData(m.db)
@@ -221,6 +355,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.analysis, cmd = m.analysis.Update(msg)
 			cmds = append(cmds, cmd)
 		}
+		if m.state == stateComparison && m.comparison != nil {
+			m.comparison, cmd = m.comparison.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.state == stateMarket && m.market != nil {
+			m.market, cmd = m.market.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 
 	case ToggleWatchlistMsg:
@@ -264,6 +406,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(cmds...)
 
+	case alertCountMsg:
+		m.unreadAlerts = msg.count
+		return m, nil
+
+	case portfolioLoadedMsg:
+		if m.portfolio != nil {
+			m.portfolio, cmd = m.portfolio.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
 	case WatchlistTraderSelectedMsg:
 		if msg.Trader != nil {
 			m.selectedTrader = msg.Trader
@@ -303,6 +456,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		oldSort := m.leaderboard.GetSortField()
 		oldOrder := m.leaderboard.GetSortOrder()
 		oldPage := m.leaderboard.GetCurrentPage()
+		oldFilter := m.leaderboard.GetTypeFilter()
+		oldCategory := m.leaderboard.GetCategory()
 
 		m.leaderboard, cmd = m.leaderboard.Update(msg)
 		cmds = append(cmds, cmd)
@@ -311,8 +466,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newSort := m.leaderboard.GetSortField()
 		newOrder := m.leaderboard.GetSortOrder()
 		newPage := m.leaderboard.GetCurrentPage()
+		newFilter := m.leaderboard.GetTypeFilter()
+		newCategory := m.leaderboard.GetCategory()
 
-		if m.db != nil && (oldSort != newSort || oldOrder != newOrder || oldPage != newPage) {
+		if m.db != nil && (oldSort != newSort || oldOrder != newOrder || oldPage != newPage || oldFilter != newFilter || oldCategory != newCategory) {
 			cmds = append(cmds, m.leaderboard.LoadTraders(m.db))
 		}
 	}
@@ -329,15 +486,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	// Pass messages to portfolio when in portfolio state
+	if m.state == statePortfolio && m.portfolio != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, portfolioKeys.Refresh) && m.db != nil {
+			cmds = append(cmds, m.portfolio.LoadPortfolio(m.db))
+		}
+		m.portfolio, cmd = m.portfolio.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	// Pass messages to analysis when in analysis state
 	if m.state == stateAnalysis && m.analysis != nil {
 		m.analysis, cmd = m.analysis.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
+	// Pass messages to comparison when in comparison state
+	if m.state == stateComparison && m.comparison != nil {
+		m.comparison, cmd = m.comparison.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	// Pass messages to the market screen when in market state
+	if m.state == stateMarket && m.market != nil {
+		m.market, cmd = m.market.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
+// typing reports whether the current view has a focused text input, in which
+// case keys belong to it rather than to the global shortcuts.
+func (m Model) typing() bool {
+	switch m.state {
+	case stateAnalysis:
+		return m.analysis != nil && m.analysis.IsAsking()
+	case stateWatchlist:
+		return m.watchlist != nil && m.watchlist.IsEditingNote()
+	}
+	return false
+}
+
 func (m Model) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
@@ -371,8 +561,8 @@ func (m Model) View() string {
 
 func (m Model) renderTabs() string {
 	var tabs []string
-	labels := []string{"1. Scan", "2. Leaderboard", "3. Watchlist", "4. Settings"}
-	states := []sessionState{stateScan, stateLeaderboard, stateWatchlist, stateSettings}
+	labels := []string{"1. Scan", "2. Leaderboard", "3. Watchlist", "4. Settings", "5. Portfolio"}
+	states := []sessionState{stateScan, stateLeaderboard, stateWatchlist, stateSettings, statePortfolio}
 
 	for i, label := range labels {
 		if m.state == states[i] {
@@ -402,6 +592,11 @@ func (m Model) renderContent() string {
 		content = "Watchlist View (Loading...)"
 	case stateSettings:
 		content = "Settings View (Work in Progress)"
+	case statePortfolio:
+		if m.portfolio != nil {
+			return m.styles.Content.Render(m.portfolio.View())
+		}
+		content = "Portfolio View (Loading...)"
 	case stateTraderDetail:
 		content = m.renderTraderDetail()
 	case stateAnalysis:
@@ -409,6 +604,16 @@ func (m Model) renderContent() string {
 			return m.styles.Content.Render(m.analysis.View())
 		}
 		content = "Analysis View (Loading...)"
+	case stateComparison:
+		if m.comparison != nil {
+			return m.styles.Content.Render(m.comparison.View())
+		}
+		content = "Comparison View (Loading...)"
+	case stateMarket:
+		if m.market != nil {
+			return m.styles.Content.Render(m.market.View())
+		}
+		content = "Market View (Loading...)"
 	}
 
 	return m.styles.Content.Render(content)
@@ -437,9 +642,9 @@ func (m Model) renderFooter() string {
 	switch m.state {
 	case stateLeaderboard:
 		if m.leaderboard != nil && m.db != nil {
-			help = m.leaderboard.HelpText() + " | q: quit | 1-4: tabs"
+			help = m.leaderboard.HelpText() + " | q: quit | 1-5: tabs"
 		} else {
-			help = "q: quit | 1-4: change tab | ?: help"
+			help = "q: quit | 1-5: change tab | ?: help"
 		}
 	case stateTraderDetail:
 		if m.traderDetail != nil {
@@ -449,9 +654,15 @@ func (m Model) renderFooter() string {
 		}
 	case stateWatchlist:
 		if m.watchlist != nil {
-			help = m.watchlist.HelpText() + " | q: quit | 1-4: tabs"
+			help = m.watchlist.HelpText() + " | q: quit | 1-5: tabs"
 		} else {
-			help = "q: quit | 1-4: change tab | ?: help"
+			help = "q: quit | 1-5: change tab | ?: help"
+		}
+	case statePortfolio:
+		if m.portfolio != nil {
+			help = m.portfolio.HelpText() + " | q: quit | 1-5: tabs"
+		} else {
+			help = "q: quit | 1-5: change tab | ?: help"
 		}
 	case stateAnalysis:
 		if m.analysis != nil {
@@ -459,8 +670,23 @@ func (m Model) renderFooter() string {
 		} else {
 			help = "esc: back | q: quit"
 		}
+	case stateComparison:
+		if m.comparison != nil {
+			help = m.comparison.HelpText() + " | q: quit"
+		} else {
+			help = "esc: back | q: quit"
+		}
+	case stateMarket:
+		if m.market != nil {
+			help = m.market.HelpText() + " | q: quit"
+		} else {
+			help = "esc: back | q: quit"
+		}
 	default:
-		help = "q: quit | 1-4: change tab | ?: help"
+		help = "q: quit | 1-5: change tab | ?: help"
+	}
+	if m.unreadAlerts > 0 {
+		help += " | " + m.styles.Highlight.Render(fmt.Sprintf("alerts: %d unread", m.unreadAlerts))
 	}
 	return m.styles.Footer.Width(m.width).Render(help)
 }
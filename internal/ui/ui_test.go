@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"polytracker/internal/db"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -20,6 +22,7 @@ func TestModelUpdate(t *testing.T) {
 			{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")}, stateLeaderboard},
 			{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}, stateWatchlist},
 			{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")}, stateSettings},
+			{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")}, statePortfolio},
 		}
 
 		for _, tc := range msgs {
@@ -68,3 +71,54 @@ func TestModelView(t *testing.T) {
 		}
 	}
 }
+
+func TestModelView_UnreadAlerts(t *testing.T) {
+	m := NewModel("dracula")
+	m.width = 120
+	m.height = 24
+
+	if strings.Contains(m.View(), "unread") {
+		t.Error("Footer should not mention alerts when there are none")
+	}
+
+	newModel, _ := m.Update(alertCountMsg{count: 3})
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "alerts: 3 unread") {
+		t.Error("Footer missing unread alert count")
+	}
+}
+
+func TestModelUpdate_TypingSkipsGlobalKeys(t *testing.T) {
+	m := NewModel("dracula")
+	m.state = stateAnalysis
+	m.analysis = NewAnalysis(&db.Trader{Address: "0x1234"}, m.styles, nil)
+	m.analysis.asking = true
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = newModel.(Model)
+	if m.state != stateAnalysis {
+		t.Errorf("Expected to stay in analysis, got %v", m.state)
+	}
+	if cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Error("Expected q to be typed, not quit")
+		}
+	}
+}
+
+func TestModelUpdate_MarketReturnsToPreviousView(t *testing.T) {
+	m := NewModel("dracula")
+	m.state = statePortfolio
+
+	newModel, _ := m.Update(OpenMarketMsg{MarketID: "m1"})
+	m = newModel.(Model)
+	if m.state != stateMarket || m.market == nil {
+		t.Fatalf("Expected the market screen, got %v", m.state)
+	}
+
+	newModel, _ = m.Update(GoBackMsg{})
+	m = newModel.(Model)
+	if m.state != statePortfolio || m.market != nil {
+		t.Errorf("Expected to return to the portfolio, got %v", m.state)
+	}
+}